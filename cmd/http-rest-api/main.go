@@ -15,16 +15,32 @@ import (
 //	@contact.email	camgo@wizeline.com
 //
 // @securityDefinitions.basic	BasicAuth
+//
+// @securityDefinitions.apikey	BearerAuth
+// @in							header
+// @name						Authorization
+// @description				Type "Bearer" followed by a space and the access token.
 func main() {
-	l := logger.NewZeroLog()
+	os.Exit(run())
+}
+
+// run serves the API until it is stopped and returns the exit code, so the deferred shutdown and closes run before exiting.
+func run() int {
 	cfg := config.NewConfig()
+	l, err := logger.New(cfg.Logger)
+	if err != nil {
+		logger.NewZeroLog().Log().Err(err).Msg("logger configuration failed")
+		return 2
+	}
+	defer l.Close()
 
 	api, err := app.NewApiHTTP(cfg, l)
 	if err != nil {
 		l.Log().Err(err).Msg("http rest api startup failed")
-		os.Exit(1)
+		return app.ExitCode(err)
 	}
 	defer api.Shutdown()
 
 	api.Start()
+	return 0
 }
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/config"
+	"github.com/wizeline/CA-Microservices-Go/internal/db/migration"
+	"github.com/wizeline/CA-Microservices-Go/internal/logger"
+	"github.com/wizeline/CA-Microservices-Go/pkg/app"
+)
+
+const usage = `Usage: migrate <command> [argument]
+
+Commands:
+  up [N]         apply every pending migration, or the next N ones
+  down [N]       revert the last applied migration, or the last N ones
+  goto VERSION   apply or revert the migrations until VERSION is the last one applied
+  status         print the applied and pending migrations
+  redo           revert the last applied migration and apply it again
+  force VERSION  record VERSION as the last applied migration without running any SQL
+
+The database and the migrations directory are configured the same way as the HTTP REST API.
+
+Exit codes:
+  1   unexpected failure
+  2   invalid usage
+  65  a migration file is invalid, modified or fails to apply
+  69  the database is unreachable
+  75  another instance holds the migrations lock
+`
+
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	os.Exit(run())
+}
+
+// run migrates the database and returns the exit code, so the deferred closes run before exiting.
+func run() int {
+	cfg := config.NewConfig()
+	l, err := logger.New(cfg.Logger)
+	if err != nil {
+		logger.NewZeroLog().Log().Err(err).Msg("logger configuration failed")
+		return 2
+	}
+	defer l.Close()
+
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+	}
+	flag.Parse()
+	if flag.NArg() == 0 || flag.NArg() > 2 {
+		flag.Usage()
+		return 2
+	}
+
+	dbConn, dialect, err := app.ConnectDB(cfg.Database)
+	if err != nil {
+		l.Log().Err(err).Msg("database connection failed")
+		return app.ExitCode(err)
+	}
+	defer dbConn.Close()
+
+	migrations, err := migration.Load(dialect, cfg.Database.MigrationsDir())
+	if err != nil {
+		l.Log().Err(err).Msg("loading migrations failed")
+		return app.ExitCode(err)
+	}
+
+	m := migration.NewMigrator(dbConn.DB(), dialect, migrations, cfg.Database.MigrationsLockTimeout(), l)
+	if err := execute(m, flag.Arg(0), flag.Arg(1), os.Stdout); err != nil {
+		l.Log().Err(err).Str("command", flag.Arg(0)).Msg("migrate command failed")
+		if errors.Is(err, errUsage) {
+			flag.Usage()
+			return 2
+		}
+		return app.ExitCode(err)
+	}
+	return 0
+}
+
+// execute runs the command and prints the resulting status of the migrations.
+func execute(m migration.Migrator, cmd, arg string, out io.Writer) error {
+	var err error
+	switch cmd {
+	case "up":
+		var n int
+		if n, err = parseCount(arg, 0); err == nil {
+			err = m.Up(n)
+		}
+	case "down":
+		var n int
+		if n, err = parseCount(arg, 1); err == nil {
+			err = m.Down(n)
+		}
+	case "goto":
+		var version uint64
+		if version, err = parseVersion(arg); err == nil {
+			err = m.Goto(version)
+		}
+	case "force":
+		var version uint64
+		if version, err = parseVersion(arg); err == nil {
+			err = m.Force(version)
+		}
+	case "redo":
+		err = m.Redo()
+	case "status":
+	default:
+		err = fmt.Errorf("unknown command %q: %w", cmd, errUsage)
+	}
+	if err != nil {
+		return err
+	}
+
+	states, err := m.Status()
+	if err != nil {
+		return err
+	}
+	return printStatus(out, states)
+}
+
+// parseCount parses the optional number of migrations, the default value is returned when it is empty.
+func parseCount(arg string, def int) (int, error) {
+	if arg == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid number of migrations %q: %w", arg, errUsage)
+	}
+	return n, nil
+}
+
+// parseVersion parses the required migration version.
+func parseVersion(arg string) (uint64, error) {
+	version, err := strconv.ParseUint(arg, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid version %q: %w", arg, errUsage)
+	}
+	return version, nil
+}
+
+// printStatus prints the table of the applied and pending migrations.
+func printStatus(out io.Writer, states []migration.State) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT\tEXECUTION TIME")
+	for _, s := range states {
+		status, appliedAt, elapsed := "pending", "-", "-"
+		if s.Applied {
+			status = "applied"
+			appliedAt = s.Record.AppliedAt.Format(time.DateTime)
+			elapsed = s.Record.ExecutionTime.String()
+		}
+		if s.Missing {
+			status = "missing"
+		}
+		fmt.Fprintf(w, "%03d\t%s\t%s\t%s\t%s\n", s.Version, s.Name, status, appliedAt, elapsed)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/config"
+	"github.com/wizeline/CA-Microservices-Go/internal/db/seed"
+	"github.com/wizeline/CA-Microservices-Go/internal/logger"
+	"github.com/wizeline/CA-Microservices-Go/internal/service"
+	"github.com/wizeline/CA-Microservices-Go/pkg/app"
+)
+
+const usage = `Usage: seed [ENV]
+
+Loads the seed set of the environment, "development" by default, into the database.
+The users already stored are kept, so it can be run repeatedly. The migrations must have been applied.
+
+The database and the seeds directory are configured the same way as the HTTP REST API.
+`
+
+func main() {
+	os.Exit(run())
+}
+
+// run seeds the database and returns the exit code, so the deferred closes run before exiting.
+func run() int {
+	cfg := config.NewConfig()
+	l, err := logger.New(cfg.Logger)
+	if err != nil {
+		logger.NewZeroLog().Log().Err(err).Msg("logger configuration failed")
+		return 2
+	}
+	defer l.Close()
+
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+	}
+	flag.Parse()
+	if flag.NArg() > 1 {
+		flag.Usage()
+		return 2
+	}
+	env := cfg.Database.SeedsEnv()
+	if flag.NArg() == 1 {
+		env = flag.Arg(0)
+	}
+
+	fixtures, err := seed.Load(env, cfg.Database.SeedsDir())
+	if err != nil {
+		l.Log().Err(err).Msg("loading seeds failed")
+		return 1
+	}
+
+	dbConn, _, err := app.ConnectDB(cfg.Database)
+	if err != nil {
+		l.Log().Err(err).Msg("database connection failed")
+		return app.ExitCode(err)
+	}
+	defer dbConn.Close()
+
+	userRepo, err := app.NewUserRepo(cfg.Database, dbConn)
+	if err != nil {
+		l.Log().Err(err).Msg("user repository failed")
+		return app.ExitCode(err)
+	}
+	roleRepo, err := app.NewRoleRepo(cfg.Database, dbConn)
+	if err != nil {
+		l.Log().Err(err).Msg("role repository failed")
+		return app.ExitCode(err)
+	}
+	userSvc := service.NewUserService(userRepo, roleRepo, cfg.Pagination.CursorSecret())
+
+	res, err := seed.NewSeeder(userSvc, l).Apply(context.Background(), fixtures)
+	if err != nil {
+		l.Log().Err(err).Str("env", env).Msg("seed failed")
+		return app.ExitCode(err)
+	}
+	fmt.Printf("seed set %s: %d users created, %d already present\n", env, res.Created, res.Existing)
+	return 0
+}
@@ -0,0 +1,82 @@
+package config
+
+import "time"
+
+// Auth holds the configurations of the supported authentication mechanisms.
+type Auth struct {
+	JWT          JWT
+	RefreshToken RefreshToken
+	Admin        Admin
+}
+
+// JWT holds the configuration values used to sign and verify the JSON Web Tokens.
+type JWT struct {
+	algorithm      string
+	secret         string
+	privateKeyFile string
+	publicKeyFile  string
+	issuer         string
+	accessTokenTTL time.Duration
+}
+
+// Algorithm returns the signing algorithm of the tokens. e.g. "HS256", "RS256"
+func (j JWT) Algorithm() string {
+	return j.algorithm
+}
+
+// Secret returns the shared secret used by the HMAC based algorithms, it has no default and must be configured.
+func (j JWT) Secret() string {
+	return j.secret
+}
+
+// PrivateKeyFile returns the path of the PEM encoded private key used by the RSA based algorithms.
+func (j JWT) PrivateKeyFile() string {
+	return j.privateKeyFile
+}
+
+// PublicKeyFile returns the path of the PEM encoded public key used by the RSA based algorithms.
+func (j JWT) PublicKeyFile() string {
+	return j.publicKeyFile
+}
+
+// Issuer returns the value set as the "iss" claim of the tokens.
+func (j JWT) Issuer() string {
+	return j.issuer
+}
+
+// AccessTokenTTL returns the lifetime of the access tokens.
+func (j JWT) AccessTokenTTL() time.Duration {
+	return j.accessTokenTTL
+}
+
+// RefreshToken holds the configuration values of the refresh tokens.
+type RefreshToken struct {
+	ttl time.Duration
+}
+
+// TTL returns the lifetime of the refresh tokens.
+func (rt RefreshToken) TTL() time.Duration {
+	return rt.ttl
+}
+
+// Admin holds the account of the initial administrator, created on startup when the users can't be seeded, e.g. with the memory driver.
+type Admin struct {
+	username string
+	email    string
+	passwd   string
+}
+
+// Username returns the username of the initial administrator, the bootstrap is disabled when it is empty.
+func (a Admin) Username() string {
+	return a.username
+}
+
+// Email returns the email of the initial administrator.
+func (a Admin) Email() string {
+	return a.email
+}
+
+// Passwd returns the password the initial administrator is created with, it is ignored once the account exists.
+func (a Admin) Passwd() string {
+	return a.passwd
+}
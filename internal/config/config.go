@@ -13,7 +13,12 @@ const defaultAppName = "camgo"
 type Config struct {
 	Application Application
 	HTTPServer  HTTPServer
+	HTTPAdmin   HTTPAdmin
 	Database    Database
+	Auth        Auth
+	Pagination  Pagination
+	Tracing     Tracing
+	Logger      Logger
 }
 
 func setDefaultConfig() {
@@ -24,13 +29,63 @@ func setDefaultConfig() {
 	viper.SetDefault("http.server.host", "localhost")
 	viper.SetDefault("http.server.port", 8080)
 	viper.SetDefault("http.server.shutdown.timeout", time.Second*15)
+	viper.SetDefault("http.server.shutdown.drain_delay", time.Second*5)
+	viper.SetDefault("http.server.request.timeout", time.Second*10)
+	viper.SetDefault("http.server.readiness.timeout", time.Second*2)
+	viper.SetDefault("http.server.problem_details", false)
+	viper.SetDefault("http.admin.host", "localhost")
+	viper.SetDefault("http.admin.port", 9090)
 	// Database configurations
 	viper.SetDefault("database.driver", "postgres")
+	viper.SetDefault("database.migrations.dir", "")
+	viper.SetDefault("database.migrations.lock_timeout", time.Minute)
+	viper.SetDefault("database.seeds.env", "development")
+	viper.SetDefault("database.seeds.dir", "")
 	viper.SetDefault("database.postgres.host", "localhost")
 	viper.SetDefault("database.postgres.port", 5432)
 	viper.SetDefault("database.postgres.user", defaultAppName+"user")
 	viper.SetDefault("database.postgres.passwd", defaultAppName+"p4s5W0rD")
 	viper.SetDefault("database.postgres.dbname", defaultAppName)
+	viper.SetDefault("database.mysql.host", "localhost")
+	viper.SetDefault("database.mysql.port", 3306)
+	viper.SetDefault("database.mysql.user", defaultAppName+"user")
+	viper.SetDefault("database.mysql.passwd", defaultAppName+"p4s5W0rD")
+	viper.SetDefault("database.mysql.dbname", defaultAppName)
+	viper.SetDefault("database.sqlite.path", defaultAppName+".db")
+	// Authentication configurations
+	viper.SetDefault("auth.jwt.algorithm", "HS256")
+	viper.SetDefault("auth.jwt.secret", "")
+	viper.SetDefault("auth.jwt.private_key_file", "")
+	viper.SetDefault("auth.jwt.public_key_file", "")
+	viper.SetDefault("auth.jwt.issuer", defaultAppName)
+	viper.SetDefault("auth.jwt.access_token.ttl", time.Minute*15)
+	viper.SetDefault("auth.refresh_token.ttl", time.Hour*24*7)
+	viper.SetDefault("auth.admin.username", "")
+	viper.SetDefault("auth.admin.email", "")
+	viper.SetDefault("auth.admin.passwd", "")
+	// Pagination configurations
+	viper.SetDefault("pagination.cursor_secret", "")
+	// Tracing configurations
+	viper.SetDefault("tracing.exporter", "none")
+	viper.SetDefault("tracing.sample_ratio", 1.0)
+	viper.SetDefault("tracing.otlp.endpoint", "localhost:4318")
+	viper.SetDefault("tracing.otlp.insecure", true)
+	viper.SetDefault("tracing.file.path", defaultAppName+"-traces.json")
+	// Logger configurations
+	viper.SetDefault("log.level", "info")
+	viper.SetDefault("log.format", "console")
+	viper.SetDefault("log.time_format", "")
+	viper.SetDefault("log.caller", false)
+	viper.SetDefault("log.outputs", "stderr")
+	viper.SetDefault("log.redact", true)
+	viper.SetDefault("log.sampling.burst", 100)
+	viper.SetDefault("log.sampling.period", time.Second)
+	viper.SetDefault("log.sampling.every", 0)
+	viper.SetDefault("log.file.path", defaultAppName+".log")
+	viper.SetDefault("log.file.max_size", 100)
+	viper.SetDefault("log.file.max_age", time.Hour*24*28)
+	viper.SetDefault("log.file.max_backups", 5)
+	viper.SetDefault("log.file.compress", false)
 }
 
 // NewConfig creates a new Config instance
@@ -46,12 +101,24 @@ func NewConfig() Config {
 			version: viper.GetString("application.version"),
 		},
 		HTTPServer: HTTPServer{
-			host:            viper.GetString("http.server.host"),
-			port:            viper.GetInt("http.server.port"),
-			shutdownTimeout: viper.GetDuration("http.server.shutdown.timeout"),
+			host:             viper.GetString("http.server.host"),
+			port:             viper.GetInt("http.server.port"),
+			shutdownTimeout:  viper.GetDuration("http.server.shutdown.timeout"),
+			drainDelay:       viper.GetDuration("http.server.shutdown.drain_delay"),
+			requestTimeout:   viper.GetDuration("http.server.request.timeout"),
+			readinessTimeout: viper.GetDuration("http.server.readiness.timeout"),
+			problemDetails:   viper.GetBool("http.server.problem_details"),
+		},
+		HTTPAdmin: HTTPAdmin{
+			host: viper.GetString("http.admin.host"),
+			port: viper.GetInt("http.admin.port"),
 		},
 		Database: Database{
-			driver: viper.GetString("database.driver"),
+			driver:                viper.GetString("database.driver"),
+			migrationsDir:         viper.GetString("database.migrations.dir"),
+			migrationsLockTimeout: viper.GetDuration("database.migrations.lock_timeout"),
+			seedsEnv:              viper.GetString("database.seeds.env"),
+			seedsDir:              viper.GetString("database.seeds.dir"),
 			Postgres: PostgreSQL{
 				host:   viper.GetString("database.postgres.host"),
 				port:   viper.GetInt("database.postgres.port"),
@@ -59,6 +126,60 @@ func NewConfig() Config {
 				passwd: viper.GetString("database.postgres.passwd"),
 				dbname: viper.GetString("database.postgres.dbname"),
 			},
+			MySQL: MySQL{
+				host:   viper.GetString("database.mysql.host"),
+				port:   viper.GetInt("database.mysql.port"),
+				user:   viper.GetString("database.mysql.user"),
+				passwd: viper.GetString("database.mysql.passwd"),
+				dbname: viper.GetString("database.mysql.dbname"),
+			},
+			SQLite: SQLite{
+				path: viper.GetString("database.sqlite.path"),
+			},
+		},
+		Auth: Auth{
+			JWT: JWT{
+				algorithm:      viper.GetString("auth.jwt.algorithm"),
+				secret:         viper.GetString("auth.jwt.secret"),
+				privateKeyFile: viper.GetString("auth.jwt.private_key_file"),
+				publicKeyFile:  viper.GetString("auth.jwt.public_key_file"),
+				issuer:         viper.GetString("auth.jwt.issuer"),
+				accessTokenTTL: viper.GetDuration("auth.jwt.access_token.ttl"),
+			},
+			RefreshToken: RefreshToken{
+				ttl: viper.GetDuration("auth.refresh_token.ttl"),
+			},
+			Admin: Admin{
+				username: viper.GetString("auth.admin.username"),
+				email:    viper.GetString("auth.admin.email"),
+				passwd:   viper.GetString("auth.admin.passwd"),
+			},
+		},
+		Pagination: Pagination{
+			cursorSecret: viper.GetString("pagination.cursor_secret"),
+		},
+		Tracing: Tracing{
+			exporter:     viper.GetString("tracing.exporter"),
+			sampleRatio:  viper.GetFloat64("tracing.sample_ratio"),
+			otlpEndpoint: viper.GetString("tracing.otlp.endpoint"),
+			otlpInsecure: viper.GetBool("tracing.otlp.insecure"),
+			filePath:     viper.GetString("tracing.file.path"),
+		},
+		Logger: Logger{
+			level:          viper.GetString("log.level"),
+			format:         viper.GetString("log.format"),
+			timeFormat:     viper.GetString("log.time_format"),
+			caller:         viper.GetBool("log.caller"),
+			outputs:        viper.GetString("log.outputs"),
+			redact:         viper.GetBool("log.redact"),
+			samplingBurst:  viper.GetInt("log.sampling.burst"),
+			samplingPeriod: viper.GetDuration("log.sampling.period"),
+			samplingEvery:  viper.GetInt("log.sampling.every"),
+			filePath:       viper.GetString("log.file.path"),
+			fileMaxSize:    viper.GetInt("log.file.max_size"),
+			fileMaxAge:     viper.GetDuration("log.file.max_age"),
+			fileMaxBackups: viper.GetInt("log.file.max_backups"),
+			fileCompress:   viper.GetBool("log.file.compress"),
 		},
 	}
 }
@@ -22,12 +22,21 @@ func TestNewConfig(t *testing.T) {
 					version: "v0.0.0",
 				},
 				HTTPServer: HTTPServer{
-					host:            "localhost",
-					port:            8080,
-					shutdownTimeout: 15000000000,
+					host:             "localhost",
+					port:             8080,
+					shutdownTimeout:  15000000000,
+					drainDelay:       5000000000,
+					requestTimeout:   10000000000,
+					readinessTimeout: 2000000000,
+				},
+				HTTPAdmin: HTTPAdmin{
+					host: "localhost",
+					port: 9090,
 				},
 				Database: Database{
-					driver: "postgres",
+					driver:                "postgres",
+					migrationsLockTimeout: 60000000000,
+					seedsEnv:              "development",
 					Postgres: PostgreSQL{
 						host:   "localhost",
 						port:   5432,
@@ -35,6 +44,49 @@ func TestNewConfig(t *testing.T) {
 						passwd: defaultAppName + "p4s5W0rD",
 						dbname: defaultAppName,
 					},
+					MySQL: MySQL{
+						host:   "localhost",
+						port:   3306,
+						user:   defaultAppName + "user",
+						passwd: defaultAppName + "p4s5W0rD",
+						dbname: defaultAppName,
+					},
+					SQLite: SQLite{
+						path: defaultAppName + ".db",
+					},
+				},
+				Auth: Auth{
+					JWT: JWT{
+						algorithm:      "HS256",
+						secret:         "",
+						issuer:         defaultAppName,
+						accessTokenTTL: 900000000000,
+					},
+					RefreshToken: RefreshToken{
+						ttl: 604800000000000,
+					},
+				},
+				Pagination: Pagination{
+					cursorSecret: "",
+				},
+				Tracing: Tracing{
+					exporter:     "none",
+					sampleRatio:  1,
+					otlpEndpoint: "localhost:4318",
+					otlpInsecure: true,
+					filePath:     defaultAppName + "-traces.json",
+				},
+				Logger: Logger{
+					level:          "info",
+					format:         "console",
+					outputs:        "stderr",
+					redact:         true,
+					samplingBurst:  100,
+					samplingPeriod: 1000000000,
+					filePath:       defaultAppName + ".log",
+					fileMaxSize:    100,
+					fileMaxAge:     2419200000000000,
+					fileMaxBackups: 5,
 				},
 			},
 		},
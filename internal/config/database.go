@@ -1,16 +1,47 @@
 package config
 
+import "time"
+
 // Database holds the configurations of the supported databases
 type Database struct {
-	driver   string
-	Postgres PostgreSQL
+	driver                string
+	migrationsDir         string
+	migrationsLockTimeout time.Duration
+	seedsEnv              string
+	seedsDir              string
+	Postgres              PostgreSQL
+	MySQL                 MySQL
+	SQLite                SQLite
 }
 
-// Driver returns the configured database driver.
+// Driver returns the configured database driver, "postgres", "mysql", "sqlite" or "memory".
 func (db Database) Driver() string {
 	return db.driver
 }
 
+// MigrationsDir returns the directory the SQL migration files are read from.
+// When empty, the migration files embedded into the binary are used.
+func (db Database) MigrationsDir() string {
+	return db.migrationsDir
+}
+
+// MigrationsLockTimeout returns how long an instance waits for the migrations lock held by another one.
+// A zero value waits without limit.
+func (db Database) MigrationsLockTimeout() time.Duration {
+	return db.migrationsLockTimeout
+}
+
+// SeedsEnv returns the environment whose seed set is applied. e.g. "development"
+func (db Database) SeedsEnv() string {
+	return db.seedsEnv
+}
+
+// SeedsDir returns the directory the seed sets are read from, one subdirectory per environment.
+// When empty, the fixtures embedded into the binary are used.
+func (db Database) SeedsDir() string {
+	return db.seedsDir
+}
+
 // PostgreSQL holds the configuration values of the postgresql database instances.
 type PostgreSQL struct {
 	host   string
@@ -44,3 +75,47 @@ func (pg PostgreSQL) Passwd() string {
 func (pg PostgreSQL) DBName() string {
 	return pg.dbname
 }
+
+// MySQL holds the configuration values of the mysql or mariadb database instances.
+type MySQL struct {
+	host   string
+	port   int
+	user   string
+	passwd string
+	dbname string
+}
+
+// Host returns the host value set for the mysql instance.
+func (my MySQL) Host() string {
+	return my.host
+}
+
+// Port returns the port value set for the mysql instance.
+func (my MySQL) Port() int {
+	return my.port
+}
+
+// User returns the username value set for the mysql instance.
+func (my MySQL) User() string {
+	return my.user
+}
+
+// Passwd returns the password value set for the mysql instance.
+func (my MySQL) Passwd() string {
+	return my.passwd
+}
+
+// DBName returns the database name value set for the mysql instance.
+func (my MySQL) DBName() string {
+	return my.dbname
+}
+
+// SQLite holds the configuration values of the sqlite database.
+type SQLite struct {
+	path string
+}
+
+// Path returns the path of the sqlite database file, it is created when missing.
+func (lite SQLite) Path() string {
+	return lite.path
+}
@@ -7,9 +7,13 @@ import (
 
 // HTTPServer holds the config properties for the http server instance
 type HTTPServer struct {
-	host            string
-	port            int
-	shutdownTimeout time.Duration
+	host             string
+	port             int
+	shutdownTimeout  time.Duration
+	drainDelay       time.Duration
+	requestTimeout   time.Duration
+	readinessTimeout time.Duration
+	problemDetails   bool
 }
 
 // Address returns the TCP address for the server to listen on, in the form of "host:port"
@@ -21,3 +25,36 @@ func (h HTTPServer) Address() string {
 func (h HTTPServer) ShutdownTimeout() time.Duration {
 	return h.shutdownTimeout
 }
+
+// DrainDelay returns how long the server keeps serving once it reports itself not ready on shutdown,
+// so the load balancers stop routing requests to it before the connections are closed
+func (h HTTPServer) DrainDelay() time.Duration {
+	return h.drainDelay
+}
+
+// RequestTimeout returns the deadline given to every request, a zero or negative value disables it
+func (h HTTPServer) RequestTimeout() time.Duration {
+	return h.requestTimeout
+}
+
+// ReadinessTimeout returns the deadline given to every readiness check, a zero or negative value disables it
+func (h HTTPServer) ReadinessTimeout() time.Duration {
+	return h.readinessTimeout
+}
+
+// ProblemDetails reports whether the error responses are written in the RFC 7807 "application/problem+json" format
+func (h HTTPServer) ProblemDetails() bool {
+	return h.problemDetails
+}
+
+// HTTPAdmin holds the config properties for the http server instance exposing the operational endpoints, e.g. the metrics.
+// It listens on its own port, so they are not reachable through the public one.
+type HTTPAdmin struct {
+	host string
+	port int
+}
+
+// Address returns the TCP address for the admin server to listen on, in the form of "host:port"
+func (h HTTPAdmin) Address() string {
+	return fmt.Sprintf("%v:%d", h.host, h.port)
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"strings"
+	"time"
+)
+
+// Logger holds the configuration values of the application logger.
+type Logger struct {
+	level          string
+	format         string
+	timeFormat     string
+	caller         bool
+	outputs        string
+	redact         bool
+	samplingBurst  int
+	samplingPeriod time.Duration
+	samplingEvery  int
+	filePath       string
+	fileMaxSize    int
+	fileMaxAge     time.Duration
+	fileMaxBackups int
+	fileCompress   bool
+}
+
+// Level returns the lowest level of the lines written. e.g. "info"
+func (l Logger) Level() string {
+	return l.level
+}
+
+// Format returns the format of the lines: "console" for humans or "json" for the log collectors.
+func (l Logger) Format() string {
+	return l.format
+}
+
+// TimeFormat returns the Go layout of the line timestamps, the format default is used when empty.
+func (l Logger) TimeFormat() string {
+	return l.timeFormat
+}
+
+// Caller reports whether the lines carry the file and line of the code writing them.
+func (l Logger) Caller() bool {
+	return l.caller
+}
+
+// Outputs returns where the lines are written: "stderr", "stdout" or "file".
+// They are configured as a comma separated list. e.g. "stderr,file"
+func (l Logger) Outputs() []string {
+	outputs := make([]string, 0)
+	for _, output := range strings.Split(l.outputs, ",") {
+		if output = strings.TrimSpace(output); output != "" {
+			outputs = append(outputs, output)
+		}
+	}
+	return outputs
+}
+
+// Redact reports whether the emails, the passwords and the tokens are masked before the lines are written.
+func (l Logger) Redact() bool {
+	return l.redact
+}
+
+// SamplingBurst returns how many debug and info lines are written per sampling period before the sampling starts.
+func (l Logger) SamplingBurst() int {
+	return l.samplingBurst
+}
+
+// SamplingPeriod returns the period the sampling burst is counted over.
+func (l Logger) SamplingPeriod() time.Duration {
+	return l.samplingPeriod
+}
+
+// SamplingEvery returns the sampling rate past the burst, one debug or info line out of every N is written.
+// A value lower than 2 disables the sampling, the warnings and the errors are never sampled.
+func (l Logger) SamplingEvery() int {
+	return l.samplingEvery
+}
+
+// FilePath returns the file the lines are written to by the "file" output.
+func (l Logger) FilePath() string {
+	return l.filePath
+}
+
+// FileMaxSize returns the size in megabytes the file reaches before it is rotated.
+func (l Logger) FileMaxSize() int {
+	return l.fileMaxSize
+}
+
+// FileMaxAge returns how long the rotated files are kept, a zero value keeps them regardless of their age.
+func (l Logger) FileMaxAge() time.Duration {
+	return l.fileMaxAge
+}
+
+// FileMaxBackups returns how many rotated files are kept, a zero value keeps them all.
+func (l Logger) FileMaxBackups() int {
+	return l.fileMaxBackups
+}
+
+// FileCompress reports whether the rotated files are compressed with gzip.
+func (l Logger) FileCompress() bool {
+	return l.fileCompress
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger_Outputs(t *testing.T) {
+	tests := []struct {
+		name    string
+		outputs string
+		exp     []string
+	}{
+		{name: "Empty", outputs: "", exp: []string{}},
+		{name: "Single", outputs: "stderr", exp: []string{"stderr"}},
+		{name: "Multiple", outputs: "stderr, file", exp: []string{"stderr", "file"}},
+		{name: "Blank items", outputs: " stdout,, ,file,", exp: []string{"stdout", "file"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.exp, Logger{outputs: tt.outputs}.Outputs())
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package config
+
+// Pagination holds the configuration values of the paginated listings.
+type Pagination struct {
+	cursorSecret string
+}
+
+// CursorSecret returns the key used to sign the opaque pagination cursors, it has no default value.
+func (p Pagination) CursorSecret() string {
+	return p.cursorSecret
+}
@@ -0,0 +1,36 @@
+package config
+
+// Tracing holds the configuration values of the distributed tracing.
+type Tracing struct {
+	exporter     string
+	sampleRatio  float64
+	otlpEndpoint string
+	otlpInsecure bool
+	filePath     string
+}
+
+// Exporter returns the destination of the spans: "none", "otlp", "stdout" or "file".
+func (t Tracing) Exporter() string {
+	return t.exporter
+}
+
+// SampleRatio returns the fraction of the traces started by the service that are recorded, from 0 to 1.
+// The traces started by a caller follow its sampling decision.
+func (t Tracing) SampleRatio() float64 {
+	return t.sampleRatio
+}
+
+// OTLPEndpoint returns the "host:port" address of the OTLP/HTTP collector receiving the spans.
+func (t Tracing) OTLPEndpoint() string {
+	return t.otlpEndpoint
+}
+
+// OTLPInsecure reports whether the spans are sent to the collector over plain HTTP instead of HTTPS.
+func (t Tracing) OTLPInsecure() bool {
+	return t.otlpInsecure
+}
+
+// FilePath returns the file the spans are appended to by the "file" exporter.
+func (t Tracing) FilePath() string {
+	return t.filePath
+}
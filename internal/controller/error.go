@@ -1,11 +1,13 @@
 package controller
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
-	"reflect"
 
+	"github.com/wizeline/CA-Microservices-Go/internal/logger"
+	"github.com/wizeline/CA-Microservices-Go/internal/middleware"
 	"github.com/wizeline/CA-Microservices-Go/internal/repository"
 	"github.com/wizeline/CA-Microservices-Go/internal/service"
 
@@ -14,13 +16,42 @@ import (
 
 const (
 	repoErrStatus        errStatus = "RepositoryError"
+	repoNotFoundStatus   errStatus = "RepositoryNotFoundError"
+	repoConflictStatus   errStatus = "RepositoryConflictError"
+	repoConnErrStatus    errStatus = "RepositoryConnectionError"
+	repoFieldErrStatus   errStatus = "RepositoryInvalidFieldError"
 	svcErrStatus         errStatus = "ServiceError"
+	svcUnauthErrStatus   errStatus = "ServiceUnauthorizedError"
+	svcInvalidErrStatus  errStatus = "ServiceValidationError"
 	ctrlPayloadErrStatus errStatus = "ControllerPayloadError"
 	ctrlParamErrStatus   errStatus = "ControllerParameterError"
+	authnErrStatus       errStatus = "AuthenticationError"
+	authzErrStatus       errStatus = "AuthorizationError"
+	timeoutErrStatus     errStatus = "RequestTimeoutError"
+	internalErrStatus    errStatus = "InternalError"
 )
 
 var _ fmt.Stringer = errStatus("")
 
+// problemTitles holds the problem details titles of the known error statuses.
+// The problems of an unknown status are typed as "about:blank" and titled after the HTTP status code.
+var problemTitles = map[errStatus]string{
+	repoErrStatus:        "Repository error",
+	repoNotFoundStatus:   "Resource not found",
+	repoConflictStatus:   "Resource conflict",
+	repoConnErrStatus:    "Repository unavailable",
+	repoFieldErrStatus:   "Invalid field",
+	svcErrStatus:         "Service error",
+	svcUnauthErrStatus:   "Unauthorized",
+	svcInvalidErrStatus:  "Invalid input",
+	ctrlPayloadErrStatus: "Invalid payload",
+	ctrlParamErrStatus:   "Invalid parameter",
+	authnErrStatus:       "Authentication failed",
+	authzErrStatus:       "Permission denied",
+	timeoutErrStatus:     "Request timeout",
+	internalErrStatus:    "Internal server error",
+}
+
 // errHTTP represents the default http error responses.
 type errHTTP struct {
 	Code    int       `json:"code"`
@@ -56,23 +87,67 @@ func (e ParameterErr) Error() string {
 func newErrHTTP(err error) errHTTP {
 	var (
 		repoErr        *repository.Err
+		repoNotFound   *repository.NotFoundErr
+		repoConflict   *repository.ConflictErr
+		repoConnErr    *repository.ConnectionErr
+		repoFieldErr   *repository.InvalidFieldErr
 		svcErr         *service.Err
+		svcUnauthErr   *service.UnauthorizedErr
+		svcInputErr    *service.InvalidInputErr
+		svcFilterErr   *service.InvalidFilterErr
 		ctrlPayloadErr *PayloadErr
 		ctrlParamErr   *ParameterErr
+		authnErr       *middleware.AuthenticationErr
+		authzErr       *middleware.AuthorizationErr
 	)
 
 	switch {
 
+	// ########### CONTEXT ERRORS ###########
+
+	case errors.Is(err, context.DeadlineExceeded):
+		return errHTTP{
+			Code:    http.StatusGatewayTimeout,
+			Status:  timeoutErrStatus,
+			Message: err.Error(),
+		}
+
 	// ########### REPOSITORY ERRORS ###########
+	// The messages of the server errors hold the driver details, they are only logged by errJSON.
 
-	case errors.As(err, &repoErr):
+	case errors.As(err, &repoNotFound):
+		return errHTTP{
+			Code:    http.StatusNotFound,
+			Status:  repoNotFoundStatus,
+			Message: err.Error(),
+		}
 
-		// TODO: evaluate the rest of the repository errors
+	case errors.As(err, &repoConflict):
+		return errHTTP{
+			Code:    http.StatusConflict,
+			Status:  repoConflictStatus,
+			Message: err.Error(),
+		}
 
+	case errors.As(err, &repoConnErr):
+		return errHTTP{
+			Code:    http.StatusServiceUnavailable,
+			Status:  repoConnErrStatus,
+			Message: http.StatusText(http.StatusServiceUnavailable),
+		}
+
+	case errors.As(err, &repoFieldErr):
+		return errHTTP{
+			Code:    http.StatusBadRequest,
+			Status:  repoFieldErrStatus,
+			Message: err.Error(),
+		}
+
+	case errors.As(err, &repoErr):
 		return errHTTP{
 			Code:    http.StatusInternalServerError,
 			Status:  repoErrStatus,
-			Message: err.Error(),
+			Message: http.StatusText(http.StatusInternalServerError),
 		}
 
 	// ########### SERVICE ERRORS ###########
@@ -87,6 +162,20 @@ func newErrHTTP(err error) errHTTP {
 			Message: err.Error(),
 		}
 
+	case errors.As(err, &svcUnauthErr):
+		return errHTTP{
+			Code:    http.StatusUnauthorized,
+			Status:  svcUnauthErrStatus,
+			Message: err.Error(),
+		}
+
+	case errors.As(err, &svcInputErr), errors.As(err, &svcFilterErr):
+		return errHTTP{
+			Code:    http.StatusBadRequest,
+			Status:  svcInvalidErrStatus,
+			Message: err.Error(),
+		}
+
 	// ########### CONTROLLER ERRORS ###########
 
 	case errors.As(err, &ctrlParamErr):
@@ -103,20 +192,93 @@ func newErrHTTP(err error) errHTTP {
 			Message: err.Error(),
 		}
 
+	// ########### MIDDLEWARE ERRORS ###########
+
+	case errors.As(err, &authnErr):
+		return errHTTP{
+			Code:    http.StatusUnauthorized,
+			Status:  authnErrStatus,
+			Message: err.Error(),
+		}
+
+	case errors.As(err, &authzErr):
+		return errHTTP{
+			Code:    http.StatusForbidden,
+			Status:  authzErrStatus,
+			Message: err.Error(),
+		}
+
 	// ########### DEFAULT ERRORS ###########
 
+	// The unknown errors are logged by errJSON, their details are not exposed to the clients.
 	default:
 		return errHTTP{
-			Code:    http.StatusBadRequest,
-			Status:  errStatus(reflect.TypeOf(err).String()),
-			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+			Status:  internalErrStatus,
+			Message: http.StatusText(http.StatusInternalServerError),
+		}
+	}
+}
+
+// newProblem returns the RFC 7807 problem details of the HTTP error.
+func newProblem(errHttp errHTTP, err error) middleware.Problem {
+	problem := middleware.Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(errHttp.Code),
+		Status: errHttp.Code,
+		Detail: errHttp.Message,
+		Errors: problemFields(err),
+	}
+	if title, ok := problemTitles[errHttp.Status]; ok {
+		problem.Type = middleware.ProblemType(errHttp.Status.String())
+		problem.Title = title
+	}
+	return problem
+}
+
+// problemFields returns the field level failures of the error, the joined errors are walked through.
+func problemFields(err error) []middleware.ProblemField {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var fields []middleware.ProblemField
+		for _, e := range joined.Unwrap() {
+			fields = append(fields, problemFields(e)...)
 		}
+		return fields
 	}
+
+	var (
+		svcInputErr  *service.InvalidInputErr
+		svcFilterErr *service.InvalidFilterErr
+		repoFieldErr *repository.InvalidFieldErr
+		repoConflict *repository.ConflictErr
+		ctrlParamErr *ParameterErr
+	)
+	switch {
+	case errors.As(err, &svcInputErr):
+		return []middleware.ProblemField{{Field: svcInputErr.Field, Detail: svcInputErr.Err.Error()}}
+	case errors.As(err, &svcFilterErr):
+		return []middleware.ProblemField{{Field: svcFilterErr.Filter, Detail: svcFilterErr.Err.Error()}}
+	case errors.As(err, &repoFieldErr):
+		return []middleware.ProblemField{{Field: repoFieldErr.Name, Detail: repoFieldErr.Err.Error()}}
+	case errors.As(err, &repoConflict):
+		return []middleware.ProblemField{{Field: repoConflict.Field, Detail: repoConflict.Err.Error()}}
+	case errors.As(err, &ctrlParamErr):
+		return []middleware.ProblemField{{Field: ctrlParamErr.Param, Detail: ctrlParamErr.Err}}
+	}
+	return nil
 }
 
-// errJSON returns an error JSON response
+// errJSON returns an error JSON response, or a problem details response when the request wants it
 func errJSON(w http.ResponseWriter, r *http.Request, err error) {
 	errHttp := newErrHTTP(err)
+	if errHttp.Code >= http.StatusInternalServerError {
+		// The server errors are logged with the request ID, the access log line only holds their status.
+		logger.FromContext(r.Context()).Log().Error().Ctx(r.Context()).Err(err).Int("status", errHttp.Code).Msg("request failed")
+	}
+	if middleware.WantsProblem(r) {
+		middleware.WriteProblem(w, r, newProblem(errHttp, err))
+		return
+	}
 	render.Status(r, errHttp.Code)
 	render.JSON(w, r, errHttp)
 }
@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/middleware"
+	"github.com/wizeline/CA-Microservices-Go/internal/repository"
+	"github.com/wizeline/CA-Microservices-Go/internal/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewErrHTTP(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		exp  errHTTP
+	}{
+		{
+			name: "Request timeout",
+			err:  &repository.Err{Err: context.DeadlineExceeded},
+			exp: errHTTP{
+				Code:    http.StatusGatewayTimeout,
+				Status:  timeoutErrStatus,
+				Message: "repository: context deadline exceeded",
+			},
+		},
+		{
+			name: "Repository not found",
+			err:  &repository.NotFoundErr{Entity: "user"},
+			exp: errHTTP{
+				Code:    http.StatusNotFound,
+				Status:  repoNotFoundStatus,
+				Message: "user not found",
+			},
+		},
+		{
+			name: "Repository conflict",
+			err:  &repository.ConflictErr{Entity: "user", Field: "email", Constraint: "users_email_key", Err: repository.ErrDuplicated},
+			exp: errHTTP{
+				Code:    http.StatusConflict,
+				Status:  repoConflictStatus,
+				Message: "user conflict on field email: already exists",
+			},
+		},
+		{
+			name: "Repository connection",
+			err:  &repository.ConnectionErr{Err: errors.New("connection refused")},
+			exp: errHTTP{
+				Code:    http.StatusServiceUnavailable,
+				Status:  repoConnErrStatus,
+				Message: "Service Unavailable",
+			},
+		},
+		{
+			name: "Repository invalid field",
+			err:  &repository.InvalidFieldErr{Name: "passwd", Err: repository.ErrFieldNotSupported},
+			exp: errHTTP{
+				Code:    http.StatusBadRequest,
+				Status:  repoFieldErrStatus,
+				Message: "invalid field passwd: field not supported",
+			},
+		},
+		{
+			name: "Repository error",
+			err:  &repository.Err{Err: errors.New("some repo error")},
+			exp: errHTTP{
+				Code:    http.StatusInternalServerError,
+				Status:  repoErrStatus,
+				Message: "Internal Server Error",
+			},
+		},
+		{
+			name: "Service validation error",
+			err:  &service.InvalidInputErr{Field: "email", Err: service.ErrInvalidEmail},
+			exp: errHTTP{
+				Code:    http.StatusBadRequest,
+				Status:  svcInvalidErrStatus,
+				Message: "invalid input for field \"email\" : invalid email",
+			},
+		},
+		{
+			name: "Service error",
+			err:  &service.Err{Err: errors.New("some svc error")},
+			exp: errHTTP{
+				Code:    http.StatusBadRequest,
+				Status:  svcErrStatus,
+				Message: "service: some svc error",
+			},
+		},
+		{
+			name: "Unknown error",
+			err:  errors.New("some error"),
+			exp: errHTTP{
+				Code:    http.StatusInternalServerError,
+				Status:  internalErrStatus,
+				Message: "Internal Server Error",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.exp, newErrHTTP(tt.err))
+		})
+	}
+}
+
+func TestErrJSON_problem(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+		body string
+	}{
+		{
+			name: "Validation error",
+			err:  &service.InvalidInputErr{Field: "email", Err: service.ErrInvalidEmail},
+			code: http.StatusBadRequest,
+			body: "{\"type\":\"urn:problem-type:ServiceValidationError\",\"title\":\"Invalid input\",\"status\":400,\"detail\":\"invalid input for field \\\"email\\\" : invalid email\",\"errors\":[{\"field\":\"email\",\"detail\":\"invalid email\"}]}\n",
+		},
+		{
+			name: "Joined validation errors",
+			err: errors.Join(
+				&service.InvalidInputErr{Field: "first_name", Err: service.ErrEmptyValue},
+				&service.InvalidInputErr{Field: "email", Err: service.ErrInvalidEmail},
+			),
+			code: http.StatusBadRequest,
+			body: "{\"type\":\"urn:problem-type:ServiceValidationError\",\"title\":\"Invalid input\",\"status\":400,\"detail\":\"invalid input for field \\\"first_name\\\" : empty value\\ninvalid input for field \\\"email\\\" : invalid email\",\"errors\":[{\"field\":\"first_name\",\"detail\":\"empty value\"},{\"field\":\"email\",\"detail\":\"invalid email\"}]}\n",
+		},
+		{
+			name: "Not found",
+			err:  &repository.NotFoundErr{Entity: "user"},
+			code: http.StatusNotFound,
+			body: "{\"type\":\"urn:problem-type:RepositoryNotFoundError\",\"title\":\"Resource not found\",\"status\":404,\"detail\":\"user not found\"}\n",
+		},
+		{
+			name: "Unknown error",
+			err:  errors.New("some error"),
+			code: http.StatusInternalServerError,
+			body: "{\"type\":\"urn:problem-type:InternalError\",\"title\":\"Internal server error\",\"status\":500,\"detail\":\"Internal Server Error\"}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/users", nil)
+			req.Header.Set("Accept", middleware.ProblemContentType)
+			rec := httptest.NewRecorder()
+
+			errJSON(rec, req, tt.err)
+
+			assert.Equal(t, tt.code, rec.Code)
+			assert.Equal(t, middleware.ProblemContentType, rec.Header().Get("Content-Type"))
+			assert.Equal(t, tt.body, rec.Body.String())
+		})
+	}
+}
@@ -1,7 +1,12 @@
 package controller
 
 import (
+	"context"
 	"net/http"
+	"time"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/health"
+	"github.com/wizeline/CA-Microservices-Go/internal/logger"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/render"
@@ -10,30 +15,134 @@ import (
 // We ensure the HTTP interface signature is satisfied by the HealthCheckHTTP implementation
 var _ HTTP = &HealthCheckHTTP{}
 
+const (
+	readyStatus        = "ready"
+	notReadyStatus     = "not ready"
+	shuttingDownStatus = "shutting down"
+
+	checkUpStatus   = "up"
+	checkDownStatus = "down"
+)
+
+// Readiness is an abstraction of the readiness checks dependency used by the HealthCheckHTTP
+type Readiness interface {
+	Check(ctx context.Context) health.Report
+}
+
+// readinessResponse represents the data transfer object response for the readiness of the node
+type readinessResponse struct {
+	Status string          `json:"status"`
+	Checks []checkResponse `json:"checks"`
+}
+
+// checkResponse represents the data transfer object response for a readiness check.
+// The probe is not authenticated, the last error is only described, its details are logged.
+type checkResponse struct {
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	Critical    bool       `json:"critical"`
+	LatencyMs   float64    `json:"latency_ms"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+}
+
 // HealthCheckHTTP represents the system monitoring tool.
-type HealthCheckHTTP struct{}
+type HealthCheckHTTP struct {
+	readiness Readiness
+}
 
 // NewHealthCheckHTTP returns a new HealthCheckHTTP implementation.
-func NewHealthCheckHTTP() HealthCheckHTTP {
-	return HealthCheckHTTP{}
+func NewHealthCheckHTTP(readiness Readiness) HealthCheckHTTP {
+	return HealthCheckHTTP{
+		readiness: readiness,
+	}
 }
 
 // SetRoutes sets a fresh middleware stack to configure the handle functions of HealthCheckHTTP and mounts them to the given subrouter.
 func (h HealthCheckHTTP) SetRoutes(r chi.Router) {
 	r.Get("/healthz", h.heartbeat)
+	r.Get("/livez", h.heartbeat)
+	r.Get("/readyz", h.ready)
 }
 
 // heartbeat godoc
 // @Summary Check if node is alive
-// @Description  Check if node is alive
+// @Description  Check if the process is alive, its dependencies are not checked. /healthz is kept as an alias of /livez.
 // @Tags         admin
 // @Accept       json
 // @Produce      json
 // @Success      200  {object}  basicMessage
-// @Failure      503  {object}  errHTTP
+// @Router       /livez [get]
 // @Router       /healthz [get]
 func (h HealthCheckHTTP) heartbeat(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, basicMessage{
 		Message: http.StatusText(http.StatusOK),
 	})
 }
+
+// ready godoc
+// @Summary Check if node is ready
+// @Description  Run the checks of the node dependencies at the same time, and report their status, latency and last error.
+// @Description  The last error is only described, e.g. "check failed", the details of the failed checks are logged.
+// @Description  The node is not ready when a critical check fails, or once it is shutting down.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  readinessResponse
+// @Failure      503  {object}  readinessResponse
+// @Router       /readyz [get]
+func (h HealthCheckHTTP) ready(w http.ResponseWriter, r *http.Request) {
+	report := h.readiness.Check(r.Context())
+	logFailedChecks(r.Context(), report)
+	if !report.Ready {
+		render.Status(r, http.StatusServiceUnavailable)
+	}
+	render.JSON(w, r, parseReadinessResponse(report))
+}
+
+// logFailedChecks logs the errors of the failed readiness checks, they are kept out of the public response.
+func logFailedChecks(ctx context.Context, report health.Report) {
+	for _, res := range report.Results {
+		if res.Healthy() {
+			continue
+		}
+		logger.FromContext(ctx).Log().Warn().Ctx(ctx).
+			Str("check", res.Name).
+			Bool("critical", res.Critical).
+			Err(res.Err).
+			Time("last_error_at", res.LastErrorAt).
+			Msg("readiness check failed")
+	}
+}
+
+// parseReadinessResponse returns the public response of the readiness report, the errors of the checks are only described.
+func parseReadinessResponse(report health.Report) readinessResponse {
+	resp := readinessResponse{
+		Status: readyStatus,
+		Checks: make([]checkResponse, 0, len(report.Results)),
+	}
+	switch {
+	case report.ShuttingDown:
+		resp.Status = shuttingDownStatus
+	case !report.Ready:
+		resp.Status = notReadyStatus
+	}
+	for _, res := range report.Results {
+		check := checkResponse{
+			Name:      res.Name,
+			Status:    checkUpStatus,
+			Critical:  res.Critical,
+			LatencyMs: float64(res.Latency.Microseconds()) / 1000,
+			LastError: res.LastFailure,
+		}
+		if !res.Healthy() {
+			check.Status = checkDownStatus
+		}
+		if !res.LastErrorAt.IsZero() {
+			lastErrorAt := res.LastErrorAt.UTC()
+			check.LastErrorAt = &lastErrorAt
+		}
+		resp.Checks = append(resp.Checks, check)
+	}
+	return resp
+}
@@ -1,24 +1,159 @@
 package controller
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/controller/mocks"
+	"github.com/wizeline/CA-Microservices-Go/internal/health"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
+// We ensure the Readiness mock object satisfies the Readiness dependency signature.
+var _ Readiness = &mocks.Readiness{}
+
 func TestHealthCheck_heartbeat(t *testing.T) {
-	ctrl := NewHealthCheckHTTP()
+	ctrl := NewHealthCheckHTTP(&mocks.Readiness{})
+
+	for _, path := range []string{"/healthz", "/livez"} {
+		t.Run(path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			rec := httptest.NewRecorder()
+
+			r := chi.NewRouter()
+			r.Get(path, ctrl.heartbeat)
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, "{\"message\":\"OK\"}\n", rec.Body.String())
+		})
+	}
+}
+
+func TestHealthCheck_ready(t *testing.T) {
+	lastErrorAt := time.Date(2024, 4, 21, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		report health.Report
+		code   int
+		resp   readinessResponse
+	}{
+		{
+			name:   "No checks",
+			report: health.Report{Ready: true},
+			code:   http.StatusOK,
+			resp:   readinessResponse{Status: readyStatus, Checks: []checkResponse{}},
+		},
+		{
+			name: "Ready",
+			report: health.Report{
+				Ready: true,
+				Results: []health.Result{
+					{Name: "database", Critical: true, Latency: 1500 * time.Microsecond, LastError: "connection refused", LastFailure: health.FailureError, LastErrorAt: lastErrorAt},
+					{Name: "cache", Err: errors.New("timeout"), Latency: time.Millisecond, LastError: "timeout", LastFailure: health.FailureTimeout, LastErrorAt: lastErrorAt},
+				},
+			},
+			code: http.StatusOK,
+			resp: readinessResponse{
+				Status: readyStatus,
+				Checks: []checkResponse{
+					{Name: "database", Status: checkUpStatus, Critical: true, LatencyMs: 1.5, LastError: health.FailureError, LastErrorAt: &lastErrorAt},
+					{Name: "cache", Status: checkDownStatus, LatencyMs: 1, LastError: health.FailureTimeout, LastErrorAt: &lastErrorAt},
+				},
+			},
+		},
+		{
+			name: "Critical check failed",
+			report: health.Report{
+				Results: []health.Result{
+					{Name: "database", Critical: true, Err: errors.New("connection refused"), Latency: time.Millisecond, LastError: "connection refused", LastFailure: health.FailureError, LastErrorAt: lastErrorAt},
+				},
+			},
+			code: http.StatusServiceUnavailable,
+			resp: readinessResponse{
+				Status: notReadyStatus,
+				Checks: []checkResponse{
+					{Name: "database", Status: checkDownStatus, Critical: true, LatencyMs: 1, LastError: health.FailureError, LastErrorAt: &lastErrorAt},
+				},
+			},
+		},
+		{
+			name: "Shutting down",
+			report: health.Report{
+				ShuttingDown: true,
+				Results:      []health.Result{{Name: "database", Critical: true}},
+			},
+			code: http.StatusServiceUnavailable,
+			resp: readinessResponse{
+				Status: shuttingDownStatus,
+				Checks: []checkResponse{{Name: "database", Status: checkUpStatus, Critical: true}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			readiness := &mocks.Readiness{}
+			readiness.On("Check", mock.Anything).Return(test.report)
+			ctrl := NewHealthCheckHTTP(readiness)
+
+			req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			rec := httptest.NewRecorder()
+
+			ctrl.ready(rec, req)
+
+			assert.Equal(t, test.code, rec.Code)
+			var resp readinessResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, test.resp, resp)
+			assert.NotContains(t, rec.Body.String(), "connection refused", "the check errors are not exposed")
+		})
+	}
+}
+
+func TestHealthCheck_ready_lastError(t *testing.T) {
+	failing := true
+	readiness := health.NewReadiness(time.Second, health.Check{
+		Name: "database",
+		Checker: health.CheckerFunc(func(context.Context) error {
+			if failing {
+				return errors.New("dial tcp 10.0.0.7:5432: connection refused")
+			}
+			return nil
+		}),
+		Critical: true,
+	})
+	ctrl := NewHealthCheckHTTP(readiness)
 
-	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
-	rec := httptest.NewRecorder()
+	ready := func() map[string]any {
+		rec := httptest.NewRecorder()
+		ctrl.ready(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		assert.NotContains(t, rec.Body.String(), "10.0.0.7", "the check errors are not exposed")
+		var resp struct {
+			Checks []map[string]any `json:"checks"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		require.Len(t, resp.Checks, 1)
+		return resp.Checks[0]
+	}
 
-	r := chi.NewRouter()
-	r.Get("/healthz", ctrl.heartbeat)
-	r.ServeHTTP(rec, req)
+	check := ready()
+	assert.Equal(t, health.FailureError, check["last_error"])
+	failedAt := check["last_error_at"]
+	assert.NotEmpty(t, failedAt)
 
-	assert.Equal(t, http.StatusOK, rec.Code)
-	assert.Equal(t, "{\"message\":\"OK\"}\n", rec.Body.String())
+	failing = false
+	check = ready()
+	assert.Equal(t, checkUpStatus, check["status"])
+	assert.Equal(t, health.FailureError, check["last_error"], "the last error is kept once the check recovers")
+	assert.Equal(t, failedAt, check["last_error_at"])
 }
@@ -0,0 +1,207 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+	"github.com/wizeline/CA-Microservices-Go/internal/service"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+)
+
+// We ensure the HTTP interface signature is satisfied by the RoleHTTP implementation
+var _ HTTP = &RoleHTTP{}
+
+// roleRequest represents the data transfer object requested for creating or updating a role
+type roleRequest struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+// roleResponse represents the data transfer object response for a role
+type roleResponse struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+// RoleService is an abstraction of the RoleService dependency used by the RoleHTTP
+type RoleService interface {
+	Create(ctx context.Context, args service.RoleArgs) error
+	Get(ctx context.Context, name string) (service.RoleResponse, error)
+	GetAll(ctx context.Context) ([]service.RoleResponse, error)
+	Update(ctx context.Context, args service.RoleArgs) error
+	Delete(ctx context.Context, name string) error
+}
+
+// RoleHTTP is the controller managing the roles permission matrix.
+type RoleHTTP struct {
+	svc   RoleService
+	auth  Authenticator
+	authz Authorizer
+}
+
+// NewRoleHTTP returns a new RoleHTTP implementation.
+func NewRoleHTTP(svc RoleService, auth Authenticator, authz Authorizer) RoleHTTP {
+	return RoleHTTP{
+		svc:   svc,
+		auth:  auth,
+		authz: authz,
+	}
+}
+
+// SetRoutes sets a fresh middleware stack to configure the handle functions of the RoleHTTP and mounts them to the given subrouter.
+// Every route requires the permission to manage roles.
+func (rc RoleHTTP) SetRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(rc.auth.Authenticate)
+		r.Use(rc.authz.RequirePermission(entity.PermRolesManage))
+		r.Post("/roles", rc.create)
+		r.Get("/roles", rc.getAll)
+		r.Get("/roles/{name}", rc.get)
+		r.Put("/roles/{name}", rc.update)
+		r.Delete("/roles/{name}", rc.delete)
+	})
+}
+
+// create godoc
+// @Summary creates a new role
+// @Description  Creates a new role with its set of permissions
+// @Tags         role
+// @Produce      json
+// @Param        request body roleRequest true "New Role"
+// @Success      201  {object}  basicMessage
+// @Failure      400  {object}  errHTTP
+// @Failure      401  {object}  errHTTP
+// @Failure      403  {object}  errHTTP
+// @Failure      409  {object}  errHTTP
+// @Failure      500  {object}  errHTTP
+// @Security     BearerAuth
+// @Router       /roles [post]
+func (rc RoleHTTP) create(w http.ResponseWriter, r *http.Request) {
+	var dto roleRequest
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		errJSON(w, r, &PayloadErr{err})
+		return
+	}
+	args := service.RoleArgs{
+		Name:        dto.Name,
+		Description: dto.Description,
+		Permissions: dto.Permissions,
+	}
+	if err := rc.svc.Create(r.Context(), args); err != nil {
+		errJSON(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, basicMessage{Message: fmt.Sprintf("role %v created successfully", args.Name)})
+}
+
+// get godoc
+// @Summary retrieves a role by name
+// @Description  retrieves a role and its permissions by name
+// @Tags         role
+// @Produce      json
+// @Param        name   path     string  true  "Role Name"
+// @Success      200  {object}  roleResponse
+// @Failure      400  {object}  errHTTP
+// @Failure      401  {object}  errHTTP
+// @Failure      403  {object}  errHTTP
+// @Failure      404  {object}  errHTTP
+// @Failure      500  {object}  errHTTP
+// @Security     BearerAuth
+// @Router       /roles/{name} [get]
+func (rc RoleHTTP) get(w http.ResponseWriter, r *http.Request) {
+	role, err := rc.svc.Get(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	render.JSON(w, r, parseRoleResponse(role))
+}
+
+// getAll godoc
+// @Summary retrieves all roles
+// @Description  retrieves the whole permission matrix
+// @Tags         role
+// @Produce      json
+// @Success      200  {object}  []roleResponse
+// @Failure      401  {object}  errHTTP
+// @Failure      403  {object}  errHTTP
+// @Failure      500  {object}  errHTTP
+// @Security     BearerAuth
+// @Router       /roles [get]
+func (rc RoleHTTP) getAll(w http.ResponseWriter, r *http.Request) {
+	roles, err := rc.svc.GetAll(r.Context())
+	if err != nil {
+		errJSON(w, r, err)
+		return
+	}
+
+	rolesResp := make([]roleResponse, 0)
+	for _, role := range roles {
+		rolesResp = append(rolesResp, parseRoleResponse(role))
+	}
+	render.JSON(w, r, rolesResp)
+}
+
+// update godoc
+// @Summary updates a role
+// @Description  Replaces the description and the permissions of a role
+// @Tags         role
+// @Produce      json
+// @Param        name      path     string       true  "Role Name"
+// @Param        request   body     roleRequest  true  "Role Update Request"
+// @Success      200  {object}  basicMessage
+// @Failure      400  {object}  errHTTP
+// @Failure      401  {object}  errHTTP
+// @Failure      403  {object}  errHTTP
+// @Failure      404  {object}  errHTTP
+// @Failure      500  {object}  errHTTP
+// @Security     BearerAuth
+// @Router       /roles/{name} [put]
+func (rc RoleHTTP) update(w http.ResponseWriter, r *http.Request) {
+	var dto roleRequest
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		errJSON(w, r, &PayloadErr{err})
+		return
+	}
+	args := service.RoleArgs{
+		Name:        chi.URLParam(r, "name"),
+		Description: dto.Description,
+		Permissions: dto.Permissions,
+	}
+	if err := rc.svc.Update(r.Context(), args); err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	render.JSON(w, r, basicMessage{Message: fmt.Sprintf("role %v updated successfully", args.Name)})
+}
+
+// delete godoc
+// @Summary deletes a role
+// @Description  deletes a custom role. The built-in roles can not be deleted.
+// @Tags         role
+// @Produce      json
+// @Param        name   path     string  true  "Role Name"
+// @Success      200  {object}  basicMessage
+// @Failure      400  {object}  errHTTP
+// @Failure      401  {object}  errHTTP
+// @Failure      403  {object}  errHTTP
+// @Failure      500  {object}  errHTTP
+// @Security     BearerAuth
+// @Router       /roles/{name} [delete]
+func (rc RoleHTTP) delete(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	if err := rc.svc.Delete(r.Context(), name); err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	render.JSON(w, r, basicMessage{Message: fmt.Sprintf("role %v deleted successfully", name)})
+}
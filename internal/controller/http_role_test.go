@@ -0,0 +1,308 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/controller/mocks"
+	"github.com/wizeline/CA-Microservices-Go/internal/repository"
+	"github.com/wizeline/CA-Microservices-Go/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// We ensure the RoleSvc mock object satisfies the RoleService dependency signature.
+var _ RoleService = &mocks.RoleSvc{}
+
+func TestRoleController_create(t *testing.T) {
+	type svc struct {
+		args service.RoleArgs
+		err  error
+	}
+	tests := []struct {
+		name     string
+		svc      svc
+		httpReq  httpRequestTest
+		httpResp httpResponseTest
+		err      errHTTP
+	}{
+		{
+			name: "Payload empty",
+			httpReq: httpRequestTest{
+				payload: []byte(""),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusUnsupportedMediaType,
+			},
+			err: errHTTP{
+				Code:    http.StatusUnsupportedMediaType,
+				Status:  ctrlPayloadErrStatus,
+				Message: "invalid payload: EOF",
+			},
+		},
+		{
+			name: "Service error",
+			svc: svc{
+				args: service.RoleArgs{Name: "Bad Name"},
+				err:  &service.Err{Err: errors.New("some svc error")},
+			},
+			httpReq: httpRequestTest{
+				payload: []byte(`{"name": "Bad Name"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusBadRequest,
+			},
+			err: errHTTP{
+				Code:    http.StatusBadRequest,
+				Status:  svcErrStatus,
+				Message: "service: some svc error",
+			},
+		},
+		{
+			name: "Created",
+			svc: svc{
+				args: service.RoleArgs{
+					Name:        "auditor",
+					Description: "read only access",
+					Permissions: []string{"users:read"},
+				},
+			},
+			httpReq: httpRequestTest{
+				payload: []byte(`{"name": "auditor", "description": "read only access", "permissions": ["users:read"]}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusCreated,
+				body: "{\"message\":\"role auditor created successfully\"}\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := &mocks.RoleSvc{}
+			mockSvc.On("Create", mock.Anything, test.svc.args).Return(test.svc.err)
+			ctrl := NewRoleHTTP(mockSvc, &mocks.Authenticator{}, &mocks.Authorizer{})
+
+			req := httptest.NewRequest(http.MethodPost, "/roles", bytes.NewBuffer(test.httpReq.payload))
+			rec := httptest.NewRecorder()
+
+			ctrl.create(rec, req)
+
+			assert.Equal(t, rec.Code, test.httpResp.code)
+			if test.err != (errHTTP{}) {
+				var errMsg errHTTP
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errMsg))
+				assert.Equal(t, test.err, errMsg)
+				return
+			}
+			assert.Equal(t, test.httpResp.body, rec.Body.String())
+		})
+	}
+}
+
+func TestRoleController_get(t *testing.T) {
+	type svcResp struct {
+		role service.RoleResponse
+		err  error
+	}
+	tests := []struct {
+		name     string
+		svcResp  svcResp
+		httpReq  httpRequestTest
+		httpResp httpResponseTest
+		err      errHTTP
+	}{
+		{
+			name: "Repository error",
+			svcResp: svcResp{
+				err: &repository.Err{Err: errors.New("some repo error")},
+			},
+			httpReq: httpRequestTest{
+				params: map[string]string{"name": "admin"},
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusInternalServerError,
+			},
+			err: errHTTP{
+				Code:    http.StatusInternalServerError,
+				Status:  repoErrStatus,
+				Message: "Internal Server Error",
+			},
+		},
+		{
+			name: "Valid",
+			svcResp: svcResp{
+				role: service.RoleResponse{
+					Name:        "admin",
+					Description: "administrators",
+					Permissions: []string{"roles:manage", "users:delete"},
+				},
+			},
+			httpReq: httpRequestTest{
+				params: map[string]string{"name": "admin"},
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusOK,
+				body: "{\"name\":\"admin\",\"description\":\"administrators\",\"permissions\":[\"roles:manage\",\"users:delete\"]}\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := &mocks.RoleSvc{}
+			mockSvc.On("Get", mock.Anything, test.httpReq.params["name"]).Return(test.svcResp.role, test.svcResp.err)
+			ctrl := NewRoleHTTP(mockSvc, &mocks.Authenticator{}, &mocks.Authorizer{})
+
+			req := httptest.NewRequest(http.MethodGet, "/roles/"+test.httpReq.params["name"], nil)
+			req = withURLParams(req, test.httpReq.params)
+			rec := httptest.NewRecorder()
+
+			ctrl.get(rec, req)
+
+			assert.Equal(t, rec.Code, test.httpResp.code)
+			if test.err != (errHTTP{}) {
+				var errMsg errHTTP
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errMsg))
+				assert.Equal(t, test.err, errMsg)
+				return
+			}
+			assert.Equal(t, test.httpResp.body, rec.Body.String())
+		})
+	}
+}
+
+func TestRoleController_update(t *testing.T) {
+	type svc struct {
+		args service.RoleArgs
+		err  error
+	}
+	tests := []struct {
+		name     string
+		svc      svc
+		httpReq  httpRequestTest
+		httpResp httpResponseTest
+		err      errHTTP
+	}{
+		{
+			name: "Bad JSON",
+			httpReq: httpRequestTest{
+				params:  map[string]string{"name": "auditor"},
+				payload: []byte(`{"permissions": ["users:read"]`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusUnsupportedMediaType,
+			},
+			err: errHTTP{
+				Code:    http.StatusUnsupportedMediaType,
+				Status:  ctrlPayloadErrStatus,
+				Message: "invalid payload: unexpected EOF",
+			},
+		},
+		{
+			name: "Updated",
+			svc: svc{
+				args: service.RoleArgs{
+					Name:        "auditor",
+					Permissions: []string{"users:read"},
+				},
+			},
+			httpReq: httpRequestTest{
+				params:  map[string]string{"name": "auditor"},
+				payload: []byte(`{"permissions": ["users:read"]}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusOK,
+				body: "{\"message\":\"role auditor updated successfully\"}\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := &mocks.RoleSvc{}
+			mockSvc.On("Update", mock.Anything, test.svc.args).Return(test.svc.err)
+			ctrl := NewRoleHTTP(mockSvc, &mocks.Authenticator{}, &mocks.Authorizer{})
+
+			req := httptest.NewRequest(http.MethodPut, "/roles/"+test.httpReq.params["name"], bytes.NewBuffer(test.httpReq.payload))
+			req = withURLParams(req, test.httpReq.params)
+			rec := httptest.NewRecorder()
+
+			ctrl.update(rec, req)
+
+			assert.Equal(t, rec.Code, test.httpResp.code)
+			if test.err != (errHTTP{}) {
+				var errMsg errHTTP
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errMsg))
+				assert.Equal(t, test.err, errMsg)
+				return
+			}
+			assert.Equal(t, test.httpResp.body, rec.Body.String())
+		})
+	}
+}
+
+func TestRoleController_delete(t *testing.T) {
+	tests := []struct {
+		name     string
+		svcErr   error
+		httpReq  httpRequestTest
+		httpResp httpResponseTest
+		err      errHTTP
+	}{
+		{
+			name:   "Built-in role",
+			svcErr: &service.Err{Err: service.ErrBuiltInRole},
+			httpReq: httpRequestTest{
+				params: map[string]string{"name": "admin"},
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusBadRequest,
+			},
+			err: errHTTP{
+				Code:    http.StatusBadRequest,
+				Status:  svcErrStatus,
+				Message: "service: built-in role",
+			},
+		},
+		{
+			name: "Deleted",
+			httpReq: httpRequestTest{
+				params: map[string]string{"name": "auditor"},
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusOK,
+				body: "{\"message\":\"role auditor deleted successfully\"}\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := &mocks.RoleSvc{}
+			mockSvc.On("Delete", mock.Anything, test.httpReq.params["name"]).Return(test.svcErr)
+			ctrl := NewRoleHTTP(mockSvc, &mocks.Authenticator{}, &mocks.Authorizer{})
+
+			req := httptest.NewRequest(http.MethodDelete, "/roles/"+test.httpReq.params["name"], nil)
+			req = withURLParams(req, test.httpReq.params)
+			rec := httptest.NewRecorder()
+
+			ctrl.delete(rec, req)
+
+			assert.Equal(t, rec.Code, test.httpResp.code)
+			if test.err != (errHTTP{}) {
+				var errMsg errHTTP
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errMsg))
+				assert.Equal(t, test.err, errMsg)
+				return
+			}
+			assert.Equal(t, test.httpResp.body, rec.Body.String())
+		})
+	}
+}
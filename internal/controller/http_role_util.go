@@ -0,0 +1,15 @@
+package controller
+
+import "github.com/wizeline/CA-Microservices-Go/internal/service"
+
+func parseRoleResponse(role service.RoleResponse) roleResponse {
+	perms := role.Permissions
+	if perms == nil {
+		perms = make([]string, 0)
+	}
+	return roleResponse{
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: perms,
+	}
+}
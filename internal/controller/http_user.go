@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -8,6 +9,8 @@ import (
 	"time"
 
 	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+	"github.com/wizeline/CA-Microservices-Go/internal/logger"
+	"github.com/wizeline/CA-Microservices-Go/internal/middleware"
 	"github.com/wizeline/CA-Microservices-Go/internal/service"
 
 	"github.com/go-chi/chi"
@@ -17,6 +20,15 @@ import (
 // We ensure the HTTP interface signature is satisfied by the UserHTTP implementation
 var _ HTTP = &UserHTTP{}
 
+// tokenType is the authentication scheme of the issued access tokens.
+const tokenType = "Bearer"
+
+// The query and body based user routes are kept until the sunset date, the /users/{id} routes replace them.
+var (
+	legacyRoutesDeprecatedAt = time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+	legacyRoutesSunsetAt     = time.Date(2027, time.April, 18, 0, 0, 0, 0, time.UTC)
+)
+
 // userCreateRequest represents the data transfer object requested for creating a user
 type userCreateRequest struct {
 	FirstName string `json:"first_name"`
@@ -36,6 +48,13 @@ type userUpdateRequest struct {
 	Username  string `json:"username"`
 }
 
+// userWriteRequest represents the data transfer object requested for replacing or patching the user given in the path
+type userWriteRequest struct {
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	BirthDay  string `json:"birthday"`
+}
+
 // userResponse represents the data transfer object response for a default user
 type userResponse struct {
 	ID        string `json:"id"`
@@ -44,6 +63,63 @@ type userResponse struct {
 	Email     string `json:"email"`
 	BirthDay  string `json:"birthday"`
 	Username  string `json:"username"`
+	Role      string `json:"role"`
+}
+
+// userListResponse represents the data transfer object response for a page of users
+type userListResponse struct {
+	Users      []userResponse `json:"users"`
+	Total      int            `json:"total"`
+	Limit      int            `json:"limit"`
+	Offset     int            `json:"offset"`
+	NextCursor string         `json:"next_cursor"`
+	PrevCursor string         `json:"prev_cursor"`
+}
+
+// userRoleRequest represents the data transfer object requested for changing the role of a user
+type userRoleRequest struct {
+	ID   string `json:"id"`
+	Role string `json:"role"`
+}
+
+// userIDRequest represents the data transfer object requested for the operations over a single user
+type userIDRequest struct {
+	ID string `json:"id"`
+}
+
+// userEmailRequest represents the data transfer object requested for changing the email of a user
+type userEmailRequest struct {
+	ID    string `json:"id"`
+	Email string `json:"email"`
+}
+
+// userPasswdRequest represents the data transfer object requested for changing the password of a user
+type userPasswdRequest struct {
+	ID            string `json:"id"`
+	CurrentPasswd string `json:"current_password"`
+	NewPasswd     string `json:"new_password"`
+}
+
+// userRoleWriteRequest represents the data transfer object requested for changing the role of the user given in the path
+type userRoleWriteRequest struct {
+	Role string `json:"role"`
+}
+
+// userEmailWriteRequest represents the data transfer object requested for changing the email of the user given in the path
+type userEmailWriteRequest struct {
+	Email string `json:"email"`
+}
+
+// userPasswdWriteRequest represents the data transfer object requested for changing the password of the user given in the path
+type userPasswdWriteRequest struct {
+	CurrentPasswd string `json:"current_password"`
+	NewPasswd     string `json:"new_password"`
+}
+
+// userActiveResponse represents the data transfer object response for the active status of a user
+type userActiveResponse struct {
+	ID     string `json:"id"`
+	Active bool   `json:"active"`
 }
 
 // userLoginRequest represents the data transfer object requested for login a user
@@ -54,52 +130,129 @@ type userLoginRequest struct {
 
 // userLoginResponse represents the data transfer object response for a logged user
 type userLoginResponse struct {
-	ID        string `json:"id"`
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	Email     string `json:"email"`
-	Username  string `json:"username"`
-	LastLogin string `json:"last_login"`
+	ID          string `json:"id"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+	Email       string `json:"email"`
+	Username    string `json:"username"`
+	LastLogin   string `json:"last_login"`
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresAt   string `json:"expires_at"`
+
+	RefreshToken     string `json:"refresh_token"`
+	RefreshExpiresAt string `json:"refresh_expires_at"`
+}
+
+// tokenRequest represents the data transfer object requested for refreshing or revoking tokens
+type tokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// tokenResponse represents the data transfer object response for a refreshed token pair
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresAt        string `json:"expires_at"`
+	RefreshToken     string `json:"refresh_token"`
+	RefreshExpiresAt string `json:"refresh_expires_at"`
 }
 
 // UserService is an abstraction of the UserService dependecy used by the UserHTTP
 type UserService interface {
-	Create(args service.UserCreateArgs) error
-	Get(id uint64) (service.UserResponse, error)
-	GetAll() ([]service.UserResponse, error)
-	Find(filter, value string) ([]entity.User, error)
-	Update(args service.UserUpdateArgs) error
-	Delete(id uint64) error
+	Create(ctx context.Context, args service.UserCreateArgs) error
+	Get(ctx context.Context, id uint64) (service.UserResponse, error)
+	GetAll(ctx context.Context) ([]service.UserResponse, error)
+	Find(ctx context.Context, filter, value string) ([]entity.User, error)
+	Search(ctx context.Context, query entity.UserQuery, cursor string) (service.UserSearchResponse, error)
+	Update(ctx context.Context, args service.UserUpdateArgs) error
+	Delete(ctx context.Context, id uint64) error
+
+	Activate(ctx context.Context, id uint64) error
+	Deactivate(ctx context.Context, id uint64) error
+	ChangeEmail(ctx context.Context, id uint64, email string) error
+	ChangePasswd(ctx context.Context, id uint64, currentPasswd, passwd string) error
+	ChangeRole(ctx context.Context, id uint64, role string) error
+	IsActive(ctx context.Context, id uint64) (bool, error)
+	ValidateLogin(ctx context.Context, username string, passwd string) (service.UserLoginResponse, error)
+}
+
+// TokenService is an abstraction of the refresh TokenService dependency used by the UserHTTP
+type TokenService interface {
+	Issue(ctx context.Context, userID uint64) (service.RefreshTokenResponse, error)
+	Verify(ctx context.Context, token string) (uint64, error)
+	Rotate(ctx context.Context, token string) (service.RefreshTokenResponse, error)
+	Revoke(ctx context.Context, token string) error
+	RevokeAll(ctx context.Context, userID uint64) error
+}
+
+// Authenticator is an abstraction of the token based authentication used by the UserHTTP
+type Authenticator interface {
+	IssueAccessToken(userID uint64, username, role string) (string, time.Time, error)
+	Authenticate(next http.Handler) http.Handler
+}
 
-	Activate(id uint64) error
-	ChangeEmail(id uint64, email string) error
-	ChangePasswd(id uint64, passwd string) error
-	IsActive(id uint64) (bool, error)
-	ValidateLogin(username string, passwd string) (service.UserLoginResponse, error)
+// Authorizer is an abstraction of the role based access control used by the HTTP controllers
+type Authorizer interface {
+	RequirePermission(permission string) func(http.Handler) http.Handler
+	AuthorizeOwner(ctx context.Context, ownerID uint64, permission string) error
 }
 
 // UserHTTP is the user controller representation.
 type UserHTTP struct {
-	svc UserService
+	svc    UserService
+	tokens TokenService
+	auth   Authenticator
+	authz  Authorizer
 }
 
 // NewUserHTTP returns a new UserHTTP implementation.
-func NewUserHTTP(svc UserService) UserHTTP {
+func NewUserHTTP(svc UserService, tokens TokenService, auth Authenticator, authz Authorizer) UserHTTP {
 	return UserHTTP{
-		svc: svc,
+		svc:    svc,
+		tokens: tokens,
+		auth:   auth,
+		authz:  authz,
 	}
 }
 
 // SetRoutes sets a fresh middleware stack to configure the handle functions of the UserHTTP and mounts them to the given subrouter.
+// The sign-up, login, token refresh and logout routes are public, the rest of them require a valid access token.
 func (uc UserHTTP) SetRoutes(r chi.Router) {
 	r.Post("/users", uc.create)
-	r.Get("/user", uc.get)
-	r.Get("/users", uc.getAll)
-	r.Get("/users/filter", uc.getFiltered)
-	r.Put("/users", uc.update)
-	r.Delete("/users", uc.delete)
-
 	r.Post("/login", uc.login)
+	r.Post("/token/refresh", uc.refreshToken)
+	r.Post("/logout", uc.logout)
+
+	r.Group(func(r chi.Router) {
+		r.Use(uc.auth.Authenticate)
+		r.With(uc.authz.RequirePermission(entity.PermUsersRead)).Get("/users", uc.getAll)
+		r.With(uc.authz.RequirePermission(entity.PermUsersRead)).Get("/users/filter", uc.getFiltered)
+		r.With(uc.authz.RequirePermission(entity.PermUsersRead)).Get("/users/{id}", uc.getByID)
+		r.Put("/users/{id}", uc.replace)
+		r.Patch("/users/{id}", uc.patch)
+		r.With(uc.authz.RequirePermission(entity.PermUsersDelete)).Delete("/users/{id}", uc.deleteByID)
+		r.With(uc.authz.RequirePermission(entity.PermRolesManage)).Put("/users/{id}/role", uc.changeRoleByID)
+		r.With(uc.authz.RequirePermission(entity.PermUsersRead)).Get("/users/{id}/active", uc.isActiveByID)
+		r.With(uc.authz.RequirePermission(entity.PermUsersUpdate)).Put("/users/{id}/activation", uc.activateByID)
+		r.With(uc.authz.RequirePermission(entity.PermUsersUpdate)).Delete("/users/{id}/activation", uc.deactivateByID)
+		r.Put("/users/{id}/email", uc.changeEmailByID)
+		r.Put("/users/{id}/password", uc.changePasswdByID)
+		r.Post("/logout/all", uc.logoutAll)
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Deprecation(legacyRoutesDeprecatedAt, legacyRoutesSunsetAt))
+			r.With(uc.authz.RequirePermission(entity.PermUsersRead)).Get("/user", uc.get)
+			r.Put("/users", uc.update)
+			r.With(uc.authz.RequirePermission(entity.PermUsersDelete)).Delete("/users", uc.delete)
+			r.With(uc.authz.RequirePermission(entity.PermRolesManage)).Put("/users/role", uc.changeRole)
+			r.With(uc.authz.RequirePermission(entity.PermUsersRead)).Get("/users/active", uc.isActive)
+			r.With(uc.authz.RequirePermission(entity.PermUsersUpdate)).Put("/users/activate", uc.activate)
+			r.With(uc.authz.RequirePermission(entity.PermUsersUpdate)).Put("/users/deactivate", uc.deactivate)
+			r.Put("/users/email", uc.changeEmail)
+			r.Put("/users/password", uc.changePasswd)
+		})
+	})
 }
 
 // create godoc
@@ -111,6 +264,7 @@ func (uc UserHTTP) SetRoutes(r chi.Router) {
 // @Success      200  {object}  basicMessage
 // @Failure      400  {object}  errHTTP
 // @Failure      404  {object}  errHTTP
+// @Failure      409  {object}  errHTTP
 // @Failure      500  {object}  errHTTP
 // @Router       /users [post]
 func (uc UserHTTP) create(w http.ResponseWriter, r *http.Request) {
@@ -134,7 +288,7 @@ func (uc UserHTTP) create(w http.ResponseWriter, r *http.Request) {
 		Passwd:    dto.Passwd,
 	}
 
-	if err := uc.svc.Create(user); err != nil {
+	if err := uc.svc.Create(r.Context(), user); err != nil {
 		errJSON(w, r, err)
 		return
 	}
@@ -151,8 +305,12 @@ func (uc UserHTTP) create(w http.ResponseWriter, r *http.Request) {
 // @Param        id   query     int  true  "User ID"
 // @Success      200  {object}  userResponse
 // @Failure      400  {object}  errHTTP
+// @Failure      401  {object}  errHTTP
+// @Failure      403  {object}  errHTTP
 // @Failure      404  {object}  errHTTP
 // @Failure      500  {object}  errHTTP
+// @Security     BearerAuth
+// @Deprecated
 // @Router       /user [get]
 func (uc UserHTTP) get(w http.ResponseWriter, r *http.Request) {
 	id := r.URL.Query().Get("id")
@@ -165,7 +323,35 @@ func (uc UserHTTP) get(w http.ResponseWriter, r *http.Request) {
 		errJSON(w, r, &ParameterErr{Param: "id", Err: err.Error()})
 		return
 	}
-	user, err := uc.svc.Get(idUint)
+	user, err := uc.svc.Get(r.Context(), idUint)
+	if err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	render.JSON(w, r, parseUserResponse(user))
+}
+
+// getByID godoc
+// @Summary retrieves a user by id
+// @Description  retrieves a user by id
+// @Tags         user
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  userResponse
+// @Failure      400  {object}  errHTTP
+// @Failure      401  {object}  errHTTP
+// @Failure      403  {object}  errHTTP
+// @Failure      404  {object}  errHTTP
+// @Failure      500  {object}  errHTTP
+// @Security     BearerAuth
+// @Router       /users/{id} [get]
+func (uc UserHTTP) getByID(w http.ResponseWriter, r *http.Request) {
+	id, err := userIDParam(r)
+	if err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	user, err := uc.svc.Get(r.Context(), id)
 	if err != nil {
 		errJSON(w, r, err)
 		return
@@ -174,41 +360,61 @@ func (uc UserHTTP) get(w http.ResponseWriter, r *http.Request) {
 }
 
 // getAll godoc
-// @Summary retrieves all users
-// @Description  retrieves all users
+// @Summary retrieves a page of users
+// @Description  retrieves a page of the users matching the filters, the pages are browsed with the offset or the cursors. A filter is given as field=value, or as field[op]=value
+// @Description  where op is one of eq, prefix, contains (first_name, last_name, email, username) or eq, gte, lte (birthday, created_at).
+// @Description  The role and active fields only support eq.
 // @Tags         user
 // @Produce      json
-// @Success      200  {object}  []userResponse
+// @Param        first_name  query     string  false  "First name filter, e.g. first_name[prefix]=jo"
+// @Param        last_name   query     string  false  "Last name filter, e.g. last_name[contains]=ez"
+// @Param        email       query     string  false  "Email filter, e.g. email[contains]=@example.com"
+// @Param        username    query     string  false  "Username filter"
+// @Param        role        query     string  false  "Role filter"
+// @Param        active      query     bool    false  "Active filter"
+// @Param        birthday    query     string  false  "Birthday filter, e.g. birthday[gte]=1990-01-01"
+// @Param        created_at  query     string  false  "Creation date filter, e.g. created_at[lte]=2024-01-31T23:59:59Z"
+// @Param        sort        query     string  false  "Comma separated sort fields, prefixed with - for descending order, e.g. -created_at,last_name"
+// @Param        limit       query     int     false  "Page size, 20 by default and 100 at most"
+// @Param        offset      query     int     false  "Users skipped, it can not be combined with a cursor"
+// @Param        cursor      query     string  false  "Opaque cursor returned as next_cursor or prev_cursor by a previous page"
+// @Success      200  {object}  userListResponse
+// @Header       200  {string}  Link  "RFC 8288 links to the next and previous pages"
 // @Failure      400  {object}  errHTTP
+// @Failure      401  {object}  errHTTP
+// @Failure      403  {object}  errHTTP
 // @Failure      404  {object}  errHTTP
 // @Failure      500  {object}  errHTTP
+// @Security     BearerAuth
 // @Router       /users [get]
 func (uc UserHTTP) getAll(w http.ResponseWriter, r *http.Request) {
-	users, err := uc.svc.GetAll()
+	query, err := parseUserQuery(r.URL.Query())
 	if err != nil {
 		errJSON(w, r, err)
 		return
 	}
-
-	usersResp := make([]userResponse, 0)
-	for _, u := range users {
-		usersResp = append(usersResp, parseUserResponse(u))
-	}
-
-	render.JSON(w, r, usersResp)
+	uc.search(w, r, query)
 }
 
 // getFiltered godoc
-// @Summary retrieves a list of filtered users
-// @Description  retrieves a list of filtered users.
+// @Summary retrieves a page of filtered users
+// @Description  retrieves a page of the users whose field equals the value given. It supports the same filters, sorting and pagination of GET /users.
 // @Tags         user
 // @Produce      json
 // @Param        filter   query     string  true  "Filter Name"
 // @Param        value    query     string  false  "Filter Value"
-// @Success      200  {object}  userResponse
+// @Param        sort     query     string  false  "Comma separated sort fields, prefixed with - for descending order"
+// @Param        limit    query     int     false  "Page size, 20 by default and 100 at most"
+// @Param        offset   query     int     false  "Users skipped, it can not be combined with a cursor"
+// @Param        cursor   query     string  false  "Opaque cursor returned as next_cursor or prev_cursor by a previous page"
+// @Success      200  {object}  userListResponse
+// @Header       200  {string}  Link  "RFC 8288 links to the next and previous pages"
 // @Failure      400  {object}  errHTTP
+// @Failure      401  {object}  errHTTP
+// @Failure      403  {object}  errHTTP
 // @Failure      404  {object}  errHTTP
 // @Failure      500  {object}  errHTTP
+// @Security     BearerAuth
 // @Router       /users/filter [get]
 func (uc UserHTTP) getFiltered(w http.ResponseWriter, r *http.Request) {
 	filter := r.URL.Query().Get("filter")
@@ -221,25 +427,43 @@ func (uc UserHTTP) getFiltered(w http.ResponseWriter, r *http.Request) {
 		errJSON(w, r, &ParameterErr{Param: "value", Err: "filter value empty"})
 		return
 	}
-	users, err := uc.svc.Find(filter, value)
+	query, err := parseUserQuery(r.URL.Query())
 	if err != nil {
 		errJSON(w, r, err)
 		return
 	}
+	field := filter
+	if f, ok := legacyUserFilters[filter]; ok {
+		field = f
+	}
+	query.Filters = append(query.Filters, entity.Filter{Field: field, Op: entity.FilterEq, Value: value})
+	uc.search(w, r, query)
+}
 
-	usersResp := make([]userResponse, 0)
-	for _, u := range users {
-		usersResp = append(usersResp, userResponse{
-			ID:        fmt.Sprintf("%d", u.ID),
-			FirstName: u.FirstName,
-			LastName:  u.LastName,
-			Email:     u.Email,
-			BirthDay:  u.BirthDay.Format(dateFormat),
-			Username:  u.Username,
-		})
+// search renders the page of users matching the query, and links the pages next to it.
+func (uc UserHTTP) search(w http.ResponseWriter, r *http.Request, query entity.UserQuery) {
+	page, err := uc.svc.Search(r.Context(), query, r.URL.Query().Get("cursor"))
+	if err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	if link := pageLinks(r.URL, page.NextCursor, page.PrevCursor); link != "" {
+		w.Header().Set("Link", link)
 	}
 
-	render.JSON(w, r, usersResp)
+	usersResp := make([]userResponse, 0, len(page.Users))
+	for _, u := range page.Users {
+		usersResp = append(usersResp, parseUserResponse(u))
+	}
+
+	render.JSON(w, r, userListResponse{
+		Users:      usersResp,
+		Total:      page.Total,
+		Limit:      page.Limit,
+		Offset:     page.Offset,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+	})
 }
 
 // update godoc
@@ -250,8 +474,12 @@ func (uc UserHTTP) getFiltered(w http.ResponseWriter, r *http.Request) {
 // @Param        request   body     userUpdateRequest  true  "User Update Request"
 // @Success      200  {object}  basicMessage
 // @Failure      400  {object}  errHTTP
+// @Failure      401  {object}  errHTTP
+// @Failure      403  {object}  errHTTP
 // @Failure      404  {object}  errHTTP
 // @Failure      500  {object}  errHTTP
+// @Security     BearerAuth
+// @Deprecated
 // @Router       /users [put]
 func (uc UserHTTP) update(w http.ResponseWriter, r *http.Request) {
 	var dto userUpdateRequest
@@ -264,6 +492,10 @@ func (uc UserHTTP) update(w http.ResponseWriter, r *http.Request) {
 		errJSON(w, r, &PayloadErr{err})
 		return
 	}
+	if err := uc.authz.AuthorizeOwner(r.Context(), idUint, entity.PermUsersUpdate); err != nil {
+		errJSON(w, r, err)
+		return
+	}
 	birthDay, err := time.Parse(dateFormat, dto.BirthDay)
 	if err != nil {
 		errJSON(w, r, &PayloadErr{err})
@@ -275,13 +507,134 @@ func (uc UserHTTP) update(w http.ResponseWriter, r *http.Request) {
 		LastName:  dto.LastName,
 		BirthDay:  birthDay,
 	}
-	if err := uc.svc.Update(userArgs); err != nil {
+	if err := uc.svc.Update(r.Context(), userArgs); err != nil {
 		errJSON(w, r, err)
 		return
 	}
 	render.JSON(w, r, basicMessage{Message: fmt.Sprintf("user %d updated successfully", userArgs.ID)})
 }
 
+// replace godoc
+// @Summary replaces a user
+// @Description  Replaces the first name, last name and birthday of a user, all of them are required.
+// @Tags         user
+// @Produce      json
+// @Param        id        path     int               true  "User ID"
+// @Param        request   body     userWriteRequest  true  "User Replace Request"
+// @Success      200  {object}  basicMessage
+// @Failure      400  {object}  errHTTP
+// @Failure      401  {object}  errHTTP
+// @Failure      403  {object}  errHTTP
+// @Failure      404  {object}  errHTTP
+// @Failure      500  {object}  errHTTP
+// @Security     BearerAuth
+// @Router       /users/{id} [put]
+func (uc UserHTTP) replace(w http.ResponseWriter, r *http.Request) {
+	id, err := userIDParam(r)
+	if err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	if err := uc.authz.AuthorizeOwner(r.Context(), id, entity.PermUsersUpdate); err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	var dto userWriteRequest
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		errJSON(w, r, &PayloadErr{err})
+		return
+	}
+	if err := validateUserReplace(dto); err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	uc.write(w, r, id, dto)
+}
+
+// patch godoc
+// @Summary partially updates a user
+// @Description  Updates the first name, last name or birthday of a user, the fields omitted are kept.
+// @Tags         user
+// @Produce      json
+// @Param        id        path     int               true  "User ID"
+// @Param        request   body     userWriteRequest  true  "User Patch Request"
+// @Success      200  {object}  basicMessage
+// @Failure      400  {object}  errHTTP
+// @Failure      401  {object}  errHTTP
+// @Failure      403  {object}  errHTTP
+// @Failure      404  {object}  errHTTP
+// @Failure      500  {object}  errHTTP
+// @Security     BearerAuth
+// @Router       /users/{id} [patch]
+func (uc UserHTTP) patch(w http.ResponseWriter, r *http.Request) {
+	id, err := userIDParam(r)
+	if err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	if err := uc.authz.AuthorizeOwner(r.Context(), id, entity.PermUsersUpdate); err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	var dto userWriteRequest
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		errJSON(w, r, &PayloadErr{err})
+		return
+	}
+	uc.write(w, r, id, dto)
+}
+
+// write updates the user with the non-empty fields of the request.
+func (uc UserHTTP) write(w http.ResponseWriter, r *http.Request, id uint64, dto userWriteRequest) {
+	var birthDay time.Time
+	if dto.BirthDay != "" {
+		var err error
+		birthDay, err = time.Parse(dateFormat, dto.BirthDay)
+		if err != nil {
+			errJSON(w, r, &PayloadErr{err})
+			return
+		}
+	}
+	userArgs := service.UserUpdateArgs{
+		ID:        id,
+		FirstName: dto.FirstName,
+		LastName:  dto.LastName,
+		BirthDay:  birthDay,
+	}
+	if err := uc.svc.Update(r.Context(), userArgs); err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	render.JSON(w, r, basicMessage{Message: fmt.Sprintf("user %d updated successfully", id)})
+}
+
+// deleteByID godoc
+// @Summary deletes a user by ID
+// @Description  deletes a user by ID
+// @Tags         user
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  basicMessage
+// @Failure      400  {object}  errHTTP
+// @Failure      401  {object}  errHTTP
+// @Failure      403  {object}  errHTTP
+// @Failure      404  {object}  errHTTP
+// @Failure      500  {object}  errHTTP
+// @Security     BearerAuth
+// @Router       /users/{id} [delete]
+func (uc UserHTTP) deleteByID(w http.ResponseWriter, r *http.Request) {
+	id, err := userIDParam(r)
+	if err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	if err := uc.svc.Delete(r.Context(), id); err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	render.JSON(w, r, basicMessage{Message: fmt.Sprintf("user %d deleted successfully", id)})
+}
+
 // delete godoc
 // @Summary deletes a user by ID
 // @Description  retrieves a list of filtered users.
@@ -290,8 +643,12 @@ func (uc UserHTTP) update(w http.ResponseWriter, r *http.Request) {
 // @Param        id   query     int  true  "User ID"
 // @Success      200  {object}  basicMessage
 // @Failure      400  {object}  errHTTP
+// @Failure      401  {object}  errHTTP
+// @Failure      403  {object}  errHTTP
 // @Failure      404  {object}  errHTTP
 // @Failure      500  {object}  errHTTP
+// @Security     BearerAuth
+// @Deprecated
 // @Router       /users [delete]
 func (uc UserHTTP) delete(w http.ResponseWriter, r *http.Request) {
 	id := r.URL.Query().Get("id")
@@ -304,22 +661,427 @@ func (uc UserHTTP) delete(w http.ResponseWriter, r *http.Request) {
 		errJSON(w, r, &ParameterErr{Param: "id", Err: err.Error()})
 		return
 	}
-	if err := uc.svc.Delete(idUint); err != nil {
+	if err := uc.svc.Delete(r.Context(), idUint); err != nil {
 		errJSON(w, r, err)
 		return
 	}
 	render.JSON(w, r, basicMessage{Message: fmt.Sprintf("user %d deleted successfully", idUint)})
 }
 
+// changeRoleByID godoc
+// @Summary changes the role of a user
+// @Description  Assigns a role of the permission matrix to a user, an unknown role is rejected as an invalid input
+// @Tags         user
+// @Produce      json
+// @Param        id        path     int                   true  "User ID"
+// @Param        request   body     userRoleWriteRequest  true  "User Role Request"
+// @Success      200  {object}  basicMessage
+// @Failure      400  {object}  errHTTP
+// @Failure      401  {object}  errHTTP
+// @Failure      403  {object}  errHTTP
+// @Failure      404  {object}  errHTTP
+// @Failure      500  {object}  errHTTP
+// @Security     BearerAuth
+// @Router       /users/{id}/role [put]
+func (uc UserHTTP) changeRoleByID(w http.ResponseWriter, r *http.Request) {
+	id, err := userIDParam(r)
+	if err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	var dto userRoleWriteRequest
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		errJSON(w, r, &PayloadErr{err})
+		return
+	}
+	uc.setRole(w, r, id, dto.Role)
+}
+
+// changeRole godoc
+// @Summary changes the role of a user
+// @Description  Assigns a role of the permission matrix to a user, an unknown role is rejected as an invalid input
+// @Tags         user
+// @Produce      json
+// @Param        request   body     userRoleRequest  true  "User Role Request"
+// @Success      200  {object}  basicMessage
+// @Failure      400  {object}  errHTTP
+// @Failure      401  {object}  errHTTP
+// @Failure      403  {object}  errHTTP
+// @Failure      404  {object}  errHTTP
+// @Failure      500  {object}  errHTTP
+// @Security     BearerAuth
+// @Deprecated
+// @Router       /users/role [put]
+func (uc UserHTTP) changeRole(w http.ResponseWriter, r *http.Request) {
+	var dto userRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		errJSON(w, r, &PayloadErr{err})
+		return
+	}
+	idUint, err := strconv.ParseUint(dto.ID, 10, 64)
+	if err != nil {
+		errJSON(w, r, &PayloadErr{err})
+		return
+	}
+	uc.setRole(w, r, idUint, dto.Role)
+}
+
+// setRole assigns the role to the user.
+func (uc UserHTTP) setRole(w http.ResponseWriter, r *http.Request, id uint64, role string) {
+	if err := uc.svc.ChangeRole(r.Context(), id, role); err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	render.JSON(w, r, basicMessage{Message: fmt.Sprintf("user %d role changed to %v successfully", id, role)})
+}
+
+// isActiveByID godoc
+// @Summary retrieves the active status of a user
+// @Description  retrieves whether a user is active or not
+// @Tags         user
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  userActiveResponse
+// @Failure      400  {object}  errHTTP
+// @Failure      401  {object}  errHTTP
+// @Failure      403  {object}  errHTTP
+// @Failure      404  {object}  errHTTP
+// @Failure      500  {object}  errHTTP
+// @Security     BearerAuth
+// @Router       /users/{id}/active [get]
+func (uc UserHTTP) isActiveByID(w http.ResponseWriter, r *http.Request) {
+	id, err := userIDParam(r)
+	if err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	uc.renderActive(w, r, id)
+}
+
+// isActive godoc
+// @Summary retrieves the active status of a user
+// @Description  retrieves whether a user is active or not
+// @Tags         user
+// @Produce      json
+// @Param        id   query     int  true  "User ID"
+// @Success      200  {object}  userActiveResponse
+// @Failure      400  {object}  errHTTP
+// @Failure      401  {object}  errHTTP
+// @Failure      403  {object}  errHTTP
+// @Failure      404  {object}  errHTTP
+// @Failure      500  {object}  errHTTP
+// @Security     BearerAuth
+// @Deprecated
+// @Router       /users/active [get]
+func (uc UserHTTP) isActive(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		errJSON(w, r, &ParameterErr{Param: "id", Err: "empty value"})
+		return
+	}
+	idUint, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		errJSON(w, r, &ParameterErr{Param: "id", Err: err.Error()})
+		return
+	}
+	uc.renderActive(w, r, idUint)
+}
+
+// renderActive writes whether the user is active.
+func (uc UserHTTP) renderActive(w http.ResponseWriter, r *http.Request, id uint64) {
+	active, err := uc.svc.IsActive(r.Context(), id)
+	if err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	render.JSON(w, r, userActiveResponse{ID: strconv.FormatUint(id, 10), Active: active})
+}
+
+// activateByID godoc
+// @Summary activates a user
+// @Description  Activates a user by ID
+// @Tags         user
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  basicMessage
+// @Failure      400  {object}  errHTTP
+// @Failure      401  {object}  errHTTP
+// @Failure      403  {object}  errHTTP
+// @Failure      404  {object}  errHTTP
+// @Failure      500  {object}  errHTTP
+// @Security     BearerAuth
+// @Router       /users/{id}/activation [put]
+func (uc UserHTTP) activateByID(w http.ResponseWriter, r *http.Request) {
+	id, err := userIDParam(r)
+	if err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	uc.setActive(w, r, id, true)
+}
+
+// deactivateByID godoc
+// @Summary deactivates a user
+// @Description  Deactivates a user by ID
+// @Tags         user
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  basicMessage
+// @Failure      400  {object}  errHTTP
+// @Failure      401  {object}  errHTTP
+// @Failure      403  {object}  errHTTP
+// @Failure      404  {object}  errHTTP
+// @Failure      500  {object}  errHTTP
+// @Security     BearerAuth
+// @Router       /users/{id}/activation [delete]
+func (uc UserHTTP) deactivateByID(w http.ResponseWriter, r *http.Request) {
+	id, err := userIDParam(r)
+	if err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	uc.setActive(w, r, id, false)
+}
+
+// activate godoc
+// @Summary activates a user
+// @Description  Activates a user by ID
+// @Tags         user
+// @Produce      json
+// @Param        request   body     userIDRequest  true  "User ID Request"
+// @Success      200  {object}  basicMessage
+// @Failure      400  {object}  errHTTP
+// @Failure      401  {object}  errHTTP
+// @Failure      403  {object}  errHTTP
+// @Failure      404  {object}  errHTTP
+// @Failure      500  {object}  errHTTP
+// @Security     BearerAuth
+// @Deprecated
+// @Router       /users/activate [put]
+func (uc UserHTTP) activate(w http.ResponseWriter, r *http.Request) {
+	var dto userIDRequest
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		errJSON(w, r, &PayloadErr{err})
+		return
+	}
+	idUint, err := strconv.ParseUint(dto.ID, 10, 64)
+	if err != nil {
+		errJSON(w, r, &PayloadErr{err})
+		return
+	}
+	uc.setActive(w, r, idUint, true)
+}
+
+// deactivate godoc
+// @Summary deactivates a user
+// @Description  Deactivates a user by ID
+// @Tags         user
+// @Produce      json
+// @Param        request   body     userIDRequest  true  "User ID Request"
+// @Success      200  {object}  basicMessage
+// @Failure      400  {object}  errHTTP
+// @Failure      401  {object}  errHTTP
+// @Failure      403  {object}  errHTTP
+// @Failure      404  {object}  errHTTP
+// @Failure      500  {object}  errHTTP
+// @Security     BearerAuth
+// @Deprecated
+// @Router       /users/deactivate [put]
+func (uc UserHTTP) deactivate(w http.ResponseWriter, r *http.Request) {
+	var dto userIDRequest
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		errJSON(w, r, &PayloadErr{err})
+		return
+	}
+	idUint, err := strconv.ParseUint(dto.ID, 10, 64)
+	if err != nil {
+		errJSON(w, r, &PayloadErr{err})
+		return
+	}
+	uc.setActive(w, r, idUint, false)
+}
+
+// setActive activates or deactivates the user.
+func (uc UserHTTP) setActive(w http.ResponseWriter, r *http.Request, id uint64, active bool) {
+	if !active {
+		if err := uc.svc.Deactivate(r.Context(), id); err != nil {
+			errJSON(w, r, err)
+			return
+		}
+		render.JSON(w, r, basicMessage{Message: fmt.Sprintf("user %d deactivated successfully", id)})
+		return
+	}
+	if err := uc.svc.Activate(r.Context(), id); err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	render.JSON(w, r, basicMessage{Message: fmt.Sprintf("user %d activated successfully", id)})
+}
+
+// changeEmailByID godoc
+// @Summary changes the email of a user
+// @Description  Changes the email of a user. Only the user itself or a user allowed to update users can change it.
+// @Tags         user
+// @Produce      json
+// @Param        id        path     int                    true  "User ID"
+// @Param        request   body     userEmailWriteRequest  true  "User Email Request"
+// @Success      200  {object}  basicMessage
+// @Failure      400  {object}  errHTTP
+// @Failure      401  {object}  errHTTP
+// @Failure      403  {object}  errHTTP
+// @Failure      404  {object}  errHTTP
+// @Failure      409  {object}  errHTTP
+// @Failure      500  {object}  errHTTP
+// @Security     BearerAuth
+// @Router       /users/{id}/email [put]
+func (uc UserHTTP) changeEmailByID(w http.ResponseWriter, r *http.Request) {
+	id, err := userIDParam(r)
+	if err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	var dto userEmailWriteRequest
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		errJSON(w, r, &PayloadErr{err})
+		return
+	}
+	uc.setEmail(w, r, id, dto.Email)
+}
+
+// changeEmail godoc
+// @Summary changes the email of a user
+// @Description  Changes the email of a user. Only the user itself or a user allowed to update users can change it.
+// @Tags         user
+// @Produce      json
+// @Param        request   body     userEmailRequest  true  "User Email Request"
+// @Success      200  {object}  basicMessage
+// @Failure      400  {object}  errHTTP
+// @Failure      401  {object}  errHTTP
+// @Failure      403  {object}  errHTTP
+// @Failure      404  {object}  errHTTP
+// @Failure      409  {object}  errHTTP
+// @Failure      500  {object}  errHTTP
+// @Security     BearerAuth
+// @Deprecated
+// @Router       /users/email [put]
+func (uc UserHTTP) changeEmail(w http.ResponseWriter, r *http.Request) {
+	var dto userEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		errJSON(w, r, &PayloadErr{err})
+		return
+	}
+	idUint, err := strconv.ParseUint(dto.ID, 10, 64)
+	if err != nil {
+		errJSON(w, r, &PayloadErr{err})
+		return
+	}
+	uc.setEmail(w, r, idUint, dto.Email)
+}
+
+// setEmail changes the email of the user, once the authenticated user is allowed to.
+func (uc UserHTTP) setEmail(w http.ResponseWriter, r *http.Request, id uint64, email string) {
+	if err := uc.authz.AuthorizeOwner(r.Context(), id, entity.PermUsersUpdate); err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	if err := uc.svc.ChangeEmail(r.Context(), id, email); err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	render.JSON(w, r, basicMessage{Message: fmt.Sprintf("user %d email changed successfully", id)})
+}
+
+// changePasswdByID godoc
+// @Summary changes the password of a user
+// @Description  Changes the password of a user once the current password is verified, and logs every session of the user out.
+// @Description  Only the user itself or a user allowed to update users can change it.
+// @Description  The password stays changed when the sessions can't be logged out, the response message then tells they may still be active.
+// @Tags         user
+// @Produce      json
+// @Param        id        path     int                     true  "User ID"
+// @Param        request   body     userPasswdWriteRequest  true  "User Password Request"
+// @Success      200  {object}  basicMessage
+// @Failure      400  {object}  errHTTP
+// @Failure      401  {object}  errHTTP
+// @Failure      403  {object}  errHTTP
+// @Failure      404  {object}  errHTTP
+// @Failure      500  {object}  errHTTP
+// @Security     BearerAuth
+// @Router       /users/{id}/password [put]
+func (uc UserHTTP) changePasswdByID(w http.ResponseWriter, r *http.Request) {
+	id, err := userIDParam(r)
+	if err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	var dto userPasswdWriteRequest
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		errJSON(w, r, &PayloadErr{err})
+		return
+	}
+	uc.setPasswd(w, r, id, dto.CurrentPasswd, dto.NewPasswd)
+}
+
+// changePasswd godoc
+// @Summary changes the password of a user
+// @Description  Changes the password of a user once the current password is verified, and logs every session of the user out.
+// @Description  Only the user itself or a user allowed to update users can change it.
+// @Description  The password stays changed when the sessions can't be logged out, the response message then tells they may still be active.
+// @Tags         user
+// @Produce      json
+// @Param        request   body     userPasswdRequest  true  "User Password Request"
+// @Success      200  {object}  basicMessage
+// @Failure      400  {object}  errHTTP
+// @Failure      401  {object}  errHTTP
+// @Failure      403  {object}  errHTTP
+// @Failure      404  {object}  errHTTP
+// @Failure      500  {object}  errHTTP
+// @Security     BearerAuth
+// @Deprecated
+// @Router       /users/password [put]
+func (uc UserHTTP) changePasswd(w http.ResponseWriter, r *http.Request) {
+	var dto userPasswdRequest
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		errJSON(w, r, &PayloadErr{err})
+		return
+	}
+	idUint, err := strconv.ParseUint(dto.ID, 10, 64)
+	if err != nil {
+		errJSON(w, r, &PayloadErr{err})
+		return
+	}
+	uc.setPasswd(w, r, idUint, dto.CurrentPasswd, dto.NewPasswd)
+}
+
+// setPasswd changes the password of the user once the authenticated user is allowed to, and logs every session of the user out.
+func (uc UserHTTP) setPasswd(w http.ResponseWriter, r *http.Request, id uint64, currentPasswd, passwd string) {
+	if err := uc.authz.AuthorizeOwner(r.Context(), id, entity.PermUsersUpdate); err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	if err := uc.svc.ChangePasswd(r.Context(), id, currentPasswd, passwd); err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	// Every session is logged out, so a stolen refresh token doesn't survive the password change.
+	// The password is already changed when the revocation fails, so it is still answered as a success.
+	if err := uc.tokens.RevokeAll(r.Context(), id); err != nil {
+		logger.FromContext(r.Context()).Log().Error().Ctx(r.Context()).Err(err).Uint64("user_id", id).Msg("sessions revocation failed")
+		render.JSON(w, r, basicMessage{Message: fmt.Sprintf("user %d password changed successfully, its sessions may still be active", id)})
+		return
+	}
+	render.JSON(w, r, basicMessage{Message: fmt.Sprintf("user %d password changed successfully", id)})
+}
+
 // login godoc
 // @Summary authenticates a user
-// @Description  authenticates a user
+// @Description  authenticates a user and issues a signed access token
 // @Tags         user
 // @Produce      json
 // @Param        request 	body 		userLoginRequest  true  "Login Request"
 // @Success      200 		{object} 	userLoginResponse
 // @Failure      400		{object} 	errHTTP
-// @Failure      404		{object} 	errHTTP
+// @Failure      401		{object} 	errHTTP
 // @Failure      500		{object} 	errHTTP
 // @Router       /login [post]
 func (uc UserHTTP) login(w http.ResponseWriter, r *http.Request) {
@@ -329,18 +1091,137 @@ func (uc UserHTTP) login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := uc.svc.ValidateLogin(dto.Username, dto.Passwd)
+	user, err := uc.svc.ValidateLogin(r.Context(), dto.Username, dto.Passwd)
+	if err != nil {
+		errJSON(w, r, err)
+		return
+	}
+
+	token, expiresAt, err := uc.auth.IssueAccessToken(user.ID, user.Username, user.Role)
+	if err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	refresh, err := uc.tokens.Issue(r.Context(), user.ID)
 	if err != nil {
 		errJSON(w, r, err)
 		return
 	}
 
 	render.JSON(w, r, userLoginResponse{
-		ID:        fmt.Sprintf("%d", user.ID),
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Email:     user.Email,
-		Username:  user.Username,
-		LastLogin: user.LastLogin.String(),
+		ID:          fmt.Sprintf("%d", user.ID),
+		FirstName:   user.FirstName,
+		LastName:    user.LastName,
+		Email:       user.Email,
+		Username:    user.Username,
+		LastLogin:   user.LastLogin.String(),
+		AccessToken: token,
+		TokenType:   tokenType,
+		ExpiresAt:   expiresAt.Format(time.RFC3339),
+
+		RefreshToken:     refresh.Token,
+		RefreshExpiresAt: refresh.ExpiresAt.Format(time.RFC3339),
 	})
 }
+
+// refreshToken godoc
+// @Summary refreshes the access token
+// @Description  exchanges a refresh token for a new access and refresh token pair. The refresh token given is rotated and can not be used again.
+// @Description  The deactivated users can not refresh their tokens.
+// @Tags         user
+// @Produce      json
+// @Param        request 	body 		tokenRequest  true  "Refresh Token Request"
+// @Success      200 		{object} 	tokenResponse
+// @Failure      400		{object} 	errHTTP
+// @Failure      401		{object} 	errHTTP
+// @Failure      500		{object} 	errHTTP
+// @Router       /token/refresh [post]
+func (uc UserHTTP) refreshToken(w http.ResponseWriter, r *http.Request) {
+	var dto tokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		errJSON(w, r, &PayloadErr{err})
+		return
+	}
+
+	// The refresh token is only exchanged once the access token is issued, so a failure doesn't spend it.
+	userID, err := uc.tokens.Verify(r.Context(), dto.RefreshToken)
+	if err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	user, err := uc.svc.Get(r.Context(), userID)
+	if err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	if !user.Active {
+		errJSON(w, r, &service.UnauthorizedErr{Err: service.ErrUserInactive})
+		return
+	}
+	token, expiresAt, err := uc.auth.IssueAccessToken(user.ID, user.Username, user.Role)
+	if err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	refresh, err := uc.tokens.Rotate(r.Context(), dto.RefreshToken)
+	if err != nil {
+		errJSON(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, tokenResponse{
+		AccessToken:      token,
+		TokenType:        tokenType,
+		ExpiresAt:        expiresAt.Format(time.RFC3339),
+		RefreshToken:     refresh.Token,
+		RefreshExpiresAt: refresh.ExpiresAt.Format(time.RFC3339),
+	})
+}
+
+// logout godoc
+// @Summary logs a session out
+// @Description  revokes the refresh token given and every token rotated from the same login
+// @Tags         user
+// @Produce      json
+// @Param        request 	body 		tokenRequest  true  "Logout Request"
+// @Success      200 		{object} 	basicMessage
+// @Failure      400		{object} 	errHTTP
+// @Failure      401		{object} 	errHTTP
+// @Failure      500		{object} 	errHTTP
+// @Router       /logout [post]
+func (uc UserHTTP) logout(w http.ResponseWriter, r *http.Request) {
+	var dto tokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		errJSON(w, r, &PayloadErr{err})
+		return
+	}
+	if err := uc.tokens.Revoke(r.Context(), dto.RefreshToken); err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	render.JSON(w, r, basicMessage{Message: "logged out successfully"})
+}
+
+// logoutAll godoc
+// @Summary logs every session out
+// @Description  revokes every refresh token issued for the authenticated user
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 		{object} 	basicMessage
+// @Failure      400		{object} 	errHTTP
+// @Failure      401		{object} 	errHTTP
+// @Failure      500		{object} 	errHTTP
+// @Router       /logout/all [post]
+func (uc UserHTTP) logoutAll(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		errJSON(w, r, &service.UnauthorizedErr{Err: service.ErrTokenInvalid})
+		return
+	}
+	if err := uc.tokens.RevokeAll(r.Context(), userID); err != nil {
+		errJSON(w, r, err)
+		return
+	}
+	render.JSON(w, r, basicMessage{Message: "logged out from all sessions successfully"})
+}
@@ -2,25 +2,36 @@ package controller
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/wizeline/CA-Microservices-Go/internal/controller/mocks"
 	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+	"github.com/wizeline/CA-Microservices-Go/internal/middleware"
 	"github.com/wizeline/CA-Microservices-Go/internal/repository"
 	"github.com/wizeline/CA-Microservices-Go/internal/service"
 
+	"github.com/go-chi/chi"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
-// We ensure the UserSvc mock object satisfies the UserService dependency signature.
-var _ UserService = &mocks.UserSvc{}
+// We ensure the mock objects satisfy the UserHTTP dependencies signature.
+var (
+	_ UserService   = &mocks.UserSvc{}
+	_ TokenService  = &mocks.TokenSvc{}
+	_ Authenticator = &mocks.Authenticator{}
+	_ Authorizer    = &mocks.Authorizer{}
+)
 
 func TestUserControlller_create(t *testing.T) {
 	type svc struct {
@@ -62,6 +73,31 @@ func TestUserControlller_create(t *testing.T) {
 				Message: "invalid payload: unexpected EOF",
 			},
 		},
+		{
+			name: "Duplicated email",
+			svc: svc{
+				args: service.UserCreateArgs{
+					FirstName: "foo",
+					LastName:  "baz",
+					Email:     "foo@example.com",
+					BirthDay:  time.Date(1990, time.December, 5, 0, 0, 0, 0, time.UTC),
+					Username:  "foouser",
+					Passwd:    "foopasswd",
+				},
+				err: &repository.ConflictErr{Entity: "user", Field: "email", Constraint: "users_email_key", Err: repository.ErrDuplicated},
+			},
+			httpReq: httpRequestTest{
+				payload: []byte(`{"first_name": "foo","last_name": "baz","email": "foo@example.com", "birthday":"1990-12-05", "username": "foouser","password": "foopasswd"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusConflict,
+			},
+			err: errHTTP{
+				Code:    http.StatusConflict,
+				Status:  repoConflictStatus,
+				Message: "user conflict on field email: already exists",
+			},
+		},
 		{
 			name: "Created",
 			svc: svc{
@@ -87,8 +123,8 @@ func TestUserControlller_create(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			mockSvc := &mocks.UserSvc{}
-			mockSvc.On("Create", test.svc.args).Return(test.svc.err)
-			ctrl := NewUserHTTP(mockSvc)
+			mockSvc.On("Create", mock.Anything, test.svc.args).Return(test.svc.err)
+			ctrl := NewUserHTTP(mockSvc, &mocks.TokenSvc{}, &mocks.Authenticator{}, &mocks.Authorizer{})
 
 			req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewBuffer(test.httpReq.payload))
 			rec := httptest.NewRecorder()
@@ -175,7 +211,7 @@ func TestUserController_get(t *testing.T) {
 			},
 			httpResp: httpResponseTest{
 				code: http.StatusOK,
-				body: "{\"id\":\"1\",\"first_name\":\"foo\",\"last_name\":\"baz\",\"email\":\"\",\"birthday\":\"0001-01-01\",\"username\":\"\"}\n",
+				body: "{\"id\":\"1\",\"first_name\":\"foo\",\"last_name\":\"baz\",\"email\":\"\",\"birthday\":\"0001-01-01\",\"username\":\"\",\"role\":\"\"}\n",
 			},
 			err: errHTTP{},
 		},
@@ -183,8 +219,8 @@ func TestUserController_get(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockSvc := &mocks.UserSvc{}
-			mockSvc.On("Get", tt.svc.id).Return(tt.svc.resp.user, tt.svc.resp.err)
-			ctrl := NewUserHTTP(mockSvc)
+			mockSvc.On("Get", mock.Anything, tt.svc.id).Return(tt.svc.resp.user, tt.svc.resp.err)
+			ctrl := NewUserHTTP(mockSvc, &mocks.TokenSvc{}, &mocks.Authenticator{}, &mocks.Authorizer{})
 
 			req := httptest.NewRequest(http.MethodGet, "/users?id="+tt.httpReq.params["id"], nil)
 			rec := httptest.NewRecorder()
@@ -204,21 +240,37 @@ func TestUserController_get(t *testing.T) {
 }
 
 func TestUserController_getAll(t *testing.T) {
-	type svcResp struct {
-		users []service.UserResponse
-		err   error
+	type svc struct {
+		query  entity.UserQuery
+		cursor string
+		resp   service.UserSearchResponse
+		err    error
 	}
 	tests := []struct {
 		name     string
-		svcResp  svcResp
+		target   string
+		svc      svc
 		httpResp httpResponseTest
+		link     string
 		err      errHTTP
 	}{
 		{
-			name: "Repository error",
-			svcResp: svcResp{
-				users: nil,
-				err:   &repository.Err{Err: errors.New("some repo error")},
+			name:   "Bad limit",
+			target: "/users?limit=ten",
+			httpResp: httpResponseTest{
+				code: http.StatusBadRequest,
+			},
+			err: errHTTP{
+				Code:    http.StatusBadRequest,
+				Status:  ctrlParamErrStatus,
+				Message: "invalid limit parameter: strconv.Atoi: parsing \"ten\": invalid syntax",
+			},
+		},
+		{
+			name:   "Repository error",
+			target: "/users",
+			svc: svc{
+				err: &repository.Err{Err: errors.New("some repo error")},
 			},
 			httpResp: httpResponseTest{
 				code: http.StatusInternalServerError,
@@ -226,61 +278,105 @@ func TestUserController_getAll(t *testing.T) {
 			err: errHTTP{
 				Code:    http.StatusInternalServerError,
 				Status:  repoErrStatus,
-				Message: "repository: some repo error",
+				Message: "Internal Server Error",
 			},
 		},
 		{
-			name: "Service error",
-			svcResp: svcResp{
-				users: nil,
-				err:   &service.Err{Err: errors.New("some svc error")},
+			name:   "Service error",
+			target: "/users?foo=bar",
+			svc: svc{
+				query: entity.UserQuery{
+					Filters: []entity.Filter{{Field: "foo", Op: entity.FilterEq, Value: "bar"}},
+				},
+				err: &service.InvalidFilterErr{Filter: "foo", Err: service.ErrNotSupported},
 			},
 			httpResp: httpResponseTest{
 				code: http.StatusBadRequest,
 			},
 			err: errHTTP{
 				Code:    http.StatusBadRequest,
-				Status:  svcErrStatus,
-				Message: "service: some svc error",
+				Status:  svcInvalidErrStatus,
+				Message: "invalid filter \"foo\" : not supported",
 			},
 		},
 		{
-			name: "Valid with no records",
-			svcResp: svcResp{
-				users: make([]service.UserResponse, 0),
-				err:   nil,
+			name:   "Valid with no records",
+			target: "/users",
+			svc: svc{
+				resp: service.UserSearchResponse{
+					Users: make([]service.UserResponse, 0),
+					Limit: 20,
+				},
 			},
 			httpResp: httpResponseTest{
 				code: http.StatusOK,
-				body: "[]\n",
+				body: "{\"users\":[],\"total\":0,\"limit\":20,\"offset\":0,\"next_cursor\":\"\",\"prev_cursor\":\"\"}\n",
 			},
-			err: errHTTP{},
 		},
 		{
-			name: "Valid with records",
-			svcResp: svcResp{
-				users: []service.UserResponse{
-					{ID: 1, FirstName: "foo"},
-					{ID: 2, FirstName: "bar"},
-					{ID: 3, FirstName: "baz"},
+			name:   "Valid with records",
+			target: "/users?first_name[prefix]=ba&active=true&birthday[gte]=1990-01-01&sort=-created_at,last_name&limit=2&offset=1",
+			svc: svc{
+				query: entity.UserQuery{
+					Filters: []entity.Filter{
+						{Field: "active", Op: entity.FilterEq, Value: "true"},
+						{Field: "birthday", Op: entity.FilterGte, Value: "1990-01-01"},
+						{Field: "first_name", Op: entity.FilterPrefix, Value: "ba"},
+					},
+					Sort: []entity.Sort{
+						{Field: "created_at", Desc: true},
+						{Field: "last_name"},
+					},
+					Limit:  2,
+					Offset: 1,
+				},
+				resp: service.UserSearchResponse{
+					Users: []service.UserResponse{
+						{ID: 2, FirstName: "bar"},
+						{ID: 3, FirstName: "baz"},
+					},
+					Total:  3,
+					Limit:  2,
+					Offset: 1,
 				},
-				err: nil,
 			},
 			httpResp: httpResponseTest{
 				code: http.StatusOK,
-				body: "[{\"id\":\"1\",\"first_name\":\"foo\",\"last_name\":\"\",\"email\":\"\",\"birthday\":\"0001-01-01\",\"username\":\"\"},{\"id\":\"2\",\"first_name\":\"bar\",\"last_name\":\"\",\"email\":\"\",\"birthday\":\"0001-01-01\",\"username\":\"\"},{\"id\":\"3\",\"first_name\":\"baz\",\"last_name\":\"\",\"email\":\"\",\"birthday\":\"0001-01-01\",\"username\":\"\"}]\n",
+				body: "{\"users\":[{\"id\":\"2\",\"first_name\":\"bar\",\"last_name\":\"\",\"email\":\"\",\"birthday\":\"0001-01-01\",\"username\":\"\",\"role\":\"\"},{\"id\":\"3\",\"first_name\":\"baz\",\"last_name\":\"\",\"email\":\"\",\"birthday\":\"0001-01-01\",\"username\":\"\",\"role\":\"\"}],\"total\":3,\"limit\":2,\"offset\":1,\"next_cursor\":\"\",\"prev_cursor\":\"\"}\n",
 			},
-			err: errHTTP{},
+		},
+		{
+			name:   "Cursor page",
+			target: "/users?role=admin&limit=1&cursor=c2",
+			svc: svc{
+				query: entity.UserQuery{
+					Filters: []entity.Filter{{Field: "role", Op: entity.FilterEq, Value: "admin"}},
+					Limit:   1,
+				},
+				cursor: "c2",
+				resp: service.UserSearchResponse{
+					Users:      []service.UserResponse{{ID: 2, FirstName: "bar"}},
+					Total:      3,
+					Limit:      1,
+					NextCursor: "c3",
+					PrevCursor: "c1",
+				},
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusOK,
+				body: "{\"users\":[{\"id\":\"2\",\"first_name\":\"bar\",\"last_name\":\"\",\"email\":\"\",\"birthday\":\"0001-01-01\",\"username\":\"\",\"role\":\"\"}],\"total\":3,\"limit\":1,\"offset\":0,\"next_cursor\":\"c3\",\"prev_cursor\":\"c1\"}\n",
+			},
+			link: `</users?cursor=c3&limit=1&role=admin>; rel="next", </users?cursor=c1&limit=1&role=admin>; rel="prev"`,
 		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			mockSvc := &mocks.UserSvc{}
-			mockSvc.On("GetAll").Return(test.svcResp.users, test.svcResp.err)
-			ctrl := NewUserHTTP(mockSvc)
+			mockSvc.On("Search", mock.Anything, test.svc.query, test.svc.cursor).Return(test.svc.resp, test.svc.err)
+			ctrl := NewUserHTTP(mockSvc, &mocks.TokenSvc{}, &mocks.Authenticator{}, &mocks.Authorizer{})
 
-			req := httptest.NewRequest(http.MethodGet, "/users", nil)
+			req := httptest.NewRequest(http.MethodGet, test.target, nil)
 			rec := httptest.NewRecorder()
 
 			ctrl.getAll(rec, req)
@@ -293,22 +389,17 @@ func TestUserController_getAll(t *testing.T) {
 				return
 			}
 			assert.Equal(t, test.httpResp.body, rec.Body.String())
+			assert.Equal(t, test.link, rec.Header().Get("Link"))
 		})
 	}
 }
 
 func TestUserController_getFiltered(t *testing.T) {
-	type svcArgs struct {
-		filter string
-		value  string
-	}
-	type svcResp struct {
-		users []entity.User
-		err   error
-	}
 	type svc struct {
-		args svcArgs
-		resp svcResp
+		query  entity.UserQuery
+		cursor string
+		resp   service.UserSearchResponse
+		err    error
 	}
 	tests := []struct {
 		name     string
@@ -319,13 +410,6 @@ func TestUserController_getFiltered(t *testing.T) {
 	}{
 		{
 			name: "Filter empty",
-			svc: svc{
-				args: svcArgs{filter: "", value: ""},
-				resp: svcResp{
-					users: nil,
-					err:   nil,
-				},
-			},
 			httpReq: httpRequestTest{
 				params: map[string]string{
 					"filter": "",
@@ -343,13 +427,6 @@ func TestUserController_getFiltered(t *testing.T) {
 		},
 		{
 			name: "Filter value empty",
-			svc: svc{
-				args: svcArgs{filter: "", value: ""},
-				resp: svcResp{
-					users: nil,
-					err:   nil,
-				},
-			},
 			httpReq: httpRequestTest{
 				params: map[string]string{
 					"filter": "foo-filter",
@@ -368,11 +445,10 @@ func TestUserController_getFiltered(t *testing.T) {
 		{
 			name: "Service error",
 			svc: svc{
-				args: svcArgs{filter: "foo-filter", value: "foo-value"},
-				resp: svcResp{
-					users: nil,
-					err:   &service.Err{Err: errors.New("some svc error")},
+				query: entity.UserQuery{
+					Filters: []entity.Filter{{Field: "foo-filter", Op: entity.FilterEq, Value: "foo-value"}},
 				},
+				err: &service.Err{Err: errors.New("some svc error")},
 			},
 			httpReq: httpRequestTest{
 				params: map[string]string{
@@ -392,58 +468,60 @@ func TestUserController_getFiltered(t *testing.T) {
 		{
 			name: "No records",
 			svc: svc{
-				args: svcArgs{filter: "foo-filter", value: "foo-value"},
-				resp: svcResp{
-					users: make([]entity.User, 0),
-					err:   nil,
+				query: entity.UserQuery{
+					Filters: []entity.Filter{{Field: entity.UserFieldFirstName, Op: entity.FilterEq, Value: "foo"}},
+				},
+				resp: service.UserSearchResponse{
+					Users: make([]service.UserResponse, 0),
+					Limit: 20,
 				},
 			},
 			httpReq: httpRequestTest{
 				params: map[string]string{
-					"filter": "foo-filter",
-					"value":  "foo-value",
+					"filter": "FirstName",
+					"value":  "foo",
 				},
 			},
 			httpResp: httpResponseTest{
 				code: http.StatusOK,
-				body: "[]\n",
+				body: "{\"users\":[],\"total\":0,\"limit\":20,\"offset\":0,\"next_cursor\":\"\",\"prev_cursor\":\"\"}\n",
 			},
-			err: errHTTP{},
 		},
 		{
 			name: "Multiple records",
 			svc: svc{
-				args: svcArgs{filter: "foo-filter", value: "foo-value"},
-				resp: svcResp{
-					users: []entity.User{
-						{FirstName: "foo"},
-						{FirstName: "bar"},
-						{FirstName: "baz"},
+				query: entity.UserQuery{
+					Filters: []entity.Filter{{Field: entity.UserFieldLastName, Op: entity.FilterEq, Value: "foo"}},
+				},
+				resp: service.UserSearchResponse{
+					Users: []service.UserResponse{
+						{FirstName: "bar", LastName: "foo"},
+						{FirstName: "baz", LastName: "foo"},
 					},
-					err: nil,
+					Total: 2,
+					Limit: 20,
 				},
 			},
 			httpReq: httpRequestTest{
 				params: map[string]string{
-					"filter": "foo-filter",
-					"value":  "foo-value",
+					"filter": "LastName",
+					"value":  "foo",
 				},
 			},
 			httpResp: httpResponseTest{
 				code: http.StatusOK,
-				body: "[{\"id\":\"0\",\"first_name\":\"foo\",\"last_name\":\"\",\"email\":\"\",\"birthday\":\"0001-01-01\",\"username\":\"\"},{\"id\":\"0\",\"first_name\":\"bar\",\"last_name\":\"\",\"email\":\"\",\"birthday\":\"0001-01-01\",\"username\":\"\"},{\"id\":\"0\",\"first_name\":\"baz\",\"last_name\":\"\",\"email\":\"\",\"birthday\":\"0001-01-01\",\"username\":\"\"}]\n",
+				body: "{\"users\":[{\"id\":\"0\",\"first_name\":\"bar\",\"last_name\":\"foo\",\"email\":\"\",\"birthday\":\"0001-01-01\",\"username\":\"\",\"role\":\"\"},{\"id\":\"0\",\"first_name\":\"baz\",\"last_name\":\"foo\",\"email\":\"\",\"birthday\":\"0001-01-01\",\"username\":\"\",\"role\":\"\"}],\"total\":2,\"limit\":20,\"offset\":0,\"next_cursor\":\"\",\"prev_cursor\":\"\"}\n",
 			},
-			err: errHTTP{},
 		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			mockSvc := &mocks.UserSvc{}
-			mockSvc.On("Find", test.svc.args.filter, test.svc.args.value).Return(test.svc.resp.users, test.svc.resp.err)
-			ctrl := NewUserHTTP(mockSvc)
+			mockSvc.On("Search", mock.Anything, test.svc.query, test.svc.cursor).Return(test.svc.resp, test.svc.err)
+			ctrl := NewUserHTTP(mockSvc, &mocks.TokenSvc{}, &mocks.Authenticator{}, &mocks.Authorizer{})
 
-			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/users?filter=%v&value=%v", test.httpReq.params["filter"], test.httpReq.params["value"]), nil)
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/users/filter?filter=%v&value=%v", test.httpReq.params["filter"], test.httpReq.params["value"]), nil)
 			rec := httptest.NewRecorder()
 
 			ctrl.getFiltered(rec, req)
@@ -468,6 +546,7 @@ func TestUserControlller_update(t *testing.T) {
 	tests := []struct {
 		name     string
 		svc      svc
+		authzErr error
 		httpReq  httpRequestTest
 		httpResp httpResponseTest
 		err      errHTTP
@@ -514,6 +593,24 @@ func TestUserControlller_update(t *testing.T) {
 				Message: "invalid payload: strconv.ParseUint: parsing \"badid\": invalid syntax",
 			},
 		},
+		{
+			name: "Not owner nor admin",
+			svc: svc{
+				args: service.UserUpdateArgs{ID: 123},
+			},
+			authzErr: &middleware.AuthorizationErr{Permission: entity.PermUsersUpdate, Err: middleware.ErrPermissionDenied},
+			httpReq: httpRequestTest{
+				payload: []byte(`{"id": "123", "first_name": "foo","last_name": "baz", "birthday": "1990-12-05", "username": "foouser"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusForbidden,
+			},
+			err: errHTTP{
+				Code:    http.StatusForbidden,
+				Status:  authzErrStatus,
+				Message: "authorization failed for \"users:update\": permission denied",
+			},
+		},
 		{
 			name: "Updated",
 			svc: svc{
@@ -538,8 +635,10 @@ func TestUserControlller_update(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			mockSvc := &mocks.UserSvc{}
-			mockSvc.On("Update", test.svc.args).Return(test.svc.err)
-			ctrl := NewUserHTTP(mockSvc)
+			mockSvc.On("Update", mock.Anything, test.svc.args).Return(test.svc.err)
+			mockAuthz := &mocks.Authorizer{}
+			mockAuthz.On("AuthorizeOwner", mock.Anything, test.svc.args.ID, entity.PermUsersUpdate).Return(test.authzErr)
+			ctrl := NewUserHTTP(mockSvc, &mocks.TokenSvc{}, &mocks.Authenticator{}, mockAuthz)
 
 			req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewBuffer(test.httpReq.payload))
 			rec := httptest.NewRecorder()
@@ -624,8 +723,8 @@ func TestUserControlller_delete(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			mockSvc := &mocks.UserSvc{}
-			mockSvc.On("Delete", test.svc.id).Return(test.svc.err)
-			ctrl := NewUserHTTP(mockSvc)
+			mockSvc.On("Delete", mock.Anything, test.svc.id).Return(test.svc.err)
+			ctrl := NewUserHTTP(mockSvc, &mocks.TokenSvc{}, &mocks.Authenticator{}, &mocks.Authorizer{})
 
 			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/users?id=%v", test.httpReq.params["id"]), nil)
 			rec := httptest.NewRecorder()
@@ -657,9 +756,26 @@ func TestUserControlller_login(t *testing.T) {
 		args svcArgs
 		resp svcResp
 	}
+	type authResp struct {
+		token     string
+		expiresAt time.Time
+		err       error
+	}
+	type auth struct {
+		userID   uint64
+		username string
+		role     string
+		resp     authResp
+	}
+	type tokens struct {
+		resp service.RefreshTokenResponse
+		err  error
+	}
 	tests := []struct {
 		name     string
 		svc      svc
+		auth     auth
+		tokens   tokens
 		httpReq  httpRequestTest
 		httpResp httpResponseTest
 		err      errHTTP
@@ -714,7 +830,7 @@ func TestUserControlller_login(t *testing.T) {
 			err: errHTTP{
 				Code:    http.StatusInternalServerError,
 				Status:  repoErrStatus,
-				Message: "repository: some repository error",
+				Message: "Internal Server Error",
 			},
 		},
 		{
@@ -741,6 +857,61 @@ func TestUserControlller_login(t *testing.T) {
 				Message: "service: some service error",
 			},
 		},
+		{
+			name: "Invalid credentials",
+			svc: svc{
+				args: svcArgs{
+					username: "foo",
+					passwd:   "some-password",
+				},
+				resp: svcResp{
+					user: service.UserLoginResponse{},
+					err:  &service.UnauthorizedErr{Err: service.ErrInvalidCredentials},
+				},
+			},
+			httpReq: httpRequestTest{
+				payload: []byte(`{"username": "foo","password": "some-password"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusUnauthorized,
+			},
+			err: errHTTP{
+				Code:    http.StatusUnauthorized,
+				Status:  svcUnauthErrStatus,
+				Message: "unauthorized: invalid username or password",
+			},
+		},
+		{
+			name: "Token error",
+			svc: svc{
+				args: svcArgs{
+					username: "foouser",
+					passwd:   "foopasswd",
+				},
+				resp: svcResp{
+					user: service.UserLoginResponse{ID: 1, Username: "foouser"},
+					err:  nil,
+				},
+			},
+			auth: auth{
+				userID:   1,
+				username: "foouser",
+				resp: authResp{
+					err: &service.Err{Err: errors.New("some signing error")},
+				},
+			},
+			httpReq: httpRequestTest{
+				payload: []byte(`{"username": "foouser","password": "foopasswd"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusBadRequest,
+			},
+			err: errHTTP{
+				Code:    http.StatusBadRequest,
+				Status:  svcErrStatus,
+				Message: "service: some signing error",
+			},
+		},
 		{
 			name: "Valid",
 			svc: svc{
@@ -755,16 +926,33 @@ func TestUserControlller_login(t *testing.T) {
 						LastName:  "baz",
 						Email:     "foo@example.com",
 						Username:  "foouser",
+						Role:      "user",
 					},
 					err: nil,
 				},
 			},
+			auth: auth{
+				userID:   1,
+				username: "foouser",
+				role:     "user",
+				resp: authResp{
+					token:     "some.signed.token",
+					expiresAt: time.Date(2024, time.May, 1, 10, 15, 0, 0, time.UTC),
+				},
+			},
+			tokens: tokens{
+				resp: service.RefreshTokenResponse{
+					Token:     "some-refresh-token",
+					UserID:    1,
+					ExpiresAt: time.Date(2024, time.May, 8, 10, 0, 0, 0, time.UTC),
+				},
+			},
 			httpReq: httpRequestTest{
 				payload: []byte(`{"username": "foouser","password": "foopasswd"}`),
 			},
 			httpResp: httpResponseTest{
 				code: http.StatusOK,
-				body: "{\"id\":\"1\",\"first_name\":\"foo\",\"last_name\":\"baz\",\"email\":\"foo@example.com\",\"username\":\"foouser\",\"last_login\":\"0001-01-01 00:00:00 +0000 UTC\"}\n",
+				body: "{\"id\":\"1\",\"first_name\":\"foo\",\"last_name\":\"baz\",\"email\":\"foo@example.com\",\"username\":\"foouser\",\"last_login\":\"0001-01-01 00:00:00 +0000 UTC\",\"access_token\":\"some.signed.token\",\"token_type\":\"Bearer\",\"expires_at\":\"2024-05-01T10:15:00Z\",\"refresh_token\":\"some-refresh-token\",\"refresh_expires_at\":\"2024-05-08T10:00:00Z\"}\n",
 			},
 		},
 	}
@@ -772,8 +960,12 @@ func TestUserControlller_login(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			mockSvc := &mocks.UserSvc{}
-			mockSvc.On("ValidateLogin", test.svc.args.username, test.svc.args.passwd).Return(test.svc.resp.user, test.svc.resp.err)
-			ctrl := NewUserHTTP(mockSvc)
+			mockSvc.On("ValidateLogin", mock.Anything, test.svc.args.username, test.svc.args.passwd).Return(test.svc.resp.user, test.svc.resp.err)
+			mockAuth := &mocks.Authenticator{}
+			mockAuth.On("IssueAccessToken", test.auth.userID, test.auth.username, test.auth.role).Return(test.auth.resp.token, test.auth.resp.expiresAt, test.auth.resp.err)
+			mockTokens := &mocks.TokenSvc{}
+			mockTokens.On("Issue", mock.Anything, test.auth.userID).Return(test.tokens.resp, test.tokens.err)
+			ctrl := NewUserHTTP(mockSvc, mockTokens, mockAuth, &mocks.Authorizer{})
 
 			req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewBuffer(test.httpReq.payload))
 			rec := httptest.NewRecorder()
@@ -791,3 +983,1814 @@ func TestUserControlller_login(t *testing.T) {
 		})
 	}
 }
+
+func TestUserControlller_refreshToken(t *testing.T) {
+	type tokens struct {
+		token     string
+		userID    uint64
+		verifyErr error
+		resp      service.RefreshTokenResponse
+		err       error
+	}
+	type svcResp struct {
+		user service.UserResponse
+		err  error
+	}
+	tests := []struct {
+		name     string
+		tokens   tokens
+		svcResp  svcResp
+		authErr  error
+		rotated  bool
+		httpReq  httpRequestTest
+		httpResp httpResponseTest
+		err      errHTTP
+	}{
+		{
+			name: "Payload empty",
+			httpReq: httpRequestTest{
+				payload: []byte(""),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusUnsupportedMediaType,
+			},
+			err: errHTTP{
+				Code:    http.StatusUnsupportedMediaType,
+				Status:  ctrlPayloadErrStatus,
+				Message: "invalid payload: EOF",
+			},
+		},
+		{
+			name: "Token reused",
+			tokens: tokens{
+				token:     "some-used-token",
+				verifyErr: &service.UnauthorizedErr{Err: service.ErrTokenReused},
+			},
+			httpReq: httpRequestTest{
+				payload: []byte(`{"refresh_token": "some-used-token"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusUnauthorized,
+			},
+			err: errHTTP{
+				Code:    http.StatusUnauthorized,
+				Status:  svcUnauthErrStatus,
+				Message: "unauthorized: token reused",
+			},
+		},
+		{
+			name: "User inactive",
+			tokens: tokens{
+				token:  "some-refresh-token",
+				userID: 1,
+			},
+			svcResp: svcResp{
+				user: service.UserResponse{ID: 1, Username: "foouser", Active: false},
+			},
+			httpReq: httpRequestTest{
+				payload: []byte(`{"refresh_token": "some-refresh-token"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusUnauthorized,
+			},
+			err: errHTTP{
+				Code:    http.StatusUnauthorized,
+				Status:  svcUnauthErrStatus,
+				Message: "unauthorized: user inactive",
+			},
+		},
+		{
+			name: "Signing error keeps the refresh token",
+			tokens: tokens{
+				token:  "some-refresh-token",
+				userID: 1,
+			},
+			svcResp: svcResp{
+				user: service.UserResponse{ID: 1, Username: "foouser", Active: true},
+			},
+			authErr: &service.Err{Err: errors.New("some signing error")},
+			httpReq: httpRequestTest{
+				payload: []byte(`{"refresh_token": "some-refresh-token"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusBadRequest,
+			},
+			err: errHTTP{
+				Code:    http.StatusBadRequest,
+				Status:  svcErrStatus,
+				Message: "service: some signing error",
+			},
+		},
+		{
+			name: "Refreshed",
+			tokens: tokens{
+				token:  "some-refresh-token",
+				userID: 1,
+				resp: service.RefreshTokenResponse{
+					Token:     "some-rotated-token",
+					UserID:    1,
+					ExpiresAt: time.Date(2024, time.May, 8, 10, 0, 0, 0, time.UTC),
+				},
+			},
+			svcResp: svcResp{
+				user: service.UserResponse{ID: 1, Username: "foouser", Active: true},
+			},
+			rotated: true,
+			httpReq: httpRequestTest{
+				payload: []byte(`{"refresh_token": "some-refresh-token"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusOK,
+				body: "{\"access_token\":\"some.signed.token\",\"token_type\":\"Bearer\",\"expires_at\":\"2024-05-01T10:15:00Z\",\"refresh_token\":\"some-rotated-token\",\"refresh_expires_at\":\"2024-05-08T10:00:00Z\"}\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockTokens := mocks.NewTokenSvc(t)
+			if test.tokens.token != "" {
+				mockTokens.On("Verify", mock.Anything, test.tokens.token).Return(test.tokens.userID, test.tokens.verifyErr)
+			}
+			if test.rotated {
+				mockTokens.On("Rotate", mock.Anything, test.tokens.token).Return(test.tokens.resp, test.tokens.err)
+			}
+			mockSvc := &mocks.UserSvc{}
+			mockSvc.On("Get", mock.Anything, test.tokens.userID).Return(test.svcResp.user, test.svcResp.err)
+			mockAuth := &mocks.Authenticator{}
+			mockAuth.On("IssueAccessToken", test.svcResp.user.ID, test.svcResp.user.Username, test.svcResp.user.Role).
+				Return("some.signed.token", time.Date(2024, time.May, 1, 10, 15, 0, 0, time.UTC), test.authErr)
+			ctrl := NewUserHTTP(mockSvc, mockTokens, mockAuth, &mocks.Authorizer{})
+
+			req := httptest.NewRequest(http.MethodPost, "/token/refresh", bytes.NewBuffer(test.httpReq.payload))
+			rec := httptest.NewRecorder()
+
+			ctrl.refreshToken(rec, req)
+
+			assert.Equal(t, rec.Code, test.httpResp.code)
+			if test.err != (errHTTP{}) {
+				var errMsg errHTTP
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errMsg))
+				assert.Equal(t, test.err, errMsg)
+				return
+			}
+			assert.Equal(t, test.httpResp.body, rec.Body.String())
+		})
+	}
+}
+
+func TestUserControlller_logout(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    string
+		tokenErr error
+		httpReq  httpRequestTest
+		httpResp httpResponseTest
+		err      errHTTP
+	}{
+		{
+			name: "Bad JSON",
+			httpReq: httpRequestTest{
+				payload: []byte(`{"refresh_token": "some-token"`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusUnsupportedMediaType,
+			},
+			err: errHTTP{
+				Code:    http.StatusUnsupportedMediaType,
+				Status:  ctrlPayloadErrStatus,
+				Message: "invalid payload: unexpected EOF",
+			},
+		},
+		{
+			name:     "Unknown token",
+			token:    "some-unknown-token",
+			tokenErr: &service.UnauthorizedErr{Err: service.ErrTokenInvalid},
+			httpReq: httpRequestTest{
+				payload: []byte(`{"refresh_token": "some-unknown-token"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusUnauthorized,
+			},
+			err: errHTTP{
+				Code:    http.StatusUnauthorized,
+				Status:  svcUnauthErrStatus,
+				Message: "unauthorized: invalid token",
+			},
+		},
+		{
+			name:  "Logged out",
+			token: "some-token",
+			httpReq: httpRequestTest{
+				payload: []byte(`{"refresh_token": "some-token"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusOK,
+				body: "{\"message\":\"logged out successfully\"}\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockTokens := &mocks.TokenSvc{}
+			mockTokens.On("Revoke", mock.Anything, test.token).Return(test.tokenErr)
+			ctrl := NewUserHTTP(&mocks.UserSvc{}, mockTokens, &mocks.Authenticator{}, &mocks.Authorizer{})
+
+			req := httptest.NewRequest(http.MethodPost, "/logout", bytes.NewBuffer(test.httpReq.payload))
+			rec := httptest.NewRecorder()
+
+			ctrl.logout(rec, req)
+
+			assert.Equal(t, rec.Code, test.httpResp.code)
+			if test.err != (errHTTP{}) {
+				var errMsg errHTTP
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errMsg))
+				assert.Equal(t, test.err, errMsg)
+				return
+			}
+			assert.Equal(t, test.httpResp.body, rec.Body.String())
+		})
+	}
+}
+
+func TestUserControlller_logoutAll(t *testing.T) {
+	tests := []struct {
+		name     string
+		claims   *middleware.Claims
+		userID   uint64
+		tokenErr error
+		httpResp httpResponseTest
+		err      errHTTP
+	}{
+		{
+			name: "Unauthenticated",
+			httpResp: httpResponseTest{
+				code: http.StatusUnauthorized,
+			},
+			err: errHTTP{
+				Code:    http.StatusUnauthorized,
+				Status:  svcUnauthErrStatus,
+				Message: "unauthorized: invalid token",
+			},
+		},
+		{
+			name:     "Repository error",
+			claims:   &middleware.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}},
+			userID:   1,
+			tokenErr: &repository.Err{Err: errors.New("some repo error")},
+			httpResp: httpResponseTest{
+				code: http.StatusInternalServerError,
+			},
+			err: errHTTP{
+				Code:    http.StatusInternalServerError,
+				Status:  repoErrStatus,
+				Message: "Internal Server Error",
+			},
+		},
+		{
+			name:   "Logged out",
+			claims: &middleware.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}},
+			userID: 1,
+			httpResp: httpResponseTest{
+				code: http.StatusOK,
+				body: "{\"message\":\"logged out from all sessions successfully\"}\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockTokens := &mocks.TokenSvc{}
+			mockTokens.On("RevokeAll", mock.Anything, test.userID).Return(test.tokenErr)
+			ctrl := NewUserHTTP(&mocks.UserSvc{}, mockTokens, &mocks.Authenticator{}, &mocks.Authorizer{})
+
+			req := httptest.NewRequest(http.MethodPost, "/logout/all", nil)
+			if test.claims != nil {
+				req = req.WithContext(middleware.ContextWithClaims(req.Context(), *test.claims))
+			}
+			rec := httptest.NewRecorder()
+
+			ctrl.logoutAll(rec, req)
+
+			assert.Equal(t, rec.Code, test.httpResp.code)
+			if test.err != (errHTTP{}) {
+				var errMsg errHTTP
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errMsg))
+				assert.Equal(t, test.err, errMsg)
+				return
+			}
+			assert.Equal(t, test.httpResp.body, rec.Body.String())
+		})
+	}
+}
+
+func TestUserControlller_changeRole(t *testing.T) {
+	type svc struct {
+		id   uint64
+		role string
+		err  error
+	}
+	tests := []struct {
+		name     string
+		svc      svc
+		httpReq  httpRequestTest
+		httpResp httpResponseTest
+		err      errHTTP
+	}{
+		{
+			name: "Bad ID",
+			httpReq: httpRequestTest{
+				payload: []byte(`{"id": "badid", "role": "admin"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusUnsupportedMediaType,
+			},
+			err: errHTTP{
+				Code:    http.StatusUnsupportedMediaType,
+				Status:  ctrlPayloadErrStatus,
+				Message: "invalid payload: strconv.ParseUint: parsing \"badid\": invalid syntax",
+			},
+		},
+		{
+			name: "Service error",
+			svc: svc{
+				id:   123,
+				role: "Bad Role",
+				err:  &service.Err{Err: errors.New("some svc error")},
+			},
+			httpReq: httpRequestTest{
+				payload: []byte(`{"id": "123", "role": "Bad Role"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusBadRequest,
+			},
+			err: errHTTP{
+				Code:    http.StatusBadRequest,
+				Status:  svcErrStatus,
+				Message: "service: some svc error",
+			},
+		},
+		{
+			name: "Changed",
+			svc: svc{
+				id:   123,
+				role: "admin",
+			},
+			httpReq: httpRequestTest{
+				payload: []byte(`{"id": "123", "role": "admin"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusOK,
+				body: "{\"message\":\"user 123 role changed to admin successfully\"}\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := &mocks.UserSvc{}
+			mockSvc.On("ChangeRole", mock.Anything, test.svc.id, test.svc.role).Return(test.svc.err)
+			ctrl := NewUserHTTP(mockSvc, &mocks.TokenSvc{}, &mocks.Authenticator{}, &mocks.Authorizer{})
+
+			req := httptest.NewRequest(http.MethodPut, "/users/role", bytes.NewBuffer(test.httpReq.payload))
+			rec := httptest.NewRecorder()
+
+			ctrl.changeRole(rec, req)
+
+			assert.Equal(t, rec.Code, test.httpResp.code)
+			if test.err != (errHTTP{}) {
+				var errMsg errHTTP
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errMsg))
+				assert.Equal(t, test.err, errMsg)
+				return
+			}
+			assert.Equal(t, test.httpResp.body, rec.Body.String())
+		})
+	}
+}
+
+func TestUserController_isActive(t *testing.T) {
+	type svc struct {
+		id     uint64
+		active bool
+		err    error
+	}
+	tests := []struct {
+		name     string
+		svc      svc
+		id       string
+		httpResp httpResponseTest
+		err      errHTTP
+	}{
+		{
+			name: "Empty",
+			id:   "",
+			httpResp: httpResponseTest{
+				code: http.StatusBadRequest,
+			},
+			err: errHTTP{
+				Code:    http.StatusBadRequest,
+				Status:  ctrlParamErrStatus,
+				Message: "invalid id parameter: empty value",
+			},
+		},
+		{
+			name: "Invalid ID",
+			id:   "badid",
+			httpResp: httpResponseTest{
+				code: http.StatusBadRequest,
+			},
+			err: errHTTP{
+				Code:    http.StatusBadRequest,
+				Status:  ctrlParamErrStatus,
+				Message: "invalid id parameter: strconv.ParseUint: parsing \"badid\": invalid syntax",
+			},
+		},
+		{
+			name: "Service error",
+			svc: svc{
+				id:  1,
+				err: &service.Err{Err: errors.New("some svc error")},
+			},
+			id: "1",
+			httpResp: httpResponseTest{
+				code: http.StatusBadRequest,
+			},
+			err: errHTTP{
+				Code:    http.StatusBadRequest,
+				Status:  svcErrStatus,
+				Message: "service: some svc error",
+			},
+		},
+		{
+			name: "Inactive",
+			svc: svc{
+				id:     1,
+				active: false,
+			},
+			id: "1",
+			httpResp: httpResponseTest{
+				code: http.StatusOK,
+				body: "{\"id\":\"1\",\"active\":false}\n",
+			},
+		},
+		{
+			name: "Active",
+			svc: svc{
+				id:     1,
+				active: true,
+			},
+			id: "1",
+			httpResp: httpResponseTest{
+				code: http.StatusOK,
+				body: "{\"id\":\"1\",\"active\":true}\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := &mocks.UserSvc{}
+			mockSvc.On("IsActive", mock.Anything, tt.svc.id).Return(tt.svc.active, tt.svc.err)
+			ctrl := NewUserHTTP(mockSvc, &mocks.TokenSvc{}, &mocks.Authenticator{}, &mocks.Authorizer{})
+
+			req := httptest.NewRequest(http.MethodGet, "/users/active?id="+tt.id, nil)
+			rec := httptest.NewRecorder()
+
+			ctrl.isActive(rec, req)
+
+			assert.Equal(t, rec.Code, tt.httpResp.code)
+			if tt.err != (errHTTP{}) {
+				var errMsg errHTTP
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errMsg))
+				assert.Equal(t, tt.err, errMsg)
+				return
+			}
+			assert.Equal(t, tt.httpResp.body, rec.Body.String())
+		})
+	}
+}
+
+func TestUserControlller_activate(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		svcID    uint64
+		svcErr   error
+		httpReq  httpRequestTest
+		httpResp httpResponseTest
+		err      errHTTP
+	}{
+		{
+			name:   "Empty",
+			method: "Activate",
+			httpReq: httpRequestTest{
+				payload: []byte(""),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusUnsupportedMediaType,
+			},
+			err: errHTTP{
+				Code:    http.StatusUnsupportedMediaType,
+				Status:  ctrlPayloadErrStatus,
+				Message: "invalid payload: EOF",
+			},
+		},
+		{
+			name:   "Bad ID",
+			method: "Deactivate",
+			httpReq: httpRequestTest{
+				payload: []byte(`{"id": "badid"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusUnsupportedMediaType,
+			},
+			err: errHTTP{
+				Code:    http.StatusUnsupportedMediaType,
+				Status:  ctrlPayloadErrStatus,
+				Message: "invalid payload: strconv.ParseUint: parsing \"badid\": invalid syntax",
+			},
+		},
+		{
+			name:   "Service error",
+			method: "Activate",
+			svcID:  123,
+			svcErr: &service.Err{Err: errors.New("some svc error")},
+			httpReq: httpRequestTest{
+				payload: []byte(`{"id": "123"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusBadRequest,
+			},
+			err: errHTTP{
+				Code:    http.StatusBadRequest,
+				Status:  svcErrStatus,
+				Message: "service: some svc error",
+			},
+		},
+		{
+			name:   "Activated",
+			method: "Activate",
+			svcID:  123,
+			httpReq: httpRequestTest{
+				payload: []byte(`{"id": "123"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusOK,
+				body: "{\"message\":\"user 123 activated successfully\"}\n",
+			},
+		},
+		{
+			name:   "Deactivated",
+			method: "Deactivate",
+			svcID:  123,
+			httpReq: httpRequestTest{
+				payload: []byte(`{"id": "123"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusOK,
+				body: "{\"message\":\"user 123 deactivated successfully\"}\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := &mocks.UserSvc{}
+			mockSvc.On(test.method, mock.Anything, test.svcID).Return(test.svcErr)
+			ctrl := NewUserHTTP(mockSvc, &mocks.TokenSvc{}, &mocks.Authenticator{}, &mocks.Authorizer{})
+
+			handler, target := ctrl.activate, "/users/activate"
+			if test.method == "Deactivate" {
+				handler, target = ctrl.deactivate, "/users/deactivate"
+			}
+			req := httptest.NewRequest(http.MethodPut, target, bytes.NewBuffer(test.httpReq.payload))
+			rec := httptest.NewRecorder()
+
+			handler(rec, req)
+
+			assert.Equal(t, rec.Code, test.httpResp.code)
+			if test.err != (errHTTP{}) {
+				var errMsg errHTTP
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errMsg))
+				assert.Equal(t, test.err, errMsg)
+				return
+			}
+			assert.Equal(t, test.httpResp.body, rec.Body.String())
+		})
+	}
+}
+
+func TestUserControlller_changeEmail(t *testing.T) {
+	type svc struct {
+		id    uint64
+		email string
+		err   error
+	}
+	tests := []struct {
+		name     string
+		svc      svc
+		authzErr error
+		httpReq  httpRequestTest
+		httpResp httpResponseTest
+		err      errHTTP
+	}{
+		{
+			name: "Bad ID",
+			httpReq: httpRequestTest{
+				payload: []byte(`{"id": "badid", "email": "foo@bar.com"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusUnsupportedMediaType,
+			},
+			err: errHTTP{
+				Code:    http.StatusUnsupportedMediaType,
+				Status:  ctrlPayloadErrStatus,
+				Message: "invalid payload: strconv.ParseUint: parsing \"badid\": invalid syntax",
+			},
+		},
+		{
+			name:     "Not owner nor admin",
+			svc:      svc{id: 123},
+			authzErr: &middleware.AuthorizationErr{Permission: entity.PermUsersUpdate, Err: middleware.ErrPermissionDenied},
+			httpReq: httpRequestTest{
+				payload: []byte(`{"id": "123", "email": "foo@bar.com"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusForbidden,
+			},
+			err: errHTTP{
+				Code:    http.StatusForbidden,
+				Status:  authzErrStatus,
+				Message: "authorization failed for \"users:update\": permission denied",
+			},
+		},
+		{
+			name: "Service error",
+			svc: svc{
+				id:    123,
+				email: "foo",
+				err:   &service.Err{Err: errors.New("some svc error")},
+			},
+			httpReq: httpRequestTest{
+				payload: []byte(`{"id": "123", "email": "foo"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusBadRequest,
+			},
+			err: errHTTP{
+				Code:    http.StatusBadRequest,
+				Status:  svcErrStatus,
+				Message: "service: some svc error",
+			},
+		},
+		{
+			name: "Changed",
+			svc: svc{
+				id:    123,
+				email: "foo@bar.com",
+			},
+			httpReq: httpRequestTest{
+				payload: []byte(`{"id": "123", "email": "foo@bar.com"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusOK,
+				body: "{\"message\":\"user 123 email changed successfully\"}\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := &mocks.UserSvc{}
+			mockSvc.On("ChangeEmail", mock.Anything, test.svc.id, test.svc.email).Return(test.svc.err)
+			mockAuthz := &mocks.Authorizer{}
+			mockAuthz.On("AuthorizeOwner", mock.Anything, test.svc.id, entity.PermUsersUpdate).Return(test.authzErr)
+			ctrl := NewUserHTTP(mockSvc, &mocks.TokenSvc{}, &mocks.Authenticator{}, mockAuthz)
+
+			req := httptest.NewRequest(http.MethodPut, "/users/email", bytes.NewBuffer(test.httpReq.payload))
+			rec := httptest.NewRecorder()
+
+			ctrl.changeEmail(rec, req)
+
+			assert.Equal(t, rec.Code, test.httpResp.code)
+			if test.err != (errHTTP{}) {
+				var errMsg errHTTP
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errMsg))
+				assert.Equal(t, test.err, errMsg)
+				return
+			}
+			assert.Equal(t, test.httpResp.body, rec.Body.String())
+		})
+	}
+}
+
+func TestUserControlller_changePasswd(t *testing.T) {
+	type svc struct {
+		id      uint64
+		current string
+		passwd  string
+		err     error
+	}
+	tests := []struct {
+		name     string
+		svc      svc
+		authzErr error
+		revoked  bool
+		tokenErr error
+		httpReq  httpRequestTest
+		httpResp httpResponseTest
+		err      errHTTP
+	}{
+		{
+			name: "Bad JSON",
+			httpReq: httpRequestTest{
+				payload: []byte(`{"id": "123", "current_password": "pass123"`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusUnsupportedMediaType,
+			},
+			err: errHTTP{
+				Code:    http.StatusUnsupportedMediaType,
+				Status:  ctrlPayloadErrStatus,
+				Message: "invalid payload: unexpected EOF",
+			},
+		},
+		{
+			name:     "Not owner nor admin",
+			svc:      svc{id: 123},
+			authzErr: &middleware.AuthorizationErr{Permission: entity.PermUsersUpdate, Err: middleware.ErrPermissionDenied},
+			httpReq: httpRequestTest{
+				payload: []byte(`{"id": "123", "current_password": "pass123", "new_password": "newpass"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusForbidden,
+			},
+			err: errHTTP{
+				Code:    http.StatusForbidden,
+				Status:  authzErrStatus,
+				Message: "authorization failed for \"users:update\": permission denied",
+			},
+		},
+		{
+			name: "Current password does not match",
+			svc: svc{
+				id:      123,
+				current: "badpass",
+				passwd:  "newpass",
+				err:     &service.InvalidInputErr{Field: "currentPasswd", Err: service.ErrPasswdDoNotMatch},
+			},
+			httpReq: httpRequestTest{
+				payload: []byte(`{"id": "123", "current_password": "badpass", "new_password": "newpass"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusBadRequest,
+			},
+			err: errHTTP{
+				Code:    http.StatusBadRequest,
+				Status:  svcInvalidErrStatus,
+				Message: "invalid input for field \"currentPasswd\" : passwords do not match",
+			},
+		},
+		{
+			name: "Sessions revocation error",
+			svc: svc{
+				id:      123,
+				current: "pass123",
+				passwd:  "newpass",
+			},
+			revoked:  true,
+			tokenErr: &repository.Err{Err: errors.New("some repository error")},
+			httpReq: httpRequestTest{
+				payload: []byte(`{"id": "123", "current_password": "pass123", "new_password": "newpass"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusOK,
+				body: "{\"message\":\"user 123 password changed successfully, its sessions may still be active\"}\n",
+			},
+		},
+		{
+			name: "Changed",
+			svc: svc{
+				id:      123,
+				current: "pass123",
+				passwd:  "newpass",
+			},
+			revoked: true,
+			httpReq: httpRequestTest{
+				payload: []byte(`{"id": "123", "current_password": "pass123", "new_password": "newpass"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusOK,
+				body: "{\"message\":\"user 123 password changed successfully\"}\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := &mocks.UserSvc{}
+			mockSvc.On("ChangePasswd", mock.Anything, test.svc.id, test.svc.current, test.svc.passwd).Return(test.svc.err)
+			mockAuthz := &mocks.Authorizer{}
+			mockAuthz.On("AuthorizeOwner", mock.Anything, test.svc.id, entity.PermUsersUpdate).Return(test.authzErr)
+			mockTokens := mocks.NewTokenSvc(t)
+			if test.revoked {
+				mockTokens.On("RevokeAll", mock.Anything, test.svc.id).Return(test.tokenErr).Once()
+			}
+			ctrl := NewUserHTTP(mockSvc, mockTokens, &mocks.Authenticator{}, mockAuthz)
+
+			req := httptest.NewRequest(http.MethodPut, "/users/password", bytes.NewBuffer(test.httpReq.payload))
+			rec := httptest.NewRecorder()
+
+			ctrl.changePasswd(rec, req)
+
+			assert.Equal(t, rec.Code, test.httpResp.code)
+			if test.err != (errHTTP{}) {
+				var errMsg errHTTP
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errMsg))
+				assert.Equal(t, test.err, errMsg)
+				return
+			}
+			assert.Equal(t, test.httpResp.body, rec.Body.String())
+		})
+	}
+}
+
+func TestUserController_getByID(t *testing.T) {
+	type svcResp struct {
+		user service.UserResponse
+		err  error
+	}
+	type svc struct {
+		id   uint64
+		resp svcResp
+	}
+	tests := []struct {
+		name     string
+		svc      svc
+		httpReq  httpRequestTest
+		httpResp httpResponseTest
+		err      errHTTP
+	}{
+		{
+			name: "Invalid ID",
+			httpReq: httpRequestTest{
+				params: map[string]string{
+					"id": "badid",
+				},
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusBadRequest,
+			},
+			err: errHTTP{
+				Code:    http.StatusBadRequest,
+				Status:  ctrlParamErrStatus,
+				Message: "invalid id parameter: strconv.ParseUint: parsing \"badid\": invalid syntax",
+			},
+		},
+		{
+			name: "Service error",
+			svc: svc{
+				id: 1,
+				resp: svcResp{
+					err: &service.Err{Err: errors.New("some svc error")},
+				},
+			},
+			httpReq: httpRequestTest{
+				params: map[string]string{
+					"id": "1",
+				},
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusBadRequest,
+			},
+			err: errHTTP{
+				Code:    http.StatusBadRequest,
+				Status:  svcErrStatus,
+				Message: "service: some svc error",
+			},
+		},
+		{
+			name: "Not found",
+			svc: svc{
+				id: 1,
+				resp: svcResp{
+					err: &repository.NotFoundErr{Entity: "user"},
+				},
+			},
+			httpReq: httpRequestTest{
+				params: map[string]string{
+					"id": "1",
+				},
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusNotFound,
+			},
+			err: errHTTP{
+				Code:    http.StatusNotFound,
+				Status:  repoNotFoundStatus,
+				Message: "user not found",
+			},
+		},
+		{
+			name: "Request timeout",
+			svc: svc{
+				id: 1,
+				resp: svcResp{
+					err: context.DeadlineExceeded,
+				},
+			},
+			httpReq: httpRequestTest{
+				params: map[string]string{
+					"id": "1",
+				},
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusGatewayTimeout,
+			},
+			err: errHTTP{
+				Code:    http.StatusGatewayTimeout,
+				Status:  timeoutErrStatus,
+				Message: "context deadline exceeded",
+			},
+		},
+		{
+			name: "Valid",
+			svc: svc{
+				id: 1,
+				resp: svcResp{
+					user: service.UserResponse{
+						ID:        1,
+						FirstName: "foo",
+						LastName:  "baz",
+						Role:      entity.RoleUser,
+					},
+				},
+			},
+			httpReq: httpRequestTest{
+				params: map[string]string{
+					"id": "1",
+				},
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusOK,
+				body: "{\"id\":\"1\",\"first_name\":\"foo\",\"last_name\":\"baz\",\"email\":\"\",\"birthday\":\"0001-01-01\",\"username\":\"\",\"role\":\"user\"}\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := &mocks.UserSvc{}
+			mockSvc.On("Get", mock.Anything, tt.svc.id).Return(tt.svc.resp.user, tt.svc.resp.err)
+			ctrl := NewUserHTTP(mockSvc, &mocks.TokenSvc{}, &mocks.Authenticator{}, &mocks.Authorizer{})
+
+			req := withURLParams(httptest.NewRequest(http.MethodGet, "/users/"+tt.httpReq.params["id"], nil), tt.httpReq.params)
+			rec := httptest.NewRecorder()
+
+			ctrl.getByID(rec, req)
+
+			assert.Equal(t, rec.Code, tt.httpResp.code)
+			if tt.err != (errHTTP{}) {
+				var errMsg errHTTP
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errMsg))
+				assert.Equal(t, tt.err, errMsg)
+				return
+			}
+			assert.Equal(t, tt.httpResp.body, rec.Body.String())
+		})
+	}
+}
+
+func TestUserControlller_replace(t *testing.T) {
+	type svc struct {
+		args service.UserUpdateArgs
+		err  error
+	}
+	tests := []struct {
+		name     string
+		id       string
+		svc      svc
+		authzErr error
+		httpReq  httpRequestTest
+		httpResp httpResponseTest
+		err      errHTTP
+	}{
+		{
+			name: "Bad ID",
+			id:   "badid",
+			httpReq: httpRequestTest{
+				payload: []byte(`{"first_name": "foo","last_name": "baz", "birthday": "1990-12-05"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusBadRequest,
+			},
+			err: errHTTP{
+				Code:    http.StatusBadRequest,
+				Status:  ctrlParamErrStatus,
+				Message: "invalid id parameter: strconv.ParseUint: parsing \"badid\": invalid syntax",
+			},
+		},
+		{
+			name:     "Not owner nor admin",
+			id:       "123",
+			svc:      svc{args: service.UserUpdateArgs{ID: 123}},
+			authzErr: &middleware.AuthorizationErr{Permission: entity.PermUsersUpdate, Err: middleware.ErrPermissionDenied},
+			httpReq: httpRequestTest{
+				payload: []byte(`{"first_name": "foo","last_name": "baz", "birthday": "1990-12-05"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusForbidden,
+			},
+			err: errHTTP{
+				Code:    http.StatusForbidden,
+				Status:  authzErrStatus,
+				Message: "authorization failed for \"users:update\": permission denied",
+			},
+		},
+		{
+			name: "Missing field",
+			id:   "123",
+			svc:  svc{args: service.UserUpdateArgs{ID: 123}},
+			httpReq: httpRequestTest{
+				payload: []byte(`{"first_name": "foo", "birthday": "1990-12-05"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusBadRequest,
+			},
+			err: errHTTP{
+				Code:    http.StatusBadRequest,
+				Status:  ctrlParamErrStatus,
+				Message: "invalid last_name parameter: empty value",
+			},
+		},
+		{
+			name: "Bad birthday",
+			id:   "123",
+			svc:  svc{args: service.UserUpdateArgs{ID: 123}},
+			httpReq: httpRequestTest{
+				payload: []byte(`{"first_name": "foo","last_name": "baz", "birthday": "05-12-1990"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusUnsupportedMediaType,
+			},
+			err: errHTTP{
+				Code:    http.StatusUnsupportedMediaType,
+				Status:  ctrlPayloadErrStatus,
+				Message: "invalid payload: parsing time \"05-12-1990\" as \"2006-01-02\": cannot parse \"05-12-1990\" as \"2006\"",
+			},
+		},
+		{
+			name: "Replaced",
+			id:   "123",
+			svc: svc{
+				args: service.UserUpdateArgs{
+					ID:        123,
+					FirstName: "foo",
+					LastName:  "baz",
+					BirthDay:  time.Date(1990, time.December, 5, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			httpReq: httpRequestTest{
+				payload: []byte(`{"first_name": "foo","last_name": "baz", "birthday": "1990-12-05"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusOK,
+				body: "{\"message\":\"user 123 updated successfully\"}\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := &mocks.UserSvc{}
+			mockSvc.On("Update", mock.Anything, test.svc.args).Return(test.svc.err)
+			mockAuthz := &mocks.Authorizer{}
+			mockAuthz.On("AuthorizeOwner", mock.Anything, test.svc.args.ID, entity.PermUsersUpdate).Return(test.authzErr)
+			ctrl := NewUserHTTP(mockSvc, &mocks.TokenSvc{}, &mocks.Authenticator{}, mockAuthz)
+
+			req := httptest.NewRequest(http.MethodPut, "/users/"+test.id, bytes.NewBuffer(test.httpReq.payload))
+			req = withURLParams(req, map[string]string{"id": test.id})
+			rec := httptest.NewRecorder()
+
+			ctrl.replace(rec, req)
+
+			assert.Equal(t, rec.Code, test.httpResp.code)
+			if test.err != (errHTTP{}) {
+				var errMsg errHTTP
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errMsg))
+				assert.Equal(t, test.err, errMsg)
+				return
+			}
+			assert.Equal(t, test.httpResp.body, rec.Body.String())
+		})
+	}
+}
+
+func TestUserControlller_patch(t *testing.T) {
+	type svc struct {
+		args service.UserUpdateArgs
+		err  error
+	}
+	tests := []struct {
+		name     string
+		svc      svc
+		httpReq  httpRequestTest
+		httpResp httpResponseTest
+		err      errHTTP
+	}{
+		{
+			name: "Empty",
+			svc:  svc{args: service.UserUpdateArgs{ID: 123}},
+			httpReq: httpRequestTest{
+				payload: []byte(""),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusUnsupportedMediaType,
+			},
+			err: errHTTP{
+				Code:    http.StatusUnsupportedMediaType,
+				Status:  ctrlPayloadErrStatus,
+				Message: "invalid payload: EOF",
+			},
+		},
+		{
+			name: "Service error",
+			svc: svc{
+				args: service.UserUpdateArgs{ID: 123, LastName: "baz"},
+				err:  &service.Err{Err: errors.New("some svc error")},
+			},
+			httpReq: httpRequestTest{
+				payload: []byte(`{"last_name": "baz"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusBadRequest,
+			},
+			err: errHTTP{
+				Code:    http.StatusBadRequest,
+				Status:  svcErrStatus,
+				Message: "service: some svc error",
+			},
+		},
+		{
+			name: "Patched first name",
+			svc: svc{
+				args: service.UserUpdateArgs{ID: 123, FirstName: "foo"},
+			},
+			httpReq: httpRequestTest{
+				payload: []byte(`{"first_name": "foo"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusOK,
+				body: "{\"message\":\"user 123 updated successfully\"}\n",
+			},
+		},
+		{
+			name: "Patched birthday",
+			svc: svc{
+				args: service.UserUpdateArgs{
+					ID:       123,
+					BirthDay: time.Date(1990, time.December, 5, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			httpReq: httpRequestTest{
+				payload: []byte(`{"birthday": "1990-12-05"}`),
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusOK,
+				body: "{\"message\":\"user 123 updated successfully\"}\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := &mocks.UserSvc{}
+			mockSvc.On("Update", mock.Anything, test.svc.args).Return(test.svc.err)
+			mockAuthz := &mocks.Authorizer{}
+			mockAuthz.On("AuthorizeOwner", mock.Anything, uint64(123), entity.PermUsersUpdate).Return(nil)
+			ctrl := NewUserHTTP(mockSvc, &mocks.TokenSvc{}, &mocks.Authenticator{}, mockAuthz)
+
+			req := httptest.NewRequest(http.MethodPatch, "/users/123", bytes.NewBuffer(test.httpReq.payload))
+			req = withURLParams(req, map[string]string{"id": "123"})
+			rec := httptest.NewRecorder()
+
+			ctrl.patch(rec, req)
+
+			assert.Equal(t, rec.Code, test.httpResp.code)
+			if test.err != (errHTTP{}) {
+				var errMsg errHTTP
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errMsg))
+				assert.Equal(t, test.err, errMsg)
+				return
+			}
+			assert.Equal(t, test.httpResp.body, rec.Body.String())
+		})
+	}
+}
+
+func TestUserControlller_deleteByID(t *testing.T) {
+	type svc struct {
+		id  uint64
+		err error
+	}
+	tests := []struct {
+		name     string
+		id       string
+		svc      svc
+		httpResp httpResponseTest
+		err      errHTTP
+	}{
+		{
+			name: "Bad ID",
+			id:   "badid",
+			httpResp: httpResponseTest{
+				code: http.StatusBadRequest,
+			},
+			err: errHTTP{
+				Code:    http.StatusBadRequest,
+				Status:  ctrlParamErrStatus,
+				Message: "invalid id parameter: strconv.ParseUint: parsing \"badid\": invalid syntax",
+			},
+		},
+		{
+			name: "Service error",
+			id:   "123",
+			svc: svc{
+				id:  123,
+				err: &service.Err{Err: errors.New("some svc error")},
+			},
+			httpResp: httpResponseTest{
+				code: http.StatusBadRequest,
+			},
+			err: errHTTP{
+				Code:    http.StatusBadRequest,
+				Status:  svcErrStatus,
+				Message: "service: some svc error",
+			},
+		},
+		{
+			name: "Deleted",
+			id:   "123",
+			svc:  svc{id: 123},
+			httpResp: httpResponseTest{
+				code: http.StatusOK,
+				body: "{\"message\":\"user 123 deleted successfully\"}\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := &mocks.UserSvc{}
+			mockSvc.On("Delete", mock.Anything, test.svc.id).Return(test.svc.err)
+			ctrl := NewUserHTTP(mockSvc, &mocks.TokenSvc{}, &mocks.Authenticator{}, &mocks.Authorizer{})
+
+			req := withURLParams(httptest.NewRequest(http.MethodDelete, "/users/"+test.id, nil), map[string]string{"id": test.id})
+			rec := httptest.NewRecorder()
+
+			ctrl.deleteByID(rec, req)
+
+			assert.Equal(t, rec.Code, test.httpResp.code)
+			if test.err != (errHTTP{}) {
+				var errMsg errHTTP
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errMsg))
+				assert.Equal(t, test.err, errMsg)
+				return
+			}
+			assert.Equal(t, test.httpResp.body, rec.Body.String())
+		})
+	}
+}
+
+func TestUserController_changeRoleByID(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		role     string
+		payload  string
+		httpResp httpResponseTest
+		err      errHTTP
+	}{
+		{
+			name:     "Bad ID",
+			id:       "badid",
+			payload:  `{"role": "admin"}`,
+			httpResp: httpResponseTest{code: http.StatusBadRequest},
+			err: errHTTP{
+				Code:    http.StatusBadRequest,
+				Status:  ctrlParamErrStatus,
+				Message: "invalid id parameter: strconv.ParseUint: parsing \"badid\": invalid syntax",
+			},
+		},
+		{
+			name:     "Bad JSON",
+			id:       "123",
+			payload:  `{"role": "admin"`,
+			httpResp: httpResponseTest{code: http.StatusUnsupportedMediaType},
+			err: errHTTP{
+				Code:    http.StatusUnsupportedMediaType,
+				Status:  ctrlPayloadErrStatus,
+				Message: "invalid payload: unexpected EOF",
+			},
+		},
+		{
+			name:    "Changed",
+			id:      "123",
+			role:    "admin",
+			payload: `{"role": "admin"}`,
+			httpResp: httpResponseTest{
+				code: http.StatusOK,
+				body: "{\"message\":\"user 123 role changed to admin successfully\"}\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := &mocks.UserSvc{}
+			mockSvc.On("ChangeRole", mock.Anything, uint64(123), test.role).Return(nil)
+			ctrl := NewUserHTTP(mockSvc, &mocks.TokenSvc{}, &mocks.Authenticator{}, &mocks.Authorizer{})
+
+			req := withURLParams(httptest.NewRequest(http.MethodPut, "/users/"+test.id+"/role", bytes.NewBufferString(test.payload)), map[string]string{"id": test.id})
+			rec := httptest.NewRecorder()
+
+			ctrl.changeRoleByID(rec, req)
+
+			assert.Equal(t, test.httpResp.code, rec.Code)
+			if test.err != (errHTTP{}) {
+				var errMsg errHTTP
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errMsg))
+				assert.Equal(t, test.err, errMsg)
+				return
+			}
+			assert.Equal(t, test.httpResp.body, rec.Body.String())
+		})
+	}
+}
+
+func TestUserController_isActiveByID(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		httpResp httpResponseTest
+		err      errHTTP
+	}{
+		{
+			name:     "Bad ID",
+			id:       "badid",
+			httpResp: httpResponseTest{code: http.StatusBadRequest},
+			err: errHTTP{
+				Code:    http.StatusBadRequest,
+				Status:  ctrlParamErrStatus,
+				Message: "invalid id parameter: strconv.ParseUint: parsing \"badid\": invalid syntax",
+			},
+		},
+		{
+			name: "Active",
+			id:   "123",
+			httpResp: httpResponseTest{
+				code: http.StatusOK,
+				body: "{\"id\":\"123\",\"active\":true}\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := &mocks.UserSvc{}
+			mockSvc.On("IsActive", mock.Anything, uint64(123)).Return(true, nil)
+			ctrl := NewUserHTTP(mockSvc, &mocks.TokenSvc{}, &mocks.Authenticator{}, &mocks.Authorizer{})
+
+			req := withURLParams(httptest.NewRequest(http.MethodGet, "/users/"+test.id+"/active", nil), map[string]string{"id": test.id})
+			rec := httptest.NewRecorder()
+
+			ctrl.isActiveByID(rec, req)
+
+			assert.Equal(t, test.httpResp.code, rec.Code)
+			if test.err != (errHTTP{}) {
+				var errMsg errHTTP
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errMsg))
+				assert.Equal(t, test.err, errMsg)
+				return
+			}
+			assert.Equal(t, test.httpResp.body, rec.Body.String())
+		})
+	}
+}
+
+func TestUserController_activation(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		id       string
+		svcErr   error
+		httpResp httpResponseTest
+		err      errHTTP
+	}{
+		{
+			name:     "Bad ID",
+			method:   http.MethodPut,
+			id:       "badid",
+			httpResp: httpResponseTest{code: http.StatusBadRequest},
+			err: errHTTP{
+				Code:    http.StatusBadRequest,
+				Status:  ctrlParamErrStatus,
+				Message: "invalid id parameter: strconv.ParseUint: parsing \"badid\": invalid syntax",
+			},
+		},
+		{
+			name:     "Not found",
+			method:   http.MethodDelete,
+			id:       "123",
+			svcErr:   &repository.NotFoundErr{Entity: "user"},
+			httpResp: httpResponseTest{code: http.StatusNotFound},
+			err: errHTTP{
+				Code:    http.StatusNotFound,
+				Status:  repoNotFoundStatus,
+				Message: (&repository.NotFoundErr{Entity: "user"}).Error(),
+			},
+		},
+		{
+			name:   "Activated",
+			method: http.MethodPut,
+			id:     "123",
+			httpResp: httpResponseTest{
+				code: http.StatusOK,
+				body: "{\"message\":\"user 123 activated successfully\"}\n",
+			},
+		},
+		{
+			name:   "Deactivated",
+			method: http.MethodDelete,
+			id:     "123",
+			httpResp: httpResponseTest{
+				code: http.StatusOK,
+				body: "{\"message\":\"user 123 deactivated successfully\"}\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := &mocks.UserSvc{}
+			mockSvc.On("Activate", mock.Anything, uint64(123)).Return(test.svcErr)
+			mockSvc.On("Deactivate", mock.Anything, uint64(123)).Return(test.svcErr)
+			ctrl := NewUserHTTP(mockSvc, &mocks.TokenSvc{}, &mocks.Authenticator{}, &mocks.Authorizer{})
+
+			req := withURLParams(httptest.NewRequest(test.method, "/users/"+test.id+"/activation", nil), map[string]string{"id": test.id})
+			rec := httptest.NewRecorder()
+
+			if test.method == http.MethodDelete {
+				ctrl.deactivateByID(rec, req)
+			} else {
+				ctrl.activateByID(rec, req)
+			}
+
+			assert.Equal(t, test.httpResp.code, rec.Code)
+			if test.err != (errHTTP{}) {
+				var errMsg errHTTP
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errMsg))
+				assert.Equal(t, test.err, errMsg)
+				return
+			}
+			assert.Equal(t, test.httpResp.body, rec.Body.String())
+		})
+	}
+}
+
+func TestUserController_changeEmailByID(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		authzErr error
+		httpResp httpResponseTest
+		err      errHTTP
+	}{
+		{
+			name:     "Bad ID",
+			id:       "badid",
+			httpResp: httpResponseTest{code: http.StatusBadRequest},
+			err: errHTTP{
+				Code:    http.StatusBadRequest,
+				Status:  ctrlParamErrStatus,
+				Message: "invalid id parameter: strconv.ParseUint: parsing \"badid\": invalid syntax",
+			},
+		},
+		{
+			name:     "Not owner nor admin",
+			id:       "123",
+			authzErr: &middleware.AuthorizationErr{Permission: entity.PermUsersUpdate, Err: middleware.ErrPermissionDenied},
+			httpResp: httpResponseTest{code: http.StatusForbidden},
+			err: errHTTP{
+				Code:    http.StatusForbidden,
+				Status:  authzErrStatus,
+				Message: "authorization failed for \"users:update\": permission denied",
+			},
+		},
+		{
+			name: "Changed",
+			id:   "123",
+			httpResp: httpResponseTest{
+				code: http.StatusOK,
+				body: "{\"message\":\"user 123 email changed successfully\"}\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := &mocks.UserSvc{}
+			mockSvc.On("ChangeEmail", mock.Anything, uint64(123), "foo@example.com").Return(nil)
+			mockAuthz := &mocks.Authorizer{}
+			mockAuthz.On("AuthorizeOwner", mock.Anything, uint64(123), entity.PermUsersUpdate).Return(test.authzErr)
+			ctrl := NewUserHTTP(mockSvc, &mocks.TokenSvc{}, &mocks.Authenticator{}, mockAuthz)
+
+			payload := bytes.NewBufferString(`{"email": "foo@example.com"}`)
+			req := withURLParams(httptest.NewRequest(http.MethodPut, "/users/"+test.id+"/email", payload), map[string]string{"id": test.id})
+			rec := httptest.NewRecorder()
+
+			ctrl.changeEmailByID(rec, req)
+
+			assert.Equal(t, test.httpResp.code, rec.Code)
+			if test.err != (errHTTP{}) {
+				var errMsg errHTTP
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errMsg))
+				assert.Equal(t, test.err, errMsg)
+				return
+			}
+			assert.Equal(t, test.httpResp.body, rec.Body.String())
+		})
+	}
+}
+
+func TestUserController_changePasswdByID(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		revoked  bool
+		tokenErr error
+		httpResp httpResponseTest
+		err      errHTTP
+	}{
+		{
+			name:     "Bad ID",
+			id:       "badid",
+			httpResp: httpResponseTest{code: http.StatusBadRequest},
+			err: errHTTP{
+				Code:    http.StatusBadRequest,
+				Status:  ctrlParamErrStatus,
+				Message: "invalid id parameter: strconv.ParseUint: parsing \"badid\": invalid syntax",
+			},
+		},
+		{
+			name:     "Sessions revocation error",
+			id:       "123",
+			revoked:  true,
+			tokenErr: &repository.ConnectionErr{Err: errors.New("connection refused")},
+			httpResp: httpResponseTest{
+				code: http.StatusOK,
+				body: "{\"message\":\"user 123 password changed successfully, its sessions may still be active\"}\n",
+			},
+		},
+		{
+			name:    "Changed",
+			id:      "123",
+			revoked: true,
+			httpResp: httpResponseTest{
+				code: http.StatusOK,
+				body: "{\"message\":\"user 123 password changed successfully\"}\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := &mocks.UserSvc{}
+			mockSvc.On("ChangePasswd", mock.Anything, uint64(123), "pass123", "newpass").Return(nil)
+			mockAuthz := &mocks.Authorizer{}
+			mockAuthz.On("AuthorizeOwner", mock.Anything, uint64(123), entity.PermUsersUpdate).Return(nil)
+			mockTokens := mocks.NewTokenSvc(t)
+			if test.revoked {
+				mockTokens.On("RevokeAll", mock.Anything, uint64(123)).Return(test.tokenErr).Once()
+			}
+			ctrl := NewUserHTTP(mockSvc, mockTokens, &mocks.Authenticator{}, mockAuthz)
+
+			payload := bytes.NewBufferString(`{"current_password": "pass123", "new_password": "newpass"}`)
+			req := withURLParams(httptest.NewRequest(http.MethodPut, "/users/"+test.id+"/password", payload), map[string]string{"id": test.id})
+			rec := httptest.NewRecorder()
+
+			ctrl.changePasswdByID(rec, req)
+
+			assert.Equal(t, test.httpResp.code, rec.Code)
+			if test.err != (errHTTP{}) {
+				var errMsg errHTTP
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errMsg))
+				assert.Equal(t, test.err, errMsg)
+				return
+			}
+			assert.Equal(t, test.httpResp.body, rec.Body.String())
+		})
+	}
+}
+
+func TestUserHTTP_SetRoutes_deprecation(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		payload    string
+		deprecated bool
+	}{
+		{
+			name:       "Legacy get",
+			method:     http.MethodGet,
+			target:     "/user?id=1",
+			deprecated: true,
+		},
+		{
+			name:       "Legacy delete",
+			method:     http.MethodDelete,
+			target:     "/users?id=1",
+			deprecated: true,
+		},
+		{
+			name:       "Legacy role change",
+			method:     http.MethodPut,
+			target:     "/users/role",
+			payload:    `{"id": "1", "role": "admin"}`,
+			deprecated: true,
+		},
+		{
+			name:       "Legacy active status",
+			method:     http.MethodGet,
+			target:     "/users/active?id=1",
+			deprecated: true,
+		},
+		{
+			name:       "Legacy activation",
+			method:     http.MethodPut,
+			target:     "/users/activate",
+			payload:    `{"id": "1"}`,
+			deprecated: true,
+		},
+		{
+			name:       "Legacy deactivation",
+			method:     http.MethodPut,
+			target:     "/users/deactivate",
+			payload:    `{"id": "1"}`,
+			deprecated: true,
+		},
+		{
+			name:       "Legacy email change",
+			method:     http.MethodPut,
+			target:     "/users/email",
+			payload:    `{"id": "1", "email": "foo@example.com"}`,
+			deprecated: true,
+		},
+		{
+			name:       "Legacy password change",
+			method:     http.MethodPut,
+			target:     "/users/password",
+			payload:    `{"id": "1", "current_password": "pass123", "new_password": "newpass"}`,
+			deprecated: true,
+		},
+		{
+			name:   "Get by ID",
+			method: http.MethodGet,
+			target: "/users/1",
+		},
+		{
+			name:    "Role change by ID",
+			method:  http.MethodPut,
+			target:  "/users/1/role",
+			payload: `{"role": "admin"}`,
+		},
+		{
+			name:   "Active status by ID",
+			method: http.MethodGet,
+			target: "/users/1/active",
+		},
+		{
+			name:   "Activation by ID",
+			method: http.MethodPut,
+			target: "/users/1/activation",
+		},
+		{
+			name:   "Deactivation by ID",
+			method: http.MethodDelete,
+			target: "/users/1/activation",
+		},
+		{
+			name:    "Email change by ID",
+			method:  http.MethodPut,
+			target:  "/users/1/email",
+			payload: `{"email": "foo@example.com"}`,
+		},
+		{
+			name:    "Password change by ID",
+			method:  http.MethodPut,
+			target:  "/users/1/password",
+			payload: `{"current_password": "pass123", "new_password": "newpass"}`,
+		},
+		{
+			name:   "Delete by ID",
+			method: http.MethodDelete,
+			target: "/users/1",
+		},
+	}
+
+	passThrough := func(next http.Handler) http.Handler { return next }
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := &mocks.UserSvc{}
+			mockSvc.On("Get", mock.Anything, uint64(1)).Return(service.UserResponse{ID: 1}, nil)
+			mockSvc.On("Delete", mock.Anything, uint64(1)).Return(nil)
+			mockSvc.On("ChangeRole", mock.Anything, uint64(1), "admin").Return(nil)
+			mockSvc.On("IsActive", mock.Anything, uint64(1)).Return(true, nil)
+			mockSvc.On("Activate", mock.Anything, uint64(1)).Return(nil)
+			mockSvc.On("Deactivate", mock.Anything, uint64(1)).Return(nil)
+			mockSvc.On("ChangeEmail", mock.Anything, uint64(1), "foo@example.com").Return(nil)
+			mockSvc.On("ChangePasswd", mock.Anything, uint64(1), "pass123", "newpass").Return(nil)
+			mockTokens := &mocks.TokenSvc{}
+			mockTokens.On("RevokeAll", mock.Anything, uint64(1)).Return(nil)
+			mockAuth := &mocks.Authenticator{}
+			mockAuth.On("Authenticate", mock.Anything).Return(passThrough)
+			mockAuthz := &mocks.Authorizer{}
+			mockAuthz.On("RequirePermission", mock.Anything).Return(passThrough)
+			mockAuthz.On("AuthorizeOwner", mock.Anything, uint64(1), entity.PermUsersUpdate).Return(nil)
+
+			r := chi.NewRouter()
+			NewUserHTTP(mockSvc, mockTokens, mockAuth, mockAuthz).SetRoutes(r)
+
+			req := httptest.NewRequest(test.method, test.target, strings.NewReader(test.payload))
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			if !test.deprecated {
+				assert.Empty(t, rec.Header().Get("Deprecation"))
+				assert.Empty(t, rec.Header().Get("Sunset"))
+				return
+			}
+			assert.Equal(t, fmt.Sprintf("@%d", legacyRoutesDeprecatedAt.Unix()), rec.Header().Get("Deprecation"))
+			assert.Equal(t, legacyRoutesSunsetAt.Format(http.TimeFormat), rec.Header().Get("Sunset"))
+		})
+	}
+}
+
+// TestUserHTTP_serviceValidationErrors drives the validation errors of the actual UserService, so they are answered as client errors.
+func TestUserHTTP_serviceValidationErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		target  string
+		payload string
+		message string
+	}{
+		{
+			name:    "Zero user ID",
+			method:  http.MethodGet,
+			target:  "/users/0",
+			message: `invalid input for field "id" : zero value`,
+		},
+		{
+			name:    "Login with a short password",
+			method:  http.MethodPost,
+			target:  "/login",
+			payload: `{"username": "jdoe", "password": "abc"}`,
+			message: `invalid input for field "passwd" : invalid password`,
+		},
+		{
+			name:    "Invalid new password",
+			method:  http.MethodPut,
+			target:  "/users/1/password",
+			payload: `{"current_password": "pass123", "new_password": "abc"}`,
+			message: `invalid input for field "passwd" : invalid password`,
+		},
+	}
+
+	passThrough := func(next http.Handler) http.Handler { return next }
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc := service.NewUserService(repository.NewUserRepositoryMemory(), nil, "camgo-controller-test-cursor-key")
+			mockAuth := &mocks.Authenticator{}
+			mockAuth.On("Authenticate", mock.Anything).Return(passThrough)
+			mockAuthz := &mocks.Authorizer{}
+			mockAuthz.On("RequirePermission", mock.Anything).Return(passThrough)
+			mockAuthz.On("AuthorizeOwner", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+			r := chi.NewRouter()
+			NewUserHTTP(svc, mocks.NewTokenSvc(t), mockAuth, mockAuthz).SetRoutes(r)
+
+			req := httptest.NewRequest(test.method, test.target, strings.NewReader(test.payload))
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			var resp errHTTP
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, errHTTP{Code: http.StatusBadRequest, Status: svcInvalidErrStatus, Message: test.message}, resp)
+		})
+	}
+}
@@ -2,10 +2,36 @@ package controller
 
 import (
 	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
 	"github.com/wizeline/CA-Microservices-Go/internal/service"
+
+	"github.com/go-chi/chi"
 )
 
+// userQueryParams are the search query parameters that are not filters.
+var userQueryParams = map[string]bool{
+	"filter": true,
+	"value":  true,
+	"sort":   true,
+	"limit":  true,
+	"offset": true,
+	"cursor": true,
+}
+
+// legacyUserFilters maps the filter names of /users/filter to the searchable user fields.
+var legacyUserFilters = map[string]string{
+	"FirstName": entity.UserFieldFirstName,
+	"LastName":  entity.UserFieldLastName,
+	"Email":     entity.UserFieldEmail,
+	"Username":  entity.UserFieldUsername,
+}
+
 func parseUserResponse(user service.UserResponse) userResponse {
 	return userResponse{
 		ID:        fmt.Sprintf("%d", user.ID),
@@ -14,5 +40,92 @@ func parseUserResponse(user service.UserResponse) userResponse {
 		Email:     user.Email,
 		BirthDay:  user.BirthDay.Format(dateFormat),
 		Username:  user.Username,
+		Role:      user.Role,
+	}
+}
+
+// userIDParam returns the user ID given in the {id} path parameter.
+func userIDParam(r *http.Request) (uint64, error) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return 0, &ParameterErr{Param: "id", Err: "empty value"}
+	}
+	idUint, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return 0, &ParameterErr{Param: "id", Err: err.Error()}
+	}
+	return idUint, nil
+}
+
+// validateUserReplace verifies every field required to replace a user is given.
+func validateUserReplace(dto userWriteRequest) error {
+	switch {
+	case dto.FirstName == "":
+		return &ParameterErr{Param: "first_name", Err: "empty value"}
+	case dto.LastName == "":
+		return &ParameterErr{Param: "last_name", Err: "empty value"}
+	case dto.BirthDay == "":
+		return &ParameterErr{Param: "birthday", Err: "empty value"}
+	}
+	return nil
+}
+
+// parseUserQuery returns the search query given in the URL query parameters.
+// The filters are given as field=value or field[op]=value, e.g. ?first_name[prefix]=jo&birthday[gte]=1990-01-01&sort=-created_at&limit=10
+func parseUserQuery(values url.Values) (entity.UserQuery, error) {
+	var (
+		query entity.UserQuery
+		err   error
+	)
+	if limit := values.Get("limit"); limit != "" {
+		if query.Limit, err = strconv.Atoi(limit); err != nil {
+			return entity.UserQuery{}, &ParameterErr{Param: "limit", Err: err.Error()}
+		}
+	}
+	if offset := values.Get("offset"); offset != "" {
+		if query.Offset, err = strconv.Atoi(offset); err != nil {
+			return entity.UserQuery{}, &ParameterErr{Param: "offset", Err: err.Error()}
+		}
+	}
+	if sortParam := values.Get("sort"); sortParam != "" {
+		for _, field := range strings.Split(sortParam, ",") {
+			desc := strings.HasPrefix(field, "-")
+			query.Sort = append(query.Sort, entity.Sort{Field: strings.TrimPrefix(field, "-"), Desc: desc})
+		}
+	}
+
+	// The keys are sorted so the filters are built in a stable order.
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if !userQueryParams[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		field, op := key, entity.FilterEq
+		if i := strings.IndexByte(key, '['); i > 0 && strings.HasSuffix(key, "]") {
+			field, op = key[:i], entity.FilterOp(key[i+1:len(key)-1])
+		}
+		for _, value := range values[key] {
+			query.Filters = append(query.Filters, entity.Filter{Field: field, Op: op, Value: value})
+		}
+	}
+	return query, nil
+}
+
+// pageLinks returns the RFC 8288 Link header value pointing to the pages of the cursors given.
+// The links keep the query parameters of the current page, except for the offset and the cursor.
+func pageLinks(u *url.URL, next, prev string) string {
+	links := make([]string, 0, 2)
+	for _, l := range []struct{ rel, cursor string }{{"next", next}, {"prev", prev}} {
+		if l.cursor == "" {
+			continue
+		}
+		query := u.Query()
+		query.Del("offset")
+		query.Set("cursor", l.cursor)
+		links = append(links, fmt.Sprintf(`<%s?%s>; rel="%s"`, u.Path, query.Encode(), l.rel))
 	}
+	return strings.Join(links, ", ")
 }
@@ -0,0 +1,84 @@
+// Code generated by mockery v2.42.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	http "net/http"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Authenticator is an autogenerated mock type for the Authenticator type
+type Authenticator struct {
+	mock.Mock
+}
+
+// Authenticate provides a mock function with given fields: next
+func (_m *Authenticator) Authenticate(next http.Handler) http.Handler {
+	ret := _m.Called(next)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Authenticate")
+	}
+
+	var r0 http.Handler
+	if rf, ok := ret.Get(0).(func(http.Handler) http.Handler); ok {
+		r0 = rf(next)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(http.Handler)
+		}
+	}
+
+	return r0
+}
+
+// IssueAccessToken provides a mock function with given fields: userID, username, role
+func (_m *Authenticator) IssueAccessToken(userID uint64, username string, role string) (string, time.Time, error) {
+	ret := _m.Called(userID, username, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IssueAccessToken")
+	}
+
+	var r0 string
+	var r1 time.Time
+	var r2 error
+	if rf, ok := ret.Get(0).(func(uint64, string, string) (string, time.Time, error)); ok {
+		return rf(userID, username, role)
+	}
+	if rf, ok := ret.Get(0).(func(uint64, string, string) string); ok {
+		r0 = rf(userID, username, role)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint64, string, string) time.Time); ok {
+		r1 = rf(userID, username, role)
+	} else {
+		r1 = ret.Get(1).(time.Time)
+	}
+
+	if rf, ok := ret.Get(2).(func(uint64, string, string) error); ok {
+		r2 = rf(userID, username, role)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// NewAuthenticator creates a new instance of Authenticator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuthenticator(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Authenticator {
+	mock := &Authenticator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
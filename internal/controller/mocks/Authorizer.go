@@ -0,0 +1,68 @@
+// Code generated by mockery v2.42.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	http "net/http"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Authorizer is an autogenerated mock type for the Authorizer type
+type Authorizer struct {
+	mock.Mock
+}
+
+// AuthorizeOwner provides a mock function with given fields: ctx, ownerID, permission
+func (_m *Authorizer) AuthorizeOwner(ctx context.Context, ownerID uint64, permission string) error {
+	ret := _m.Called(ctx, ownerID, permission)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AuthorizeOwner")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) error); ok {
+		r0 = rf(ctx, ownerID, permission)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RequirePermission provides a mock function with given fields: permission
+func (_m *Authorizer) RequirePermission(permission string) func(http.Handler) http.Handler {
+	ret := _m.Called(permission)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequirePermission")
+	}
+
+	var r0 func(http.Handler) http.Handler
+	if rf, ok := ret.Get(0).(func(string) func(http.Handler) http.Handler); ok {
+		r0 = rf(permission)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(func(http.Handler) http.Handler)
+		}
+	}
+
+	return r0
+}
+
+// NewAuthorizer creates a new instance of Authorizer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuthorizer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Authorizer {
+	mock := &Authorizer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
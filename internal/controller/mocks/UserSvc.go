@@ -3,9 +3,12 @@
 package mocks
 
 import (
-	mock "github.com/stretchr/testify/mock"
+	context "context"
+
 	entity "github.com/wizeline/CA-Microservices-Go/internal/entity"
 
+	mock "github.com/stretchr/testify/mock"
+
 	service "github.com/wizeline/CA-Microservices-Go/internal/service"
 )
 
@@ -14,17 +17,17 @@ type UserSvc struct {
 	mock.Mock
 }
 
-// Activate provides a mock function with given fields: id
-func (_m *UserSvc) Activate(id uint64) error {
-	ret := _m.Called(id)
+// Activate provides a mock function with given fields: ctx, id
+func (_m *UserSvc) Activate(ctx context.Context, id uint64) error {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Activate")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(uint64) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -32,17 +35,17 @@ func (_m *UserSvc) Activate(id uint64) error {
 	return r0
 }
 
-// ChangeEmail provides a mock function with given fields: id, email
-func (_m *UserSvc) ChangeEmail(id uint64, email string) error {
-	ret := _m.Called(id, email)
+// ChangeEmail provides a mock function with given fields: ctx, id, email
+func (_m *UserSvc) ChangeEmail(ctx context.Context, id uint64, email string) error {
+	ret := _m.Called(ctx, id, email)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ChangeEmail")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(uint64, string) error); ok {
-		r0 = rf(id, email)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) error); ok {
+		r0 = rf(ctx, id, email)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -50,17 +53,35 @@ func (_m *UserSvc) ChangeEmail(id uint64, email string) error {
 	return r0
 }
 
-// ChangePasswd provides a mock function with given fields: id, passwd
-func (_m *UserSvc) ChangePasswd(id uint64, passwd string) error {
-	ret := _m.Called(id, passwd)
+// ChangePasswd provides a mock function with given fields: ctx, id, currentPasswd, passwd
+func (_m *UserSvc) ChangePasswd(ctx context.Context, id uint64, currentPasswd string, passwd string) error {
+	ret := _m.Called(ctx, id, currentPasswd, passwd)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ChangePasswd")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(uint64, string) error); ok {
-		r0 = rf(id, passwd)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string, string) error); ok {
+		r0 = rf(ctx, id, currentPasswd, passwd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ChangeRole provides a mock function with given fields: ctx, id, role
+func (_m *UserSvc) ChangeRole(ctx context.Context, id uint64, role string) error {
+	ret := _m.Called(ctx, id, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangeRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) error); ok {
+		r0 = rf(ctx, id, role)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -68,17 +89,35 @@ func (_m *UserSvc) ChangePasswd(id uint64, passwd string) error {
 	return r0
 }
 
-// Create provides a mock function with given fields: args
-func (_m *UserSvc) Create(args service.UserCreateArgs) error {
-	ret := _m.Called(args)
+// Create provides a mock function with given fields: ctx, args
+func (_m *UserSvc) Create(ctx context.Context, args service.UserCreateArgs) error {
+	ret := _m.Called(ctx, args)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Create")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(service.UserCreateArgs) error); ok {
-		r0 = rf(args)
+	if rf, ok := ret.Get(0).(func(context.Context, service.UserCreateArgs) error); ok {
+		r0 = rf(ctx, args)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Deactivate provides a mock function with given fields: ctx, id
+func (_m *UserSvc) Deactivate(ctx context.Context, id uint64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Deactivate")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -86,17 +125,17 @@ func (_m *UserSvc) Create(args service.UserCreateArgs) error {
 	return r0
 }
 
-// Delete provides a mock function with given fields: id
-func (_m *UserSvc) Delete(id uint64) error {
-	ret := _m.Called(id)
+// Delete provides a mock function with given fields: ctx, id
+func (_m *UserSvc) Delete(ctx context.Context, id uint64) error {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Delete")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(uint64) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -104,9 +143,9 @@ func (_m *UserSvc) Delete(id uint64) error {
 	return r0
 }
 
-// Find provides a mock function with given fields: filter, value
-func (_m *UserSvc) Find(filter string, value string) ([]entity.User, error) {
-	ret := _m.Called(filter, value)
+// Find provides a mock function with given fields: ctx, filter, value
+func (_m *UserSvc) Find(ctx context.Context, filter string, value string) ([]entity.User, error) {
+	ret := _m.Called(ctx, filter, value)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Find")
@@ -114,19 +153,19 @@ func (_m *UserSvc) Find(filter string, value string) ([]entity.User, error) {
 
 	var r0 []entity.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string) ([]entity.User, error)); ok {
-		return rf(filter, value)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]entity.User, error)); ok {
+		return rf(ctx, filter, value)
 	}
-	if rf, ok := ret.Get(0).(func(string, string) []entity.User); ok {
-		r0 = rf(filter, value)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []entity.User); ok {
+		r0 = rf(ctx, filter, value)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(filter, value)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, filter, value)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -134,9 +173,9 @@ func (_m *UserSvc) Find(filter string, value string) ([]entity.User, error) {
 	return r0, r1
 }
 
-// Get provides a mock function with given fields: id
-func (_m *UserSvc) Get(id uint64) (service.UserResponse, error) {
-	ret := _m.Called(id)
+// Get provides a mock function with given fields: ctx, id
+func (_m *UserSvc) Get(ctx context.Context, id uint64) (service.UserResponse, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Get")
@@ -144,17 +183,17 @@ func (_m *UserSvc) Get(id uint64) (service.UserResponse, error) {
 
 	var r0 service.UserResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(uint64) (service.UserResponse, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (service.UserResponse, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(uint64) service.UserResponse); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) service.UserResponse); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Get(0).(service.UserResponse)
 	}
 
-	if rf, ok := ret.Get(1).(func(uint64) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -162,9 +201,9 @@ func (_m *UserSvc) Get(id uint64) (service.UserResponse, error) {
 	return r0, r1
 }
 
-// GetAll provides a mock function with given fields:
-func (_m *UserSvc) GetAll() ([]service.UserResponse, error) {
-	ret := _m.Called()
+// GetAll provides a mock function with given fields: ctx
+func (_m *UserSvc) GetAll(ctx context.Context) ([]service.UserResponse, error) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAll")
@@ -172,19 +211,19 @@ func (_m *UserSvc) GetAll() ([]service.UserResponse, error) {
 
 	var r0 []service.UserResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]service.UserResponse, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context) ([]service.UserResponse, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func() []service.UserResponse); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []service.UserResponse); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]service.UserResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -192,9 +231,9 @@ func (_m *UserSvc) GetAll() ([]service.UserResponse, error) {
 	return r0, r1
 }
 
-// IsActive provides a mock function with given fields: id
-func (_m *UserSvc) IsActive(id uint64) (bool, error) {
-	ret := _m.Called(id)
+// IsActive provides a mock function with given fields: ctx, id
+func (_m *UserSvc) IsActive(ctx context.Context, id uint64) (bool, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for IsActive")
@@ -202,17 +241,45 @@ func (_m *UserSvc) IsActive(id uint64) (bool, error) {
 
 	var r0 bool
 	var r1 error
-	if rf, ok := ret.Get(0).(func(uint64) (bool, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (bool, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(uint64) bool); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) bool); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(uint64) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Search provides a mock function with given fields: ctx, query, cursor
+func (_m *UserSvc) Search(ctx context.Context, query entity.UserQuery, cursor string) (service.UserSearchResponse, error) {
+	ret := _m.Called(ctx, query, cursor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Search")
+	}
+
+	var r0 service.UserSearchResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.UserQuery, string) (service.UserSearchResponse, error)); ok {
+		return rf(ctx, query, cursor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.UserQuery, string) service.UserSearchResponse); ok {
+		r0 = rf(ctx, query, cursor)
+	} else {
+		r0 = ret.Get(0).(service.UserSearchResponse)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.UserQuery, string) error); ok {
+		r1 = rf(ctx, query, cursor)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -220,17 +287,17 @@ func (_m *UserSvc) IsActive(id uint64) (bool, error) {
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: args
-func (_m *UserSvc) Update(args service.UserUpdateArgs) error {
-	ret := _m.Called(args)
+// Update provides a mock function with given fields: ctx, args
+func (_m *UserSvc) Update(ctx context.Context, args service.UserUpdateArgs) error {
+	ret := _m.Called(ctx, args)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Update")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(service.UserUpdateArgs) error); ok {
-		r0 = rf(args)
+	if rf, ok := ret.Get(0).(func(context.Context, service.UserUpdateArgs) error); ok {
+		r0 = rf(ctx, args)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -238,9 +305,9 @@ func (_m *UserSvc) Update(args service.UserUpdateArgs) error {
 	return r0
 }
 
-// ValidateLogin provides a mock function with given fields: username, passwd
-func (_m *UserSvc) ValidateLogin(username string, passwd string) (service.UserLoginResponse, error) {
-	ret := _m.Called(username, passwd)
+// ValidateLogin provides a mock function with given fields: ctx, username, passwd
+func (_m *UserSvc) ValidateLogin(ctx context.Context, username string, passwd string) (service.UserLoginResponse, error) {
+	ret := _m.Called(ctx, username, passwd)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ValidateLogin")
@@ -248,17 +315,17 @@ func (_m *UserSvc) ValidateLogin(username string, passwd string) (service.UserLo
 
 	var r0 service.UserLoginResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string) (service.UserLoginResponse, error)); ok {
-		return rf(username, passwd)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (service.UserLoginResponse, error)); ok {
+		return rf(ctx, username, passwd)
 	}
-	if rf, ok := ret.Get(0).(func(string, string) service.UserLoginResponse); ok {
-		r0 = rf(username, passwd)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) service.UserLoginResponse); ok {
+		r0 = rf(ctx, username, passwd)
 	} else {
 		r0 = ret.Get(0).(service.UserLoginResponse)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(username, passwd)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, username, passwd)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1,5 +1,12 @@
 package controller
 
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi"
+)
+
 type httpRequestTest struct {
 	params  map[string]string
 	payload []byte
@@ -9,3 +16,12 @@ type httpResponseTest struct {
 	code int
 	body string
 }
+
+// withURLParams returns a copy of the request carrying the given chi URL parameters.
+func withURLParams(r *http.Request, params map[string]string) *http.Request {
+	rctx := chi.NewRouteContext()
+	for k, v := range params {
+		rctx.URLParams.Add(k, v)
+	}
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+}
@@ -0,0 +1,26 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// The supported database drivers.
+// The memory driver keeps the data in the process, it is intended for demos and end-to-end tests.
+const (
+	DriverPostgres = "postgres"
+	DriverMySQL    = "mysql"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory"
+)
+
+var (
+	ErrDriverUnknown = errors.New("unknown database driver")
+	ErrDriverNotSQL  = errors.New("driver without SQL database")
+)
+
+// Conn is the connection of a SQL database.
+type Conn interface {
+	DB() *sql.DB
+	Close() error
+}
@@ -0,0 +1,100 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// lockKey identifies the advisory lock serializing the migrations of the database instances.
+// PostgreSQL scopes the advisory locks to the current database, so a single key is shared by every service.
+const lockKey int64 = 0x63616d676f6d6967
+
+// lockName prefixes the name of the MySQL lock serializing the migrations, the database name is appended
+// since the MySQL named locks are shared by every database of the server.
+const lockName = "camgo_migrations:"
+
+// Dialect holds what differs between the database engines: the directory of their SQL files,
+// the placeholders of the statements and how the instances serialize their migrations.
+type Dialect struct {
+	name        string
+	placeholder func(n int) string
+	lock        func(ctx context.Context, conn *sql.Conn) error
+	unlock      func(ctx context.Context, conn *sql.Conn) error
+}
+
+var (
+	// Postgres serializes the migrations of the instances through an advisory lock.
+	Postgres = Dialect{
+		name:        "postgres",
+		placeholder: func(n int) string { return "$" + strconv.Itoa(n) },
+		lock: func(ctx context.Context, conn *sql.Conn) error {
+			_, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockKey)
+			return err
+		},
+		unlock: func(ctx context.Context, conn *sql.Conn) error {
+			_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", lockKey)
+			return err
+		},
+	}
+	// MySQL serializes the migrations of the instances through a named lock.
+	// MySQL commits the DDL statements implicitly, so a migration failing halfway is not rolled back.
+	MySQL = Dialect{
+		name: "mysql",
+		lock: func(ctx context.Context, conn *sql.Conn) error {
+			// A negative timeout waits without limit, the context deadline bounds the wait otherwise.
+			timeout := -1.0
+			if deadline, ok := ctx.Deadline(); ok {
+				timeout = time.Until(deadline).Seconds()
+			}
+			var acquired sql.NullInt64
+			row := conn.QueryRowContext(ctx, "SELECT GET_LOCK(CONCAT(?, DATABASE()), ?)", lockName, timeout)
+			if err := row.Scan(&acquired); err != nil {
+				return err
+			}
+			switch {
+			case !acquired.Valid:
+				return fmt.Errorf("named lock %s failed", lockName)
+			case acquired.Int64 != 1:
+				return fmt.Errorf("named lock %s not acquired: %w", lockName, context.DeadlineExceeded)
+			}
+			return nil
+		},
+		unlock: func(ctx context.Context, conn *sql.Conn) error {
+			_, err := conn.ExecContext(ctx, "SELECT RELEASE_LOCK(CONCAT(?, DATABASE()))", lockName)
+			return err
+		},
+	}
+	// SQLite takes no lock, a database file belongs to a single instance and its transactions are serialized.
+	SQLite = Dialect{
+		name: "sqlite",
+	}
+)
+
+// Name returns the name of the dialect, it is the directory of its embedded SQL files. e.g. "postgres"
+func (d Dialect) Name() string {
+	return d.name
+}
+
+// rebind replaces the "?" placeholders of the statement with the ones of the dialect.
+func (d Dialect) rebind(query string) string {
+	if d.placeholder == nil {
+		return query
+	}
+	var (
+		b strings.Builder
+		n int
+	)
+	for _, r := range query {
+		if r != '?' {
+			b.WriteRune(r)
+			continue
+		}
+		n++
+		b.WriteString(d.placeholder(n))
+	}
+	return b.String()
+}
@@ -1 +1,121 @@
 package migration
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Err is returned when the database fails outside the SQL statements of a migration,
+// e.g. the schema_migrations table cannot be read.
+type Err struct {
+	Err error
+}
+
+func (e Err) Error() string {
+	return fmt.Sprintf("migration: %s", e.Err)
+}
+
+func (e Err) Unwrap() error {
+	return e.Err
+}
+
+// ReadFileErr is returned when the migration files cannot be read.
+type ReadFileErr struct {
+	Path string
+	Err  error
+}
+
+func (e ReadFileErr) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("failed reading migrations directory: %s", e.Err)
+	}
+	return fmt.Sprintf("failed reading migration file %v: %s", e.Path, e.Err)
+}
+
+func (e ReadFileErr) Unwrap() error {
+	return e.Err
+}
+
+// ApplyErr is returned when the SQL statements of a migration fail, the transaction is rolled back.
+// Line and Position locate the failing statement within the SQL file when the database reports it,
+// they are zero otherwise.
+type ApplyErr struct {
+	Version   uint64
+	Name      string
+	Revert    bool
+	Line      int
+	Position  int
+	Statement string
+	Err       error
+}
+
+func (e ApplyErr) Error() string {
+	action := "applying"
+	if e.Revert {
+		action = "reverting"
+	}
+	if e.Line == 0 {
+		return fmt.Sprintf("failed %s migration %d %s: %s", action, e.Version, e.Name, e.Err)
+	}
+	return fmt.Sprintf("failed %s migration %d %s at line %d: %s", action, e.Version, e.Name, e.Line, e.Err)
+}
+
+func (e ApplyErr) Unwrap() error {
+	return e.Err
+}
+
+// ChecksumMismatchErr is returned when the file of an applied migration has been modified.
+type ChecksumMismatchErr struct {
+	Version  uint64
+	Name     string
+	Recorded string
+	Found    string
+}
+
+func (e ChecksumMismatchErr) Error() string {
+	return fmt.Sprintf("migration %d %s: %s (recorded %s, found %s)", e.Version, e.Name, ErrChecksumMismatch, e.Recorded, e.Found)
+}
+
+func (e ChecksumMismatchErr) Unwrap() error {
+	return ErrChecksumMismatch
+}
+
+// LockTimeoutErr is returned when the migrations lock held by another instance is not released in time.
+type LockTimeoutErr struct {
+	Timeout time.Duration
+	Err     error
+}
+
+func (e LockTimeoutErr) Error() string {
+	return fmt.Sprintf("timed out after %s waiting for the migrations lock: %s", e.Timeout, e.Err)
+}
+
+func (e LockTimeoutErr) Unwrap() error {
+	return e.Err
+}
+
+// locate returns the line of the 1-based character position within the SQL content,
+// along with the statement enclosing it. The statements are delimited by semicolons.
+func locate(sqlContent string, position int) (int, string) {
+	if position < 1 || position > utf8.RuneCountInString(sqlContent) {
+		return 0, ""
+	}
+	offset := len(sqlContent)
+	for i := range sqlContent {
+		if position == 1 {
+			offset = i
+			break
+		}
+		position--
+	}
+
+	line := strings.Count(sqlContent[:offset], "\n") + 1
+	start := strings.LastIndex(sqlContent[:offset], ";") + 1
+	end := len(sqlContent)
+	if i := strings.Index(sqlContent[offset:], ";"); i >= 0 {
+		end = offset + i + 1
+	}
+	return line, strings.TrimSpace(sqlContent[start:end])
+}
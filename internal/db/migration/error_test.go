@@ -0,0 +1,46 @@
+package migration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocate(t *testing.T) {
+	const sqlContent = "CREATE TABLE users (id INT);\n\nCREATE INDEX users_email ON users (emial);\n"
+
+	tests := []struct {
+		name      string
+		position  int
+		line      int
+		statement string
+	}{
+		{
+			name:      "First statement",
+			position:  8,
+			line:      1,
+			statement: "CREATE TABLE users (id INT);",
+		},
+		{
+			name:      "Later statement",
+			position:  66,
+			line:      3,
+			statement: "CREATE INDEX users_email ON users (emial);",
+		},
+		{
+			name:     "Unknown position",
+			position: 0,
+		},
+		{
+			name:     "Out of range",
+			position: 500,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line, statement := locate(sqlContent, tt.position)
+			assert.Equal(t, tt.line, line)
+			assert.Equal(t, tt.statement, statement)
+		})
+	}
+}
@@ -1,39 +1,569 @@
 package migration
 
 import (
+	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
 
+	"github.com/lib/pq"
 	"github.com/wizeline/CA-Microservices-Go/internal/logger"
 )
 
-const migrationsDir = "./internal/db/migration/v1"
+var (
+	ErrChecksumMismatch  = errors.New("checksum mismatch, the applied migration file has been modified")
+	ErrVersionDuplicated = errors.New("version duplicated")
+	ErrVersionUnknown    = errors.New("unknown version")
+	ErrMigrationMissing  = errors.New("applied migration file missing")
+	ErrDownMissing       = errors.New("down migration file missing")
+	ErrMigrationPending  = errors.New("migration pending")
+)
 
+// Migration is a versioned change of the database schema, made of the SQL statements applying and reverting it.
 type Migration struct {
-	name     string
-	filename string
-	Up       func(db *sql.DB, sqlContent string) error
-	Down     func(db *sql.DB, sqlContent string) error
+	version uint64
+	name    string
+	up      string
+	down    string
 }
 
-// Run applies the given migration functions
-func Run(db *sql.DB, migrations []Migration, l logger.ZeroLog) error {
-	for _, m := range migrations {
-		l.Log().Debug().Str("name", m.name).Msg("applying migration")
+// Version returns the version of the migration, it sets the order the migrations are applied in.
+func (m Migration) Version() uint64 {
+	return m.version
+}
+
+// Name returns the name of the migration. e.g. "create_users_table"
+func (m Migration) Name() string {
+	return m.name
+}
+
+// Record is the schema_migrations entry of an applied migration.
+type Record struct {
+	Version       uint64
+	Name          string
+	Checksum      string
+	AppliedAt     time.Time
+	ExecutionTime time.Duration
+}
+
+// State describes whether a migration has been applied.
+// Missing is set for the applied migrations whose files are not found anymore.
+type State struct {
+	Version uint64
+	Name    string
+	Applied bool
+	Missing bool
+	Record  Record
+}
+
+// Migrator applies and reverts the migrations, keeping track of them in the schema_migrations table.
+// The changes are serialized through the lock of the dialect, so several instances can migrate the same database at once:
+// the first one applies the pending migrations while the others wait, and find nothing left to apply.
+type Migrator struct {
+	db          *sql.DB
+	dialect     Dialect
+	migrations  []Migration
+	lockTimeout time.Duration
+	logger      logger.ZeroLog
+}
+
+// NewMigrator returns a Migrator of the given migrations.
+// The lock timeout bounds the wait for the migrations lock, a zero or negative value waits without limit.
+func NewMigrator(db *sql.DB, dialect Dialect, migrations []Migration, lockTimeout time.Duration, l logger.ZeroLog) Migrator {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].version < sorted[j].version })
+
+	return Migrator{
+		db:          db,
+		dialect:     dialect,
+		migrations:  sorted,
+		lockTimeout: lockTimeout,
+		logger:      l,
+	}
+}
+
+// Run applies the pending migrations in version order, each one inside its own transaction.
+// The applied migrations are recorded in the schema_migrations table, and it fails when the
+// checksum of an applied migration file has changed.
+func Run(db *sql.DB, dialect Dialect, migrations []Migration, lockTimeout time.Duration, l logger.ZeroLog) error {
+	return NewMigrator(db, dialect, migrations, lockTimeout, l).Up(0)
+}
+
+// Up applies the first n pending migrations, every pending migration is applied when n is zero.
+func (m Migrator) Up(n int) error {
+	return m.withLock(func() error {
+		return m.up(n)
+	})
+}
+
+func (m Migrator) up(n int) error {
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+	pending, err := plan(m.migrations, applied)
+	if err != nil {
+		return err
+	}
+	if n > 0 && n < len(pending) {
+		pending = pending[:n]
+	}
+	if len(pending) == 0 {
+		m.logger.Log().Debug().Msg("database schema up to date")
+		return nil
+	}
+	for _, mig := range pending {
+		if err := m.apply(mig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Down reverts the last n applied migrations, every applied migration is reverted when n is zero.
+func (m Migrator) Down(n int) error {
+	return m.withLock(func() error {
+		return m.down(n)
+	})
+}
+
+func (m Migrator) down(n int) error {
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+	reverts, err := rollbackPlan(m.migrations, applied, n)
+	if err != nil {
+		return err
+	}
+	for _, mig := range reverts {
+		if err := m.revert(mig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Goto applies or reverts the migrations until the given version is the last one applied.
+// The version zero reverts every applied migration.
+func (m Migrator) Goto(version uint64) error {
+	return m.withLock(func() error {
+		return m.goTo(version)
+	})
+}
+
+func (m Migrator) goTo(version uint64) error {
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+	ups, downs, err := gotoPlan(m.migrations, applied, version)
+	if err != nil {
+		return err
+	}
+	for _, mig := range downs {
+		if err := m.revert(mig); err != nil {
+			return err
+		}
+	}
+	for _, mig := range ups {
+		if err := m.apply(mig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Redo reverts the last applied migration and applies it again.
+func (m Migrator) Redo() error {
+	return m.withLock(m.redo)
+}
+
+func (m Migrator) redo() error {
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+	reverts, err := rollbackPlan(m.migrations, applied, 1)
+	if err != nil {
+		return err
+	}
+	for _, mig := range reverts {
+		if err := m.revert(mig); err != nil {
+			return err
+		}
+		if err := m.apply(mig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Force records the migrations up to the given version as applied, and the later ones as pending,
+// without running any SQL statement. The checksums of the recorded migrations are refreshed.
+// It is intended to recover a database whose schema has been changed by hand.
+func (m Migrator) Force(version uint64) error {
+	return m.withLock(func() error {
+		return m.force(version)
+	})
+}
+
+func (m Migrator) force(version uint64) error {
+	if version != 0 && !m.known(version) {
+		return fmt.Errorf("migration %d: %w", version, ErrVersionUnknown)
+	}
+	if err := createSchemaMigrations(m.db); err != nil {
+		return &Err{Err: fmt.Errorf("failed creating schema_migrations table: %w", err)}
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return &Err{Err: err}
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(m.dialect.rebind("DELETE FROM schema_migrations WHERE version > ?"), version); err != nil {
+		return &Err{Err: err}
+	}
+	for _, mig := range m.migrations {
+		if mig.version > version {
+			break
+		}
+		var recorded int
+		row := tx.QueryRow(m.dialect.rebind("SELECT COUNT(*) FROM schema_migrations WHERE version = ?"), mig.version)
+		if err := row.Scan(&recorded); err != nil {
+			return &Err{Err: err}
+		}
+		query := "INSERT INTO schema_migrations (name, checksum, version, execution_time_ms) VALUES (?, ?, ?, 0)"
+		if recorded > 0 {
+			query = "UPDATE schema_migrations SET name = ?, checksum = ? WHERE version = ?"
+		}
+		if _, err := tx.Exec(m.dialect.rebind(query), mig.name, checksum(mig.up), mig.version); err != nil {
+			return &Err{Err: err}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return &Err{Err: err}
+	}
+
+	m.logger.Log().Warn().Uint64("version", version).Msg("migration version forced")
+	return nil
+}
+
+// Status returns the state of every known and applied migration sorted by version.
+func (m Migrator) Status() ([]State, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	return status(m.migrations, applied), nil
+}
+
+// Check verifies the database schema is up to date: it fails when a known migration is not applied,
+// or when the checksum of an applied migration file has changed. The applied migrations whose files are missing are not reported.
+// It reads the schema_migrations table without taking the migrations lock, so it can run while the instances serve requests.
+func (m Migrator) Check(ctx context.Context) error {
+	applied, err := readApplied(ctx, m.db)
+	if err != nil {
+		return &Err{Err: fmt.Errorf("failed reading applied migrations: %w", err)}
+	}
+	pending, err := plan(m.migrations, applied)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%d migrations from version %d: %w", len(pending), pending[0].version, ErrMigrationPending)
+	}
+	return nil
+}
+
+// withLock runs fn holding the migrations lock of the dialect, fn runs right away when the dialect takes no lock.
+// The lock is taken on a dedicated connection, and released even when fn fails or panics.
+func (m Migrator) withLock(fn func() error) (err error) {
+	if m.dialect.lock == nil {
+		return fn()
+	}
 
-		path := filepath.Join(migrationsDir, m.filename)
-		sqlContent, err := os.ReadFile(path)
-		if err != nil {
-			// TODO: convert it to error migration type
-			return fmt.Errorf("failed reading SQL file %s: %s", m.filename, err)
+	ctx := context.Background()
+	if m.lockTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.lockTimeout)
+		defer cancel()
+	}
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return &Err{Err: err}
+	}
+	defer conn.Close()
+
+	m.logger.Log().Debug().Dur("timeout", m.lockTimeout).Msg("acquiring migrations lock")
+	if err := m.dialect.lock(ctx, conn); err != nil {
+		if ctx.Err() != nil || errors.Is(err, context.DeadlineExceeded) {
+			return &LockTimeoutErr{Timeout: m.lockTimeout, Err: err}
+		}
+		return &Err{Err: err}
+	}
+	defer func() {
+		// The lock timeout may have elapsed by now, the lock is released with a fresh context.
+		unlockErr := m.dialect.unlock(context.Background(), conn)
+		if unlockErr != nil && err == nil {
+			err = &Err{Err: unlockErr}
 		}
+	}()
+
+	return fn()
+}
 
-		if err := m.Up(db, string(sqlContent)); err != nil {
-			// TODO: convert it to error migration type
-			return fmt.Errorf("failed applying migration %s: %s", m.name, err)
+func (m Migrator) known(version uint64) bool {
+	for _, mig := range m.migrations {
+		if mig.version == version {
+			return true
 		}
 	}
+	return false
+}
+
+// applied returns the applied migrations records, the schema_migrations table is created when missing.
+func (m Migrator) applied() (map[uint64]Record, error) {
+	if err := createSchemaMigrations(m.db); err != nil {
+		return nil, &Err{Err: fmt.Errorf("failed creating schema_migrations table: %w", err)}
+	}
+	applied, err := readApplied(context.Background(), m.db)
+	if err != nil {
+		return nil, &Err{Err: fmt.Errorf("failed reading applied migrations: %w", err)}
+	}
+	return applied, nil
+}
+
+// apply runs the migration and records it within the same transaction.
+func (m Migrator) apply(mig Migration) error {
+	m.logger.Log().Debug().Uint64("version", mig.version).Str("name", mig.name).Msg("applying migration")
+
+	elapsed, err := m.exec(mig, false, func(tx *sql.Tx, elapsed time.Duration) error {
+		_, err := tx.Exec(m.dialect.rebind("INSERT INTO schema_migrations (version, name, checksum, execution_time_ms) VALUES (?, ?, ?, ?)"),
+			mig.version, mig.name, checksum(mig.up), elapsed.Milliseconds(),
+		)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	m.logger.Log().Info().
+		Uint64("version", mig.version).
+		Str("name", mig.name).
+		Dur("elapsed", elapsed).
+		Msg("migration applied")
+	return nil
+}
+
+// revert runs the down migration and removes its record within the same transaction.
+func (m Migrator) revert(mig Migration) error {
+	m.logger.Log().Debug().Uint64("version", mig.version).Str("name", mig.name).Msg("reverting migration")
+
+	elapsed, err := m.exec(mig, true, func(tx *sql.Tx, _ time.Duration) error {
+		_, err := tx.Exec(m.dialect.rebind("DELETE FROM schema_migrations WHERE version = ?"), mig.version)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	m.logger.Log().Info().
+		Uint64("version", mig.version).
+		Str("name", mig.name).
+		Dur("elapsed", elapsed).
+		Msg("migration reverted")
 	return nil
 }
+
+// exec runs the up or down SQL statements of the migration and the bookkeeping function inside a single transaction.
+func (m Migrator) exec(mig Migration, revert bool, record func(tx *sql.Tx, elapsed time.Duration) error) (time.Duration, error) {
+	sqlContent := mig.up
+	if revert {
+		sqlContent = mig.down
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return 0, &Err{Err: err}
+	}
+	defer tx.Rollback()
+
+	start := time.Now()
+	if _, err := tx.Exec(sqlContent); err != nil {
+		applyErr := &ApplyErr{Version: mig.version, Name: mig.name, Revert: revert, Err: err}
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			applyErr.Position, _ = strconv.Atoi(pqErr.Position)
+			applyErr.Line, applyErr.Statement = locate(sqlContent, applyErr.Position)
+		}
+		return 0, applyErr
+	}
+	elapsed := time.Since(start)
+
+	if err := record(tx, elapsed); err != nil {
+		return 0, &ApplyErr{Version: mig.version, Name: mig.name, Revert: revert, Err: err}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, &ApplyErr{Version: mig.version, Name: mig.name, Revert: revert, Err: err}
+	}
+	return elapsed, nil
+}
+
+// plan returns the migrations not applied yet sorted by version.
+// It verifies the checksums of the applied migrations against their current SQL contents.
+func plan(migrations []Migration, applied map[uint64]Record) ([]Migration, error) {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].version < sorted[j].version })
+
+	pending := make([]Migration, 0, len(sorted))
+	for i, m := range sorted {
+		if i > 0 && sorted[i-1].version == m.version {
+			return nil, fmt.Errorf("migration %d %s: %w", m.version, m.name, ErrVersionDuplicated)
+		}
+		record, ok := applied[m.version]
+		if !ok {
+			pending = append(pending, m)
+			continue
+		}
+		if sum := checksum(m.up); sum != record.Checksum {
+			return nil, &ChecksumMismatchErr{Version: m.version, Name: m.name, Recorded: record.Checksum, Found: sum}
+		}
+	}
+	return pending, nil
+}
+
+// rollbackPlan returns the last n applied migrations sorted by version in descending order,
+// every applied migration is returned when n is zero.
+func rollbackPlan(migrations []Migration, applied map[uint64]Record, n int) ([]Migration, error) {
+	byVersion := make(map[uint64]Migration, len(migrations))
+	for _, m := range migrations {
+		byVersion[m.version] = m
+	}
+	versions := make([]uint64, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+	if n > 0 && n < len(versions) {
+		versions = versions[:n]
+	}
+
+	reverts := make([]Migration, 0, len(versions))
+	for _, version := range versions {
+		m, ok := byVersion[version]
+		if !ok {
+			return nil, fmt.Errorf("migration %d %s: %w", version, applied[version].Name, ErrMigrationMissing)
+		}
+		if m.down == "" {
+			return nil, fmt.Errorf("migration %d %s: %w", m.version, m.name, ErrDownMissing)
+		}
+		reverts = append(reverts, m)
+	}
+	return reverts, nil
+}
+
+// gotoPlan returns the pending migrations up to the version to apply, and the applied migrations
+// after the version to revert.
+func gotoPlan(migrations []Migration, applied map[uint64]Record, version uint64) ([]Migration, []Migration, error) {
+	known := version == 0
+	for _, m := range migrations {
+		known = known || m.version == version
+	}
+	if !known {
+		return nil, nil, fmt.Errorf("migration %d: %w", version, ErrVersionUnknown)
+	}
+
+	pending, err := plan(migrations, applied)
+	if err != nil {
+		return nil, nil, err
+	}
+	ups := make([]Migration, 0, len(pending))
+	for _, m := range pending {
+		if m.version <= version {
+			ups = append(ups, m)
+		}
+	}
+
+	later := make(map[uint64]Record)
+	for v, record := range applied {
+		if v > version {
+			later[v] = record
+		}
+	}
+	downs, err := rollbackPlan(migrations, later, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ups, downs, nil
+}
+
+// status merges the known and the applied migrations into their states sorted by version.
+func status(migrations []Migration, applied map[uint64]Record) []State {
+	states := make([]State, 0, len(migrations))
+	known := make(map[uint64]bool, len(migrations))
+	for _, m := range migrations {
+		record, ok := applied[m.version]
+		states = append(states, State{Version: m.version, Name: m.name, Applied: ok, Record: record})
+		known[m.version] = true
+	}
+	for version, record := range applied {
+		if !known[version] {
+			states = append(states, State{Version: version, Name: record.Name, Applied: true, Missing: true, Record: record})
+		}
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Version < states[j].Version })
+	return states
+}
+
+func createSchemaMigrations(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name VARCHAR (255) NOT NULL,
+			checksum VARCHAR (64) NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			execution_time_ms BIGINT NOT NULL
+		)`)
+	return err
+}
+
+func readApplied(ctx context.Context, db *sql.DB) (map[uint64]Record, error) {
+	rows, err := db.QueryContext(ctx, "SELECT version, name, checksum, applied_at, execution_time_ms FROM schema_migrations ORDER BY version")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[uint64]Record)
+	for rows.Next() {
+		var (
+			record Record
+			execMs int64
+		)
+		if err := rows.Scan(&record.Version, &record.Name, &record.Checksum, &record.AppliedAt, &execMs); err != nil {
+			return nil, err
+		}
+		record.ExecutionTime = time.Duration(execMs) * time.Millisecond
+		applied[record.Version] = record
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+// checksum returns the hex encoded SHA-256 digest of the SQL content.
+func checksum(sqlContent string) string {
+	sum := sha256.Sum256([]byte(sqlContent))
+	return hex.EncodeToString(sum[:])
+}
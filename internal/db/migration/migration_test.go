@@ -0,0 +1,357 @@
+package migration
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/config"
+	"github.com/wizeline/CA-Microservices-Go/internal/db"
+	"github.com/wizeline/CA-Microservices-Go/internal/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlan(t *testing.T) {
+	users := Migration{version: 1, name: "create_users_table", up: "CREATE TABLE users ();"}
+	tokens := Migration{version: 2, name: "create_refresh_tokens_table", up: "CREATE TABLE refresh_tokens ();"}
+	roles := Migration{version: 3, name: "create_roles_tables", up: "CREATE TABLE roles ();"}
+
+	tests := []struct {
+		name       string
+		migrations []Migration
+		applied    map[uint64]Record
+		exp        []string
+		err        error
+	}{
+		{
+			name:       "Fresh database",
+			migrations: []Migration{roles, users, tokens},
+			applied:    map[uint64]Record{},
+			exp:        []string{"create_users_table", "create_refresh_tokens_table", "create_roles_tables"},
+		},
+		{
+			name:       "Pending migrations",
+			migrations: []Migration{users, tokens, roles},
+			applied: map[uint64]Record{
+				1: {Version: 1, Checksum: checksum(users.up)},
+			},
+			exp: []string{"create_refresh_tokens_table", "create_roles_tables"},
+		},
+		{
+			name:       "Up to date",
+			migrations: []Migration{users, tokens},
+			applied: map[uint64]Record{
+				1: {Version: 1, Checksum: checksum(users.up)},
+				2: {Version: 2, Checksum: checksum(tokens.up)},
+			},
+			exp: []string{},
+		},
+		{
+			name:       "Modified file",
+			migrations: []Migration{users, tokens},
+			applied: map[uint64]Record{
+				1: {Version: 1, Checksum: checksum("CREATE TABLE users (id INT);")},
+			},
+			err: ErrChecksumMismatch,
+		},
+		{
+			name:       "Duplicated version",
+			migrations: []Migration{users, {version: 1, name: "create_accounts_table"}},
+			applied:    map[uint64]Record{},
+			err:        ErrVersionDuplicated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := plan(tt.migrations, tt.applied)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(out))
+			for _, m := range out {
+				names = append(names, m.name)
+			}
+			assert.Equal(t, tt.exp, names)
+		})
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+		exp  []Migration
+		err  error
+	}{
+		{
+			name: "Pairs",
+			fsys: fstest.MapFS{
+				"002_create_refresh_tokens_table.up.sql":   {Data: []byte("CREATE TABLE refresh_tokens ();")},
+				"001_create_users_table.up.sql":            {Data: []byte("CREATE TABLE users ();")},
+				"001_create_users_table.down.sql":          {Data: []byte("DROP TABLE users;")},
+				"002_create_refresh_tokens_table.down.sql": {Data: []byte("DROP TABLE refresh_tokens;")},
+				"README.md": {Data: []byte("# migrations")},
+			},
+			exp: []Migration{
+				{version: 1, name: "create_users_table", up: "CREATE TABLE users ();", down: "DROP TABLE users;"},
+				{version: 2, name: "create_refresh_tokens_table", up: "CREATE TABLE refresh_tokens ();", down: "DROP TABLE refresh_tokens;"},
+			},
+		},
+		{
+			name: "Up file missing",
+			fsys: fstest.MapFS{
+				"001_create_users_table.down.sql": {Data: []byte("DROP TABLE users;")},
+			},
+			err: ErrUpMissing,
+		},
+		{
+			name: "Duplicated version",
+			fsys: fstest.MapFS{
+				"001_create_users_table.up.sql":    {Data: []byte("CREATE TABLE users ();")},
+				"001_create_accounts_table.up.sql": {Data: []byte("CREATE TABLE accounts ();")},
+			},
+			err: ErrVersionDuplicated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := discover(tt.fsys)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.exp, out)
+		})
+	}
+}
+
+func TestLoad_embedded(t *testing.T) {
+	pgMigrations, err := Load(Postgres, "")
+	require.NoError(t, err)
+	require.NotEmpty(t, pgMigrations)
+
+	for _, dialect := range []Dialect{Postgres, MySQL, SQLite} {
+		t.Run(dialect.Name(), func(t *testing.T) {
+			migrations, err := Load(dialect, "")
+			require.NoError(t, err)
+			require.Len(t, migrations, len(pgMigrations), "the dialects share the same migrations")
+
+			for i, m := range migrations {
+				assert.Equal(t, uint64(i+1), m.Version())
+				assert.Equal(t, pgMigrations[i].Name(), m.Name())
+				assert.NotEmpty(t, m.up, m.Name())
+				assert.NotEmpty(t, m.down, m.Name())
+			}
+		})
+	}
+}
+
+func TestDialect_rebind(t *testing.T) {
+	const query = "INSERT INTO schema_migrations (version, name) VALUES (?, ?)"
+	assert.Equal(t, "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", Postgres.rebind(query))
+	assert.Equal(t, query, MySQL.rebind(query))
+	assert.Equal(t, query, SQLite.rebind(query))
+}
+
+func TestRollbackPlan(t *testing.T) {
+	users := Migration{version: 1, name: "create_users_table", up: "CREATE TABLE users ();", down: "DROP TABLE users;"}
+	tokens := Migration{version: 2, name: "create_refresh_tokens_table", up: "CREATE TABLE refresh_tokens ();", down: "DROP TABLE refresh_tokens;"}
+	roles := Migration{version: 3, name: "create_roles_tables", up: "CREATE TABLE roles ();"}
+	applied := map[uint64]Record{
+		1: {Version: 1, Name: users.name},
+		2: {Version: 2, Name: tokens.name},
+	}
+
+	tests := []struct {
+		name       string
+		migrations []Migration
+		applied    map[uint64]Record
+		n          int
+		exp        []uint64
+		err        error
+	}{
+		{
+			name:       "Last one",
+			migrations: []Migration{users, tokens, roles},
+			applied:    applied,
+			n:          1,
+			exp:        []uint64{2},
+		},
+		{
+			name:       "Every applied",
+			migrations: []Migration{users, tokens, roles},
+			applied:    applied,
+			n:          0,
+			exp:        []uint64{2, 1},
+		},
+		{
+			name:       "Nothing applied",
+			migrations: []Migration{users, tokens, roles},
+			applied:    map[uint64]Record{},
+			n:          1,
+			exp:        []uint64{},
+		},
+		{
+			name:       "Applied file missing",
+			migrations: []Migration{users},
+			applied:    applied,
+			n:          1,
+			err:        ErrMigrationMissing,
+		},
+		{
+			name:       "Down file missing",
+			migrations: []Migration{users, tokens, roles},
+			applied:    map[uint64]Record{3: {Version: 3, Name: roles.name}},
+			n:          1,
+			err:        ErrDownMissing,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := rollbackPlan(tt.migrations, tt.applied, tt.n)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.exp, versions(out))
+		})
+	}
+}
+
+func TestGotoPlan(t *testing.T) {
+	users := Migration{version: 1, name: "create_users_table", up: "CREATE TABLE users ();", down: "DROP TABLE users;"}
+	tokens := Migration{version: 2, name: "create_refresh_tokens_table", up: "CREATE TABLE refresh_tokens ();", down: "DROP TABLE refresh_tokens;"}
+	roles := Migration{version: 3, name: "create_roles_tables", up: "CREATE TABLE roles ();", down: "DROP TABLE roles;"}
+	migrations := []Migration{users, tokens, roles}
+	applied := map[uint64]Record{
+		1: {Version: 1, Checksum: checksum(users.up)},
+		2: {Version: 2, Checksum: checksum(tokens.up)},
+	}
+
+	tests := []struct {
+		name    string
+		version uint64
+		ups     []uint64
+		downs   []uint64
+		err     error
+	}{
+		{
+			name:    "Forward",
+			version: 3,
+			ups:     []uint64{3},
+			downs:   []uint64{},
+		},
+		{
+			name:    "Backward",
+			version: 1,
+			ups:     []uint64{},
+			downs:   []uint64{2},
+		},
+		{
+			name:    "Current version",
+			version: 2,
+			ups:     []uint64{},
+			downs:   []uint64{},
+		},
+		{
+			name:    "Version zero",
+			version: 0,
+			ups:     []uint64{},
+			downs:   []uint64{2, 1},
+		},
+		{
+			name:    "Unknown version",
+			version: 7,
+			err:     ErrVersionUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ups, downs, err := gotoPlan(migrations, applied, tt.version)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.ups, versions(ups))
+			assert.Equal(t, tt.downs, versions(downs))
+		})
+	}
+}
+
+func TestStatus(t *testing.T) {
+	migrations := []Migration{
+		{version: 1, name: "create_users_table"},
+		{version: 3, name: "create_roles_tables"},
+	}
+	applied := map[uint64]Record{
+		1: {Version: 1, Name: "create_users_table"},
+		2: {Version: 2, Name: "create_refresh_tokens_table"},
+	}
+
+	out := status(migrations, applied)
+
+	assert.Equal(t, []State{
+		{Version: 1, Name: "create_users_table", Applied: true, Record: applied[1]},
+		{Version: 2, Name: "create_refresh_tokens_table", Applied: true, Missing: true, Record: applied[2]},
+		{Version: 3, Name: "create_roles_tables"},
+	}, out)
+}
+
+func versions(migrations []Migration) []uint64 {
+	out := make([]uint64, 0, len(migrations))
+	for _, m := range migrations {
+		out = append(out, m.version)
+	}
+	return out
+}
+
+func TestMigrator_sqlite(t *testing.T) {
+	t.Setenv("CAMGO_DATABASE_SQLITE_PATH", filepath.Join(t.TempDir(), "camgo.db"))
+	conn, err := db.NewSQLiteConn(config.NewConfig().Database.SQLite)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	migrations, err := Load(SQLite, "")
+	require.NoError(t, err)
+	m := NewMigrator(conn.DB(), SQLite, migrations, 0, logger.NewZeroLog())
+	applied := func() []uint64 {
+		states, err := m.Status()
+		require.NoError(t, err)
+		versions := make([]uint64, 0, len(states))
+		for _, s := range states {
+			if s.Applied {
+				versions = append(versions, s.Version)
+			}
+		}
+		return versions
+	}
+
+	require.NoError(t, m.Up(0))
+	assert.Equal(t, []uint64{1, 2, 3}, applied())
+	require.NoError(t, m.Up(0), "nothing left to apply")
+	assert.NoError(t, m.Check(context.Background()))
+
+	require.NoError(t, m.Down(1))
+	assert.ErrorIs(t, m.Check(context.Background()), ErrMigrationPending)
+	require.NoError(t, m.Up(0))
+
+	require.NoError(t, m.Redo())
+	require.NoError(t, m.Down(0))
+	assert.Empty(t, applied())
+
+	require.NoError(t, m.Goto(2))
+	assert.Equal(t, []uint64{1, 2}, applied())
+	require.NoError(t, m.Force(3))
+	assert.Equal(t, []uint64{1, 2, 3}, applied())
+	require.NoError(t, m.Force(1))
+	assert.Equal(t, []uint64{1}, applied())
+}
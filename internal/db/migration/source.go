@@ -0,0 +1,89 @@
+package migration
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// embedded bundles the SQL migration files of every dialect into the binary.
+//
+//go:embed v1/*/*.sql
+var embedded embed.FS
+
+// embeddedDir is the directory of the embedded SQL migration files, it holds a directory per dialect.
+const embeddedDir = "v1"
+
+// filenameRegexp matches the migration filenames, e.g. "001_create_users_table.up.sql".
+var filenameRegexp = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+var ErrUpMissing = errors.New("up migration file missing")
+
+// Load discovers the migrations of the given directory, the embedded migrations of the dialect are used when the
+// directory is empty. The override directory is intended for development, the SQL files can be changed without
+// rebuilding the binary, it must hold the files of the dialect.
+func Load(dialect Dialect, dir string) ([]Migration, error) {
+	if dir != "" {
+		return discover(os.DirFS(dir))
+	}
+	fsys, err := fs.Sub(embedded, path.Join(embeddedDir, dialect.name))
+	if err != nil {
+		return nil, err
+	}
+	return discover(fsys)
+}
+
+// discover pairs the "NNN_name.up.sql" and "NNN_name.down.sql" files found at the root of the file system
+// into migrations sorted by version.
+func discover(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, &ReadFileErr{Err: err}
+	}
+
+	byVersion := make(map[uint64]*Migration)
+	for _, entry := range entries {
+		matches := filenameRegexp.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || matches == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", entry.Name(), err)
+		}
+		sqlContent, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, &ReadFileErr{Path: entry.Name(), Err: err}
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{version: version, name: matches[2]}
+			byVersion[version] = m
+		}
+		if m.name != matches[2] {
+			return nil, fmt.Errorf("migration %s: %w", entry.Name(), ErrVersionDuplicated)
+		}
+		if matches[3] == "up" {
+			m.up = string(sqlContent)
+		} else {
+			m.down = string(sqlContent)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == "" {
+			return nil, fmt.Errorf("migration %03d_%s: %w", m.version, m.name, ErrUpMissing)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+	return migrations, nil
+}
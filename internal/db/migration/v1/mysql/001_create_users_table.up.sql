@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS users (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    first_name VARCHAR (255) NOT NULL,
+    last_name VARCHAR (255) NOT NULL,
+    email VARCHAR (255) NOT NULL,
+    birthday DATE NOT NULL,
+
+    username VARCHAR (50) NOT NULL,
+    passwd TEXT NOT NULL,
+    active BOOLEAN DEFAULT FALSE,
+    last_login DATETIME(6),
+
+    created_at DATETIME(6) DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6),
+
+    UNIQUE INDEX users_email_key (email),
+    UNIQUE INDEX users_username_key (username)
+) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    family_id VARCHAR (64) NOT NULL,
+    token_hash VARCHAR (64) NOT NULL,
+    expires_at DATETIME(6) NOT NULL,
+    revoked_at DATETIME(6),
+
+    created_at DATETIME(6) DEFAULT CURRENT_TIMESTAMP(6),
+
+    UNIQUE INDEX refresh_tokens_token_hash_key (token_hash),
+    INDEX refresh_tokens_family_id_idx (family_id),
+    INDEX refresh_tokens_user_id_idx (user_id),
+    CONSTRAINT refresh_tokens_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin;
@@ -0,0 +1,23 @@
+-- MySQL commits every DDL statement implicitly, each step is idempotent so a failed revert can be run again.
+SET @stmt = IF(
+    (SELECT COUNT(*) FROM information_schema.table_constraints
+     WHERE constraint_schema = DATABASE() AND table_name = 'users' AND constraint_name = 'users_role_fkey') > 0,
+    'ALTER TABLE users DROP FOREIGN KEY users_role_fkey',
+    'DO 0'
+);
+PREPARE stmt FROM @stmt;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @stmt = IF(
+    (SELECT COUNT(*) FROM information_schema.columns
+     WHERE table_schema = DATABASE() AND table_name = 'users' AND column_name = 'role') > 0,
+    'ALTER TABLE users DROP COLUMN role',
+    'DO 0'
+);
+PREPARE stmt FROM @stmt;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS roles;
@@ -0,0 +1,52 @@
+-- MySQL commits every DDL statement implicitly, so this migration is not transactional.
+-- Each step is idempotent instead: a run failing halfway is completed by running it again.
+CREATE TABLE IF NOT EXISTS roles (
+    name VARCHAR (50) PRIMARY KEY,
+    description TEXT NOT NULL,
+
+    created_at DATETIME(6) DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6)
+) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin;
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role VARCHAR (50) NOT NULL,
+    permission VARCHAR (100) NOT NULL,
+    PRIMARY KEY (role, permission),
+    CONSTRAINT role_permissions_role_fkey FOREIGN KEY (role) REFERENCES roles (name) ON DELETE CASCADE
+) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin;
+
+INSERT IGNORE INTO roles (name, description) VALUES
+    ('admin', 'Manages every user and the roles permission matrix'),
+    ('user', 'Default role of the registered users');
+
+-- The default permission matrix is only loaded once, so the changes made by the admins are kept.
+INSERT INTO role_permissions (role, permission)
+SELECT p.role, p.permission FROM (
+    SELECT 'admin' AS role, 'users:read' AS permission
+    UNION ALL SELECT 'admin', 'users:update'
+    UNION ALL SELECT 'admin', 'users:delete'
+    UNION ALL SELECT 'admin', 'roles:manage'
+    UNION ALL SELECT 'user', 'users:read'
+) AS p
+WHERE NOT EXISTS (SELECT 1 FROM role_permissions);
+
+-- MySQL has no ADD COLUMN IF NOT EXISTS, so each ALTER TABLE is only executed when its change is missing.
+SET @stmt = IF(
+    (SELECT COUNT(*) FROM information_schema.columns
+     WHERE table_schema = DATABASE() AND table_name = 'users' AND column_name = 'role') = 0,
+    'ALTER TABLE users ADD COLUMN role VARCHAR (50) NOT NULL DEFAULT ''user''',
+    'DO 0'
+);
+PREPARE stmt FROM @stmt;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @stmt = IF(
+    (SELECT COUNT(*) FROM information_schema.table_constraints
+     WHERE constraint_schema = DATABASE() AND table_name = 'users' AND constraint_name = 'users_role_fkey') = 0,
+    'ALTER TABLE users ADD CONSTRAINT users_role_fkey FOREIGN KEY (role) REFERENCES roles (name)',
+    'DO 0'
+);
+PREPARE stmt FROM @stmt;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
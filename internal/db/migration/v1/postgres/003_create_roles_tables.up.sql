@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS roles (
+    name VARCHAR (50) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role VARCHAR (50) NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+    permission VARCHAR (100) NOT NULL,
+    PRIMARY KEY (role, permission)
+);
+
+INSERT INTO roles (name, description) VALUES
+    ('admin', 'Manages every user and the roles permission matrix'),
+    ('user', 'Default role of the registered users')
+ON CONFLICT (name) DO NOTHING;
+
+-- The default permission matrix is only loaded once, so the changes made by the admins are kept.
+INSERT INTO role_permissions (role, permission)
+SELECT p.role, p.permission FROM (VALUES
+    ('admin', 'users:read'),
+    ('admin', 'users:update'),
+    ('admin', 'users:delete'),
+    ('admin', 'roles:manage'),
+    ('user', 'users:read')
+) AS p (role, permission)
+WHERE NOT EXISTS (SELECT 1 FROM role_permissions);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR (50) NOT NULL DEFAULT 'user' REFERENCES roles (name);
@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS roles_users_role_fkey_delete;
+DROP TRIGGER IF EXISTS users_role_fkey_update;
+DROP TRIGGER IF EXISTS users_role_fkey_insert;
+ALTER TABLE users DROP COLUMN role;
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS roles;
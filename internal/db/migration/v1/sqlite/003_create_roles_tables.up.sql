@@ -0,0 +1,50 @@
+CREATE TABLE IF NOT EXISTS roles (
+    name VARCHAR (50) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role VARCHAR (50) NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+    permission VARCHAR (100) NOT NULL,
+    PRIMARY KEY (role, permission)
+);
+
+INSERT OR IGNORE INTO roles (name, description) VALUES
+    ('admin', 'Manages every user and the roles permission matrix'),
+    ('user', 'Default role of the registered users');
+
+-- The default permission matrix is only loaded once, so the changes made by the admins are kept.
+INSERT INTO role_permissions (role, permission)
+SELECT p.role, p.permission FROM (
+    SELECT 'admin' AS role, 'users:read' AS permission
+    UNION ALL SELECT 'admin', 'users:update'
+    UNION ALL SELECT 'admin', 'users:delete'
+    UNION ALL SELECT 'admin', 'roles:manage'
+    UNION ALL SELECT 'user', 'users:read'
+) AS p
+WHERE NOT EXISTS (SELECT 1 FROM role_permissions);
+
+-- SQLite cannot add a column referencing another table once the foreign keys are enforced,
+-- the reference of the users role is enforced by triggers instead.
+ALTER TABLE users ADD COLUMN role VARCHAR (50) NOT NULL DEFAULT 'user';
+
+CREATE TRIGGER IF NOT EXISTS users_role_fkey_insert BEFORE INSERT ON users
+WHEN NOT EXISTS (SELECT 1 FROM roles WHERE name = NEW.role)
+BEGIN
+    SELECT RAISE (ABORT, 'FOREIGN KEY constraint failed: users.role');
+END;
+
+CREATE TRIGGER IF NOT EXISTS users_role_fkey_update BEFORE UPDATE OF role ON users
+WHEN NOT EXISTS (SELECT 1 FROM roles WHERE name = NEW.role)
+BEGIN
+    SELECT RAISE (ABORT, 'FOREIGN KEY constraint failed: users.role');
+END;
+
+CREATE TRIGGER IF NOT EXISTS roles_users_role_fkey_delete BEFORE DELETE ON roles
+WHEN EXISTS (SELECT 1 FROM users WHERE role = OLD.name)
+BEGIN
+    SELECT RAISE (ABORT, 'FOREIGN KEY constraint failed: users.role');
+END;
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/wizeline/CA-Microservices-Go/internal/config"
+)
+
+// MySQLConn handles the MySQL or MariaDB database connection.
+type MySQLConn struct {
+	db *sql.DB
+}
+
+// NewMySQLConn creates a new database connector instance.
+// The sessions run in UTC so NOW() and the DATETIME columns hold the same times as PostgreSQL,
+// and the UPDATE statements report the matched rows instead of the changed ones.
+func NewMySQLConn(cfg config.MySQL) (*MySQLConn, error) {
+	myCfg := mysql.NewConfig()
+	myCfg.Net = "tcp"
+	myCfg.Addr = net.JoinHostPort(cfg.Host(), strconv.Itoa(cfg.Port()))
+	myCfg.User = cfg.User()
+	myCfg.Passwd = cfg.Passwd()
+	myCfg.DBName = cfg.DBName()
+	myCfg.ParseTime = true
+	myCfg.Loc = time.UTC
+	myCfg.ClientFoundRows = true
+	myCfg.MultiStatements = true
+	myCfg.Params = map[string]string{"time_zone": "'+00:00'"}
+
+	db, err := sql.Open("mysql", myCfg.FormatDSN())
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Ping()
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%w: %w", ErrUnreachable, err)
+	}
+
+	return &MySQLConn{db}, nil
+}
+
+// Close closes the database connection.
+func (conn *MySQLConn) Close() error {
+	return conn.db.Close()
+}
+
+// DB returns the underlying *sql.DB instance.
+func (conn *MySQLConn) DB() *sql.DB {
+	return conn.db
+}
@@ -2,13 +2,15 @@ package db
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
-	"log"
 
 	_ "github.com/lib/pq"
 	"github.com/wizeline/CA-Microservices-Go/internal/config"
 )
 
+var ErrUnreachable = errors.New("database unreachable")
+
 // PgConn handles the PostgreSQL database connection.
 type PgConn struct {
 	db *sql.DB
@@ -21,14 +23,13 @@ func NewPgConn(cfg config.PostgreSQL) (*PgConn, error) {
 
 	db, err := sql.Open("postgres", connStr)
 	if err != nil {
-		log.Fatal(err)
 		return nil, err
 	}
 
 	err = db.Ping()
 	if err != nil {
-		log.Fatal(err)
-		return nil, err
+		db.Close()
+		return nil, fmt.Errorf("%w: %w", ErrUnreachable, err)
 	}
 
 	return &PgConn{db}, nil
@@ -0,0 +1,105 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+	"github.com/wizeline/CA-Microservices-Go/internal/logger"
+	"github.com/wizeline/CA-Microservices-Go/internal/repository"
+	"github.com/wizeline/CA-Microservices-Go/internal/service"
+)
+
+var (
+	ErrSetUnknown = errors.New("unknown seed set")
+	ErrUserTaken  = errors.New("email or username taken by another user")
+)
+
+// UserService stores the seeded users, so they go through the same validation and password hashing as the API.
+type UserService interface {
+	Create(ctx context.Context, args service.UserCreateArgs) error
+	Find(ctx context.Context, filter, value string) ([]entity.User, error)
+	Activate(ctx context.Context, id uint64) error
+	ChangeRole(ctx context.Context, id uint64, role string) error
+}
+
+// Result counts the seeded entities.
+type Result struct {
+	Created  int
+	Existing int
+}
+
+// Seeder applies the fixtures through the services.
+// It is idempotent: the users already stored are kept, only their role and activation are brought in line with the fixtures.
+type Seeder struct {
+	users  UserService
+	logger logger.ZeroLog
+}
+
+// NewSeeder returns a Seeder storing the users through the given service.
+func NewSeeder(users UserService, l logger.ZeroLog) Seeder {
+	return Seeder{
+		users:  users,
+		logger: l,
+	}
+}
+
+// Apply seeds the fixtures, the users are created in the order they are listed.
+func (s Seeder) Apply(ctx context.Context, fixtures Fixtures) (Result, error) {
+	var res Result
+	for _, u := range fixtures.Users {
+		created, err := s.seedUser(ctx, u)
+		if err != nil {
+			return res, fmt.Errorf("seed user %s: %w", u.Username, err)
+		}
+		if created {
+			res.Created++
+		} else {
+			res.Existing++
+		}
+	}
+	s.logger.Log().Info().Int("created", res.Created).Int("existing", res.Existing).Msg("users seeded")
+	return res, nil
+}
+
+// seedUser creates the user when missing, and reports whether it did.
+func (s Seeder) seedUser(ctx context.Context, u UserFixture) (bool, error) {
+	args, err := u.createArgs()
+	if err != nil {
+		return false, err
+	}
+
+	created := true
+	var conflictErr *repository.ConflictErr
+	if err := s.users.Create(ctx, args); err != nil {
+		if !errors.As(err, &conflictErr) {
+			return false, err
+		}
+		created = false
+	}
+
+	// The stored user is read back, a conflict on the email of another user must not pass for an existing seed.
+	users, err := s.users.Find(ctx, "Username", u.Username)
+	if err != nil {
+		return false, err
+	}
+	if len(users) != 1 || users[0].Email != u.Email {
+		return false, ErrUserTaken
+	}
+	user := users[0]
+
+	if u.Role != "" && u.Role != user.Role {
+		if err := s.users.ChangeRole(ctx, user.ID, u.Role); err != nil {
+			return false, err
+		}
+	}
+	if u.Active && !user.Active {
+		if err := s.users.Activate(ctx, user.ID); err != nil {
+			return false, err
+		}
+	}
+
+	s.logger.Log().Debug().Str("username", u.Username).Bool("created", created).Msg("user seeded")
+	return created, nil
+}
@@ -0,0 +1,167 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/db/seed/mocks"
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+	"github.com/wizeline/CA-Microservices-Go/internal/logger"
+	"github.com/wizeline/CA-Microservices-Go/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// We ensure the UserService mock object satisfies the UserService signature.
+var _ UserService = &mocks.UserService{}
+
+func TestLoad_embedded(t *testing.T) {
+	for _, env := range []string{"development", "demo", "test"} {
+		t.Run(env, func(t *testing.T) {
+			fixtures, err := Load(env, "")
+			require.NoError(t, err)
+			require.NotEmpty(t, fixtures.Users)
+			for _, u := range fixtures.Users {
+				_, err := u.createArgs()
+				assert.NoError(t, err, u.Username)
+			}
+		})
+	}
+
+	_, err := Load("staging", "")
+	assert.ErrorIs(t, err, ErrSetUnknown)
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name  string
+		fsys  fstest.MapFS
+		users []string
+		err   bool
+	}{
+		{
+			name: "YAML and JSON files merged in filename order",
+			fsys: fstest.MapFS{
+				"dev/00_empty.yaml":  {Data: []byte("users: []\n")},
+				"dev/01_admins.yml":  {Data: []byte("users:\n  - username: admin\n    role: admin\n")},
+				"dev/02_users.json":  {Data: []byte(`{"users": [{"username": "jdoe"}, {"username": "jroe"}]}`)},
+				"dev/03_extra.yaml":  {Data: []byte("users:\n  - username: extra\n")},
+				"dev/04_ignored.txt": {Data: []byte("users:\n  - username: ignored\n")},
+				"dev/nested/x.yaml":  {Data: []byte("users:\n  - username: nested\n")},
+				"other/users.yaml":   {Data: []byte("users:\n  - username: other\n")},
+			},
+			users: []string{"admin", "jdoe", "jroe", "extra"},
+		},
+		{
+			name: "Unknown YAML field",
+			fsys: fstest.MapFS{
+				"dev/users.yaml": {Data: []byte("users:\n  - usrname: admin\n")},
+			},
+			err: true,
+		},
+		{
+			name: "Unknown JSON field",
+			fsys: fstest.MapFS{
+				"dev/users.json": {Data: []byte(`{"users": [{"usrname": "admin"}]}`)},
+			},
+			err: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixtures, err := load(tt.fsys, "dev")
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			users := make([]string, 0, len(fixtures.Users))
+			for _, u := range fixtures.Users {
+				users = append(users, u.Username)
+			}
+			assert.Equal(t, tt.users, users)
+		})
+	}
+}
+
+func TestSeeder_Apply(t *testing.T) {
+	fixture := UserFixture{
+		FirstName: "Ada",
+		LastName:  "Admin",
+		Email:     "admin@camgo.dev",
+		BirthDay:  "1985-12-10",
+		Username:  "admin",
+		Passwd:    "adminp4s5",
+		Role:      entity.RoleAdmin,
+		Active:    true,
+	}
+	stored := entity.User{ID: 1, Email: fixture.Email, Username: fixture.Username, Role: entity.RoleUser}
+	seeded := stored
+	seeded.Role = entity.RoleAdmin
+	seeded.Active = true
+
+	tests := []struct {
+		name       string
+		createErr  error
+		found      []entity.User
+		changeRole bool
+		activate   bool
+		res        Result
+		err        error
+	}{
+		{
+			name:       "Created",
+			found:      []entity.User{stored},
+			changeRole: true,
+			activate:   true,
+			res:        Result{Created: 1},
+		},
+		{
+			name:      "Already seeded",
+			createErr: &repository.ConflictErr{Entity: "user", Field: "username"},
+			found:     []entity.User{seeded},
+			res:       Result{Existing: 1},
+		},
+		{
+			name:      "Email taken by another user",
+			createErr: &repository.ConflictErr{Entity: "user", Field: "email"},
+			found:     []entity.User{},
+			err:       ErrUserTaken,
+		},
+		{
+			name:      "Service error",
+			createErr: errors.New("invalid input"),
+			err:       errors.New("invalid input"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := mocks.NewUserService(t)
+			mockSvc.On("Create", mock.Anything, mock.AnythingOfType("service.UserCreateArgs")).Return(tt.createErr)
+			if tt.found != nil {
+				mockSvc.On("Find", mock.Anything, "Username", fixture.Username).Return(tt.found, nil)
+			}
+			if tt.changeRole {
+				mockSvc.On("ChangeRole", mock.Anything, stored.ID, entity.RoleAdmin).Return(nil)
+			}
+			if tt.activate {
+				mockSvc.On("Activate", mock.Anything, stored.ID).Return(nil)
+			}
+			seeder := NewSeeder(mockSvc, logger.NewZeroLog())
+
+			res, err := seeder.Apply(context.Background(), Fixtures{Users: []UserFixture{fixture}})
+
+			if tt.err != nil {
+				assert.ErrorContains(t, err, tt.err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.res, res)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+// Package seedtest seeds the integration test suites with the fixtures of a seed set.
+package seedtest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/db/seed"
+	"github.com/wizeline/CA-Microservices-Go/internal/logger"
+)
+
+// Apply seeds the embedded fixtures of the set through the user service, failing the test on error.
+// The fixtures are returned, so the tests can log in with the plain text passwords of the seeded users.
+func Apply(tb testing.TB, users seed.UserService, set string) seed.Fixtures {
+	tb.Helper()
+
+	fixtures, err := seed.Load(set, "")
+	if err != nil {
+		tb.Fatalf("loading seed set %s: %s", set, err)
+	}
+	if _, err := seed.NewSeeder(users, logger.NewZeroLog()).Apply(context.Background(), fixtures); err != nil {
+		tb.Fatalf("applying seed set %s: %s", set, err)
+	}
+	return fixtures
+}
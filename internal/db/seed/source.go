@@ -0,0 +1,114 @@
+package seed
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/service"
+
+	"gopkg.in/yaml.v3"
+)
+
+// embedded bundles the fixtures of every environment into the binary.
+//
+//go:embed fixtures
+var embedded embed.FS
+
+// embeddedDir is the directory of the embedded fixtures, it holds a seed set directory per environment.
+const embeddedDir = "fixtures"
+
+// dateFormat is the layout of the birthdays in the fixture files.
+const dateFormat = "2006-01-02"
+
+// Fixtures is the data of a seed set.
+type Fixtures struct {
+	Users []UserFixture `yaml:"users" json:"users"`
+}
+
+// UserFixture describes a user to seed. The password is in plain text, it is hashed by the user service.
+type UserFixture struct {
+	FirstName string `yaml:"first_name" json:"first_name"`
+	LastName  string `yaml:"last_name" json:"last_name"`
+	Email     string `yaml:"email" json:"email"`
+	BirthDay  string `yaml:"birthday" json:"birthday"`
+	Username  string `yaml:"username" json:"username"`
+	Passwd    string `yaml:"passwd" json:"passwd"`
+	Role      string `yaml:"role" json:"role"`
+	Active    bool   `yaml:"active" json:"active"`
+}
+
+func (u UserFixture) createArgs() (service.UserCreateArgs, error) {
+	birthDay, err := time.Parse(dateFormat, u.BirthDay)
+	if err != nil {
+		return service.UserCreateArgs{}, fmt.Errorf("invalid birthday %q: %w", u.BirthDay, err)
+	}
+	return service.UserCreateArgs{
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+		Email:     u.Email,
+		BirthDay:  birthDay,
+		Username:  u.Username,
+		Passwd:    u.Passwd,
+	}, nil
+}
+
+// Load reads the seed set of the environment from the given directory, the embedded fixtures are used when the
+// directory is empty. Each environment is a subdirectory holding YAML or JSON fixture files, merged in filename order.
+func Load(env, dir string) (Fixtures, error) {
+	if env == "" {
+		return Fixtures{}, ErrSetUnknown
+	}
+	fsys := fs.FS(embedded)
+	root := path.Join(embeddedDir, env)
+	if dir != "" {
+		fsys = os.DirFS(dir)
+		root = env
+	}
+	return load(fsys, root)
+}
+
+func load(fsys fs.FS, root string) (Fixtures, error) {
+	entries, err := fs.ReadDir(fsys, root)
+	if err != nil {
+		return Fixtures{}, fmt.Errorf("seed set %s: %w", path.Base(root), ErrSetUnknown)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var fixtures Fixtures
+	for _, entry := range entries {
+		ext := path.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+			continue
+		}
+		content, err := fs.ReadFile(fsys, path.Join(root, entry.Name()))
+		if err != nil {
+			return Fixtures{}, fmt.Errorf("failed reading seed file %s: %w", entry.Name(), err)
+		}
+		file, err := decode(ext, content)
+		if err != nil {
+			return Fixtures{}, fmt.Errorf("seed file %s: %w", entry.Name(), err)
+		}
+		fixtures.Users = append(fixtures.Users, file.Users...)
+	}
+	return fixtures, nil
+}
+
+// decode parses the fixture file content, the unknown fields are rejected to catch the typos.
+func decode(ext string, content []byte) (Fixtures, error) {
+	var fixtures Fixtures
+	if ext == ".json" {
+		dec := json.NewDecoder(bytes.NewReader(content))
+		dec.DisallowUnknownFields()
+		return fixtures, dec.Decode(&fixtures)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	dec.KnownFields(true)
+	return fixtures, dec.Decode(&fixtures)
+}
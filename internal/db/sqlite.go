@@ -0,0 +1,59 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/config"
+	_ "modernc.org/sqlite"
+)
+
+// sqlitePragmas are applied to every connection: the foreign keys are enforced, the LIKE patterns are case-sensitive
+// as in PostgreSQL, and the writers wait for each other instead of failing with SQLITE_BUSY.
+var sqlitePragmas = []string{
+	"foreign_keys(1)",
+	"case_sensitive_like(1)",
+	"busy_timeout(5000)",
+	"journal_mode(WAL)",
+}
+
+// SQLiteConn handles the SQLite database connection.
+type SQLiteConn struct {
+	db *sql.DB
+}
+
+// NewSQLiteConn opens the SQLite database file, creating it when missing.
+// The transactions take the write lock when they begin, so the concurrent writers are serialized.
+func NewSQLiteConn(cfg config.SQLite) (*SQLiteConn, error) {
+	params := url.Values{}
+	for _, pragma := range sqlitePragmas {
+		params.Add("_pragma", pragma)
+	}
+	params.Set("_txlock", "immediate")
+	params.Set("_time_format", "sqlite")
+	dsn := fmt.Sprintf("file:%s?%s", cfg.Path(), params.Encode())
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Ping()
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%w: %w", ErrUnreachable, err)
+	}
+
+	return &SQLiteConn{db}, nil
+}
+
+// Close closes the database connection.
+func (conn *SQLiteConn) Close() error {
+	return conn.db.Close()
+}
+
+// DB returns the underlying *sql.DB instance.
+func (conn *SQLiteConn) DB() *sql.DB {
+	return conn.db
+}
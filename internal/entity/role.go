@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Built-in roles.
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+// Permissions granted to the roles, in the form "resource:action".
+const (
+	PermUsersRead   = "users:read"
+	PermUsersUpdate = "users:update"
+	PermUsersDelete = "users:delete"
+	PermRolesManage = "roles:manage"
+)
+
+type Role struct {
+	Name        string
+	Description string
+	Permissions []string
+
+	CreatedAt time.Time
+	UpdatedAt sql.NullTime
+}
@@ -0,0 +1,17 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+type RefreshToken struct {
+	ID        uint64
+	UserID    uint64
+	FamilyID  string
+	TokenHash string
+	ExpiresAt time.Time
+	RevokedAt sql.NullTime
+
+	CreatedAt time.Time
+}
@@ -14,6 +14,7 @@ type User struct {
 
 	Username  string
 	Passwd    string
+	Role      string
 	Active    bool
 	LastLogin sql.NullTime
 
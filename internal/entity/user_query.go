@@ -0,0 +1,61 @@
+package entity
+
+// The user fields supported by the search filters and sorting.
+const (
+	UserFieldID        = "id"
+	UserFieldFirstName = "first_name"
+	UserFieldLastName  = "last_name"
+	UserFieldEmail     = "email"
+	UserFieldUsername  = "username"
+	UserFieldRole      = "role"
+	UserFieldActive    = "active"
+	UserFieldBirthDay  = "birthday"
+	UserFieldCreatedAt = "created_at"
+)
+
+// FilterOp is the comparison a Filter applies between a field and its value.
+type FilterOp string
+
+const (
+	FilterEq       FilterOp = "eq"
+	FilterPrefix   FilterOp = "prefix"
+	FilterContains FilterOp = "contains"
+	FilterGte      FilterOp = "gte"
+	FilterLte      FilterOp = "lte"
+)
+
+// Filter restricts the users returned by a search.
+// Value holds a string, a bool or a time.Time according to the field type.
+type Filter struct {
+	Field string
+	Op    FilterOp
+	Value any
+}
+
+// Sort orders the users returned by a search.
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// Cursor is the keyset position a search page starts from.
+// Values holds the sort fields values of the boundary user, followed by its ID when the ID is not a sort field.
+type Cursor struct {
+	Values   []any
+	Backward bool
+}
+
+// UserQuery holds the criteria of a users search. A zero Limit returns every user matched.
+type UserQuery struct {
+	Filters []Filter
+	Sort    []Sort
+	Limit   int
+	Offset  int
+	Cursor  *Cursor
+}
+
+// UserPage is a page of the users matched by a search and the total of users matched.
+type UserPage struct {
+	Users []User
+	Total int
+}
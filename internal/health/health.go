@@ -0,0 +1,165 @@
+// Package health checks whether the dependencies of the service are ready to handle requests.
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Public descriptions of the check failures, they hold none of the dependency details found in the errors.
+const (
+	FailureTimeout = "check timed out"
+	FailureError   = "check failed"
+)
+
+// Checker checks the health of a dependency, a nil error reports it healthy.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc adapts a function to the Checker interface. e.g. CheckerFunc(db.PingContext)
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Check registers a Checker under a name.
+// The service is not ready while a critical check fails, the failures of the other checks are only reported.
+type Check struct {
+	Name     string
+	Checker  Checker
+	Critical bool
+}
+
+// Result is the outcome of a check.
+// The last error is kept once the check recovers, LastErrorAt is zero when it never failed.
+// LastFailure describes the last error without its details, so it can be reported to unauthenticated callers.
+type Result struct {
+	Name        string
+	Critical    bool
+	Err         error
+	Latency     time.Duration
+	LastError   string
+	LastFailure string
+	LastErrorAt time.Time
+}
+
+// Healthy reports whether the check succeeded.
+func (r Result) Healthy() bool {
+	return r.Err == nil
+}
+
+// Report is the outcome of the readiness checks, sorted as they were registered.
+type Report struct {
+	Ready        bool
+	ShuttingDown bool
+	Results      []Result
+}
+
+// lastError is the most recent failure of a check.
+type lastError struct {
+	msg     string
+	failure string
+	at      time.Time
+}
+
+// Readiness runs the registered checks at the same time, each one bounded by the timeout.
+// It reports the service not ready once it is shutting down, so the load balancers stop routing requests to it.
+type Readiness struct {
+	checks       []Check
+	timeout      time.Duration
+	shuttingDown *atomic.Bool
+	mu           *sync.Mutex
+	lastErrors   map[string]lastError
+}
+
+// NewReadiness returns a Readiness running the given checks, a zero or negative timeout lets the checks run without limit.
+func NewReadiness(timeout time.Duration, checks ...Check) Readiness {
+	return Readiness{
+		checks:       checks,
+		timeout:      timeout,
+		shuttingDown: &atomic.Bool{},
+		mu:           &sync.Mutex{},
+		lastErrors:   make(map[string]lastError),
+	}
+}
+
+// Check runs every check and reports whether the service is ready.
+func (r Readiness) Check(ctx context.Context) Report {
+	results := make([]Result, len(r.checks))
+	var wg sync.WaitGroup
+	for i, check := range r.checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = r.run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := Report{
+		Ready:        !r.shuttingDown.Load(),
+		ShuttingDown: r.shuttingDown.Load(),
+		Results:      results,
+	}
+	for _, res := range results {
+		if res.Critical && !res.Healthy() {
+			report.Ready = false
+		}
+	}
+	return report
+}
+
+// Shutdown reports the service not ready from now on.
+func (r Readiness) Shutdown() {
+	r.shuttingDown.Store(true)
+}
+
+// run runs the check and records its failure.
+// A checker ignoring the context is abandoned once the timeout elapses, and reported as failed.
+func (r Readiness) run(ctx context.Context, check Check) Result {
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	errc := make(chan error, 1)
+	go func() {
+		errc <- check.Checker.Check(ctx)
+	}()
+	var err error
+	select {
+	case err = <-errc:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	res := Result{
+		Name:     check.Name,
+		Critical: check.Critical,
+		Err:      err,
+		Latency:  time.Since(start),
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		r.lastErrors[check.Name] = lastError{msg: err.Error(), failure: failure(err), at: start.Add(res.Latency)}
+	}
+	last := r.lastErrors[check.Name]
+	res.LastError, res.LastFailure, res.LastErrorAt = last.msg, last.failure, last.at
+	return res
+}
+
+// failure returns the public description of the check error.
+func failure(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return FailureTimeout
+	}
+	return FailureError
+}
@@ -0,0 +1,125 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadiness_Check(t *testing.T) {
+	up := CheckerFunc(func(context.Context) error { return nil })
+	down := CheckerFunc(func(context.Context) error { return errors.New("connection refused") })
+
+	tests := []struct {
+		name   string
+		checks []Check
+		ready  bool
+	}{
+		{name: "No checks", ready: true},
+		{
+			name:   "Every check up",
+			checks: []Check{{Name: "database", Checker: up, Critical: true}, {Name: "cache", Checker: up}},
+			ready:  true,
+		},
+		{
+			name:   "Non critical check down",
+			checks: []Check{{Name: "database", Checker: up, Critical: true}, {Name: "cache", Checker: down}},
+			ready:  true,
+		},
+		{
+			name:   "Critical check down",
+			checks: []Check{{Name: "database", Checker: down, Critical: true}, {Name: "cache", Checker: up}},
+			ready:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := NewReadiness(time.Second, tt.checks...).Check(context.Background())
+			assert.Equal(t, tt.ready, report.Ready)
+			assert.False(t, report.ShuttingDown)
+			require.Len(t, report.Results, len(tt.checks))
+			for i, check := range tt.checks {
+				assert.Equal(t, check.Name, report.Results[i].Name, "the results are sorted as the checks were registered")
+				assert.Equal(t, check.Critical, report.Results[i].Critical)
+			}
+		})
+	}
+}
+
+func TestReadiness_Check_concurrent(t *testing.T) {
+	slow := CheckerFunc(func(ctx context.Context) error {
+		select {
+		case <-time.After(100 * time.Millisecond):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	r := NewReadiness(time.Second,
+		Check{Name: "database", Checker: slow, Critical: true},
+		Check{Name: "cache", Checker: slow, Critical: true},
+		Check{Name: "broker", Checker: slow, Critical: true},
+	)
+
+	start := time.Now()
+	report := r.Check(context.Background())
+	assert.True(t, report.Ready)
+	assert.Less(t, time.Since(start), 250*time.Millisecond, "the checks run at the same time")
+}
+
+func TestReadiness_Check_timeout(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	stuck := CheckerFunc(func(context.Context) error {
+		<-block
+		return nil
+	})
+	r := NewReadiness(10*time.Millisecond, Check{Name: "database", Checker: stuck, Critical: true})
+
+	report := r.Check(context.Background())
+	assert.False(t, report.Ready)
+	require.Len(t, report.Results, 1)
+	assert.ErrorIs(t, report.Results[0].Err, context.DeadlineExceeded, "a checker ignoring the context is abandoned")
+	assert.Equal(t, FailureTimeout, report.Results[0].LastFailure)
+}
+
+func TestReadiness_Check_lastError(t *testing.T) {
+	var err error
+	checker := CheckerFunc(func(context.Context) error { return err })
+	r := NewReadiness(time.Second, Check{Name: "database", Checker: checker, Critical: true})
+
+	res := r.Check(context.Background()).Results[0]
+	assert.True(t, res.Healthy())
+	assert.Empty(t, res.LastError)
+	assert.Empty(t, res.LastFailure)
+	assert.True(t, res.LastErrorAt.IsZero(), "the check never failed")
+
+	err = errors.New("connection refused")
+	res = r.Check(context.Background()).Results[0]
+	assert.False(t, res.Healthy())
+	assert.Equal(t, "connection refused", res.LastError)
+	assert.Equal(t, FailureError, res.LastFailure)
+	failedAt := res.LastErrorAt
+	assert.False(t, failedAt.IsZero())
+
+	err = nil
+	res = r.Check(context.Background()).Results[0]
+	assert.True(t, res.Healthy())
+	assert.Equal(t, "connection refused", res.LastError, "the last error is kept once the check recovers")
+	assert.Equal(t, failedAt, res.LastErrorAt)
+}
+
+func TestReadiness_Shutdown(t *testing.T) {
+	r := NewReadiness(time.Second, Check{Name: "database", Checker: CheckerFunc(func(context.Context) error { return nil }), Critical: true})
+	assert.True(t, r.Check(context.Background()).Ready)
+
+	r.Shutdown()
+	report := r.Check(context.Background())
+	assert.False(t, report.Ready)
+	assert.True(t, report.ShuttingDown)
+	assert.True(t, report.Results[0].Healthy(), "the checks still run while shutting down")
+}
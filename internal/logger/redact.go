@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"regexp"
+
+	"github.com/rs/zerolog"
+)
+
+// redactedValue replaces the values of the secret fields.
+const redactedValue = `"[REDACTED]"`
+
+var (
+	// emailPattern matches the email addresses, the first character and the domain are kept. e.g. "j***@camgo.dev"
+	emailPattern = regexp.MustCompile(`([A-Za-z0-9._%+-])[A-Za-z0-9._%+-]*@([A-Za-z0-9.-]+\.[A-Za-z]{2,})`)
+	// secretFieldPattern matches the string fields holding a password or a token. e.g. "passwd":"...", "refresh_token":"..."
+	secretFieldPattern = regexp.MustCompile(`(?i)("[a-z_]*(?:passw(?:or)?d|secret|token|authorization)[a-z_]*"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	// jwtPattern matches the JSON web tokens wherever they are written, e.g. in an error message.
+	jwtPattern = regexp.MustCompile(`eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)
+	// bearerPattern matches the credentials of the Authorization headers.
+	bearerPattern = regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9._~+/=-]+`)
+)
+
+// redactWriter masks the emails, the passwords and the tokens of the lines before writing them to the next writer.
+// It is the first writer the lines go through, so no output receives them unmasked.
+type redactWriter struct {
+	next zerolog.LevelWriter
+}
+
+func (w redactWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w redactWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if _, err := w.next.WriteLevel(level, redact(p)); err != nil {
+		return 0, err
+	}
+	// The length of the given line is reported, the masked one may be shorter or longer.
+	return len(p), nil
+}
+
+// redact returns a copy of the JSON line with the emails, the passwords and the tokens masked.
+func redact(line []byte) []byte {
+	line = secretFieldPattern.ReplaceAll(line, []byte("${1}"+redactedValue))
+	line = jwtPattern.ReplaceAll(line, []byte("[REDACTED]"))
+	line = bearerPattern.ReplaceAll(line, []byte("${1}[REDACTED]"))
+	return emailPattern.ReplaceAll(line, []byte("${1}***@${2}"))
+}
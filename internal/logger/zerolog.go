@@ -1,36 +1,201 @@
 package logger
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
 	"os"
+	"time"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/config"
 
 	"github.com/rs/zerolog"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
-// defaultTimeFormat is the default time format used by the logger.
+// defaultTimeFormat is the default time format used by the console logger.
 // Note: here you might change the time format as needed.
 const defaultTimeFormat = "2006/01/02 15:04:05"
 
+// The supported formats of the lines.
+const (
+	FormatConsole = "console"
+	FormatJSON    = "json"
+)
+
+// The supported outputs of the lines.
+const (
+	OutputStderr = "stderr"
+	OutputStdout = "stdout"
+	OutputFile   = "file"
+)
+
+var (
+	ErrFormatUnknown = errors.New("unknown log format")
+	ErrOutputUnknown = errors.New("unknown log output")
+	ErrOutputMissing = errors.New("log output missing")
+)
+
 // ZeroLog is a logger type with zerolog.Logger support
 type ZeroLog struct {
 	logger *zerolog.Logger
+	file   io.Closer
 }
 
-// NewZeroLog returns an implemented ZeroLog instance.
+// NewZeroLog returns an implemented ZeroLog instance writing every level to stderr in the console format, e.g. for the tests.
+// Unlike New, it leaves the logger returned by FromContext unchanged.
 func NewZeroLog() ZeroLog {
 	writer := zerolog.NewConsoleWriter(func(w *zerolog.ConsoleWriter) {
 		w.Out = os.Stderr
 		w.TimeFormat = defaultTimeFormat
 	})
 
-	zerolog.SetGlobalLevel(zerolog.DebugLevel)
-	zl := zerolog.New(writer).With().Timestamp().Logger()
+	zl := zerolog.New(writer).Level(zerolog.DebugLevel).Hook(traceHook{}).With().Timestamp().Logger()
 
 	return ZeroLog{
 		logger: &zl,
 	}
 }
 
+// New returns a ZeroLog configured by the given config.Logger, and makes it the logger returned by FromContext
+// when the context carries none. The emails, the passwords and the tokens are masked before the lines reach any output
+// when the redaction is enabled. The file output must be closed with Close.
+func New(cfg config.Logger) (ZeroLog, error) {
+	level, err := zerolog.ParseLevel(cfg.Level())
+	if err != nil {
+		return ZeroLog{}, err
+	}
+	if cfg.Format() != FormatConsole && cfg.Format() != FormatJSON {
+		return ZeroLog{}, fmt.Errorf("log format %q: %w", cfg.Format(), ErrFormatUnknown)
+	}
+	if len(cfg.Outputs()) == 0 {
+		return ZeroLog{}, ErrOutputMissing
+	}
+
+	var (
+		writers = make([]io.Writer, 0, len(cfg.Outputs()))
+		file    *lumberjack.Logger
+	)
+	for _, output := range cfg.Outputs() {
+		var w io.Writer
+		switch output {
+		case OutputStderr:
+			w = os.Stderr
+		case OutputStdout:
+			w = os.Stdout
+		case OutputFile:
+			if file == nil {
+				file = &lumberjack.Logger{
+					Filename:   cfg.FilePath(),
+					MaxSize:    cfg.FileMaxSize(),
+					MaxAge:     int(math.Ceil(cfg.FileMaxAge().Hours() / 24)),
+					MaxBackups: cfg.FileMaxBackups(),
+					Compress:   cfg.FileCompress(),
+				}
+			}
+			w = file
+		default:
+			return ZeroLog{}, fmt.Errorf("log output %q: %w", output, ErrOutputUnknown)
+		}
+		if cfg.Format() == FormatConsole {
+			cw := zerolog.ConsoleWriter{
+				Out:        w,
+				TimeFormat: defaultTimeFormat,
+				// The files are read by other tools than terminals.
+				NoColor: output == OutputFile,
+			}
+			if cfg.TimeFormat() != "" {
+				cw.TimeFormat = cfg.TimeFormat()
+			}
+			w = cw
+		}
+		writers = append(writers, w)
+	}
+
+	multi := zerolog.MultiLevelWriter(writers...)
+	var out io.Writer = multi
+	if cfg.Redact() {
+		out = redactWriter{next: multi}
+	}
+	zl := zerolog.New(out).Level(level).Hook(traceHook{})
+	if cfg.Format() == FormatJSON && cfg.TimeFormat() != "" {
+		zl = zl.Hook(timestampHook{format: cfg.TimeFormat()})
+	} else {
+		zl = zl.With().Timestamp().Logger()
+	}
+	if cfg.Caller() {
+		zl = zl.With().Caller().Logger()
+	}
+	if cfg.SamplingEvery() > 1 {
+		zl = zl.Sample(newSampler(cfg))
+	}
+	zerolog.DefaultContextLogger = &zl
+
+	l := ZeroLog{
+		logger: &zl,
+	}
+	if file != nil {
+		l.file = file
+	}
+	return l, nil
+}
+
+// NewZeroLogFrom returns a ZeroLog writing through the given zerolog.Logger.
+// e.g. NewZeroLogFrom(l.Log().With().Str("request_id", id).Logger())
+func NewZeroLogFrom(zl zerolog.Logger) ZeroLog {
+	return ZeroLog{
+		logger: &zl,
+	}
+}
+
+// FromContext returns the logger carried by the context, e.g. the request-scoped one writing the request ID on every line.
+// The last logger returned by New is used when the context carries none, the lines are discarded when there is none either.
+func FromContext(ctx context.Context) ZeroLog {
+	return ZeroLog{
+		logger: zerolog.Ctx(ctx),
+	}
+}
+
+// WithContext returns a copy of the context carrying the logger, it is returned by FromContext.
+func (l ZeroLog) WithContext(ctx context.Context) context.Context {
+	return l.logger.WithContext(ctx)
+}
+
 // Log returns the underlying zerolog.Logger instance
 func (l ZeroLog) Log() *zerolog.Logger {
 	return l.logger
 }
+
+// Close closes the log file, it does nothing when the lines are not written to a file.
+func (l ZeroLog) Close() error {
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+// timestampHook writes the time of the lines in the given layout, instead of the global zerolog.TimeFieldFormat one.
+type timestampHook struct {
+	format string
+}
+
+func (h timestampHook) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
+	e.Str(zerolog.TimestampFieldName, time.Now().Format(h.format))
+}
+
+// newSampler returns the sampler of the debug and info lines: the first lines of every period are all written,
+// then one out of every N. The warnings and the errors are never sampled.
+func newSampler(cfg config.Logger) zerolog.Sampler {
+	sampler := &zerolog.BurstSampler{
+		Burst:       uint32(max(cfg.SamplingBurst(), 0)),
+		Period:      cfg.SamplingPeriod(),
+		NextSampler: &zerolog.BasicSampler{N: uint32(cfg.SamplingEvery())},
+	}
+	return zerolog.LevelSampler{
+		TraceSampler: sampler,
+		DebugSampler: sampler,
+		InfoSampler:  sampler,
+	}
+}
@@ -1,9 +1,21 @@
 package logger
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/wizeline/CA-Microservices-Go/internal/config"
+
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestNewZeroLog(t *testing.T) {
@@ -11,3 +23,135 @@ func TestNewZeroLog(t *testing.T) {
 	assert.NotEqual(t, zl, ZeroLog{})
 	zl.Log().Info().Msg("NewZeroLog tested successfully")
 }
+
+func TestTraceHook(t *testing.T) {
+	var buf bytes.Buffer
+	zl := zerolog.New(&buf).Hook(traceHook{})
+
+	zl.Info().Ctx(context.Background()).Msg("no span")
+	assert.NotContains(t, buf.String(), "trace_id")
+
+	buf.Reset()
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:  trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	})
+	zl.Info().Ctx(trace.ContextWithSpanContext(context.Background(), sc)).Msg("span")
+	assert.Contains(t, buf.String(), `"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736","span_id":"00f067aa0ba902b7"`)
+}
+
+func TestFromContext(t *testing.T) {
+	var buf bytes.Buffer
+	reqLogger := NewZeroLogFrom(zerolog.New(&buf).With().Str("request_id", "host/abc-000001").Logger())
+	ctx := reqLogger.WithContext(context.Background())
+
+	FromContext(ctx).Log().Info().Msg("user created")
+	assert.Equal(t, `{"level":"info","request_id":"host/abc-000001","message":"user created"}`+"\n", buf.String())
+
+	defaultLogger := zerolog.DefaultContextLogger
+	t.Cleanup(func() { zerolog.DefaultContextLogger = defaultLogger })
+	t.Setenv("CAMGO_LOG_OUTPUTS", OutputFile)
+	t.Setenv("CAMGO_LOG_FILE_PATH", filepath.Join(t.TempDir(), "camgo.log"))
+	l, err := New(config.NewConfig().Logger)
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+	assert.Equal(t, l.Log(), FromContext(context.Background()).Log(), "the configured logger is used without request logger")
+
+	NewZeroLog()
+	assert.Equal(t, l.Log(), FromContext(context.Background()).Log(), "NewZeroLog keeps the configured logger")
+}
+
+func TestNew(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "camgo.log")
+	t.Setenv("CAMGO_LOG_LEVEL", "warn")
+	t.Setenv("CAMGO_LOG_FORMAT", FormatJSON)
+	t.Setenv("CAMGO_LOG_TIME_FORMAT", time.DateOnly)
+	t.Setenv("CAMGO_LOG_OUTPUTS", OutputFile)
+	t.Setenv("CAMGO_LOG_FILE_PATH", path)
+
+	l, err := New(config.NewConfig().Logger)
+	require.NoError(t, err)
+	l.Log().Info().Msg("below the level")
+	l.Log().Warn().Str("email", "jdoe@camgo.dev").Str("passwd", "S3cr3t!").Msg("login failed")
+	require.NoError(t, l.Close())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 1)
+	var line map[string]string
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &line))
+	assert.Equal(t, "warn", line["level"])
+	assert.Equal(t, "j***@camgo.dev", line["email"])
+	assert.Equal(t, "[REDACTED]", line["passwd"])
+	assert.Equal(t, "login failed", line["message"])
+	_, err = time.Parse(time.DateOnly, line["time"])
+	assert.NoError(t, err, "the time is written in the configured format")
+	assert.Equal(t, l.Log(), FromContext(context.Background()).Log(), "the configured logger is the default one")
+}
+
+func TestNew_redactDisabled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "camgo.log")
+	t.Setenv("CAMGO_LOG_FORMAT", FormatJSON)
+	t.Setenv("CAMGO_LOG_OUTPUTS", OutputFile)
+	t.Setenv("CAMGO_LOG_FILE_PATH", path)
+	t.Setenv("CAMGO_LOG_REDACT", "false")
+
+	l, err := New(config.NewConfig().Logger)
+	require.NoError(t, err)
+	l.Log().Info().Str("email", "jdoe@camgo.dev").Msg("user created")
+	require.NoError(t, l.Close())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"email":"jdoe@camgo.dev"`)
+}
+
+func TestNew_sampling(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "camgo.log")
+	t.Setenv("CAMGO_LOG_FORMAT", FormatJSON)
+	t.Setenv("CAMGO_LOG_OUTPUTS", OutputFile)
+	t.Setenv("CAMGO_LOG_FILE_PATH", path)
+	t.Setenv("CAMGO_LOG_SAMPLING_BURST", "2")
+	t.Setenv("CAMGO_LOG_SAMPLING_PERIOD", "1h")
+	t.Setenv("CAMGO_LOG_SAMPLING_EVERY", "1000")
+
+	l, err := New(config.NewConfig().Logger)
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		l.Log().Info().Int("i", i).Msg("sampled")
+		l.Log().Error().Int("i", i).Msg("never sampled")
+	}
+	require.NoError(t, l.Close())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(string(content), `"message":"sampled"`), "the burst and the first line of the basic sampler")
+	assert.Equal(t, 10, strings.Count(string(content), `"message":"never sampled"`))
+}
+
+func TestNew_invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		key    string
+		value  string
+		expErr error
+	}{
+		{name: "Unknown format", key: "CAMGO_LOG_FORMAT", value: "xml", expErr: ErrFormatUnknown},
+		{name: "Unknown output", key: "CAMGO_LOG_OUTPUTS", value: "stderr,syslog", expErr: ErrOutputUnknown},
+		{name: "Missing output", key: "CAMGO_LOG_OUTPUTS", value: " , ", expErr: ErrOutputMissing},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.key, tt.value)
+			_, err := New(config.NewConfig().Logger)
+			assert.ErrorIs(t, err, tt.expErr)
+		})
+	}
+
+	t.Run("Unknown level", func(t *testing.T) {
+		t.Setenv("CAMGO_LOG_LEVEL", "verbose")
+		_, err := New(config.NewConfig().Logger)
+		assert.Error(t, err)
+	})
+}
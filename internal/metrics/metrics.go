@@ -0,0 +1,89 @@
+// Package metrics collects the measures of the service in the Prometheus format.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Prometheus holds the collectors of the service and the registry exposing them, along with the Go runtime and process metrics.
+// It satisfies the middleware.HTTPMetrics and service.UserMetrics recorders.
+type Prometheus struct {
+	registry        *prometheus.Registry
+	httpRequests    *prometheus.CounterVec
+	httpDuration    *prometheus.HistogramVec
+	passwdHashing   *prometheus.HistogramVec
+	loginsAttempted *prometheus.CounterVec
+}
+
+// NewPrometheus returns a Prometheus registering the collectors of the service.
+func NewPrometheus() Prometheus {
+	p := Prometheus{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Total of HTTP requests served, by route pattern, method and status.",
+		}, []string{"route", "method", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Latency of the HTTP requests served, by route pattern, method and status.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+		passwdHashing: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "passwd_hashing_duration_seconds",
+			Help: "Time spent hashing the passwords with bcrypt, by operation.",
+			// bcrypt is slow on purpose, its default cost takes tens of milliseconds.
+			Buckets: prometheus.ExponentialBuckets(0.01, 2, 10),
+		}, []string{"operation"}),
+		loginsAttempted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "user_logins_total",
+			Help: "Total of login attempts, by result.",
+		}, []string{"result"}),
+	}
+	p.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		p.httpRequests,
+		p.httpDuration,
+		p.passwdHashing,
+		p.loginsAttempted,
+	)
+	return p
+}
+
+// RegisterDB registers the connection pool statistics of the database under the given name. e.g. "postgres"
+func (p Prometheus) RegisterDB(name string, db *sql.DB) error {
+	return p.registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// ObserveHTTPRequest records a served request.
+func (p Prometheus) ObserveHTTPRequest(route, method string, status int, elapsed time.Duration) {
+	code := strconv.Itoa(status)
+	p.httpRequests.WithLabelValues(route, method, code).Inc()
+	p.httpDuration.WithLabelValues(route, method, code).Observe(elapsed.Seconds())
+}
+
+// ObservePasswdHash records the time spent by a password hashing operation.
+func (p Prometheus) ObservePasswdHash(operation string, elapsed time.Duration) {
+	p.passwdHashing.WithLabelValues(operation).Observe(elapsed.Seconds())
+}
+
+// ObserveLogin records the result of a login attempt, "success" or "failure".
+func (p Prometheus) ObserveLogin(success bool) {
+	result := "failure"
+	if success {
+		result = "success"
+	}
+	p.loginsAttempted.WithLabelValues(result).Inc()
+}
+
+// Handler returns the handler writing the collected metrics in the Prometheus text format.
+func (p Prometheus) Handler() http.Handler {
+	return promhttp.HandlerFor(p.registry, promhttp.HandlerOpts{Registry: p.registry})
+}
@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/middleware"
+	"github.com/wizeline/CA-Microservices-Go/internal/service"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+// We ensure the Prometheus collectors satisfy the recorders signature.
+var (
+	_ middleware.HTTPMetrics = Prometheus{}
+	_ service.UserMetrics    = Prometheus{}
+)
+
+func TestPrometheus(t *testing.T) {
+	p := NewPrometheus()
+	p.ObserveHTTPRequest("/api/v0/users/{id}", http.MethodGet, http.StatusOK, 20*time.Millisecond)
+	p.ObserveHTTPRequest("/api/v0/users/{id}", http.MethodGet, http.StatusOK, 30*time.Millisecond)
+	p.ObserveHTTPRequest("/api/v0/users/{id}", http.MethodGet, http.StatusNotFound, time.Millisecond)
+	p.ObservePasswdHash(service.PasswdCompareOp, 50*time.Millisecond)
+	p.ObserveLogin(true)
+	p.ObserveLogin(false)
+	p.ObserveLogin(false)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(p.httpRequests.WithLabelValues("/api/v0/users/{id}", http.MethodGet, "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(p.httpRequests.WithLabelValues("/api/v0/users/{id}", http.MethodGet, "404")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(p.loginsAttempted.WithLabelValues("success")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(p.loginsAttempted.WithLabelValues("failure")))
+
+	rec := httptest.NewRecorder()
+	p.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	for _, metric := range []string{
+		`http_request_duration_seconds_count{method="GET",route="/api/v0/users/{id}",status="200"} 2`,
+		`passwd_hashing_duration_seconds_count{operation="compare"} 1`,
+		`user_logins_total{result="failure"} 2`,
+		"go_goroutines",
+	} {
+		assert.True(t, strings.Contains(string(body), metric), "%s is exposed", metric)
+	}
+}
+
+func TestPrometheus_RegisterDB(t *testing.T) {
+	p := NewPrometheus()
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, p.RegisterDB("sqlite", db))
+	assert.Error(t, p.RegisterDB("sqlite", db), "a database is registered once")
+
+	count, err := testutil.GatherAndCount(p.registry, "go_sql_open_connections")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
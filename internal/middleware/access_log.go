@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/logger"
+
+	"github.com/go-chi/chi"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/rs/zerolog"
+)
+
+const accessEntryCtxKey ctxKey = "accessEntry"
+
+// accessEntry holds the values of the access log line only known by the inner handlers, e.g. the authenticated user.
+type accessEntry struct {
+	userID uint64
+}
+
+// AccessLog returns a middleware that writes a line per request through the given logger, once the response is written.
+// The request-scoped logger writing the request ID on every line is stored into the request context, see logger.FromContext.
+// It is installed after chi's RequestID middleware, so the request ID is known.
+func AccessLog(l logger.ZeroLog) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			reqLogger := logger.NewZeroLogFrom(l.Log().With().Str("request_id", chimiddleware.GetReqID(r.Context())).Logger())
+			entry := &accessEntry{}
+			ctx := context.WithValue(reqLogger.WithContext(r.Context()), accessEntryCtxKey, entry)
+
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			r = r.WithContext(ctx)
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			route := unmatchedRoute
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+			event := reqLogger.Log().WithLevel(accessLevel(status)).Ctx(r.Context()).
+				Str("method", r.Method).
+				Str("route", route).
+				Str("path", r.URL.Path).
+				Int("status", status).
+				Int("bytes", ww.BytesWritten()).
+				Dur("latency", time.Since(start)).
+				Str("remote_ip", remoteIP(r))
+			if entry.userID != 0 {
+				event = event.Uint64("user_id", entry.userID)
+			}
+			event.Msg("http request")
+		})
+	}
+}
+
+// accessLevel returns the level of the access line, the failed requests stand out from the served ones.
+func accessLevel(status int) zerolog.Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return zerolog.ErrorLevel
+	case status >= http.StatusBadRequest:
+		return zerolog.WarnLevel
+	default:
+		return zerolog.InfoLevel
+	}
+}
+
+// remoteIP returns the IP address of the client, or of the last proxy the request went through.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// setAccessUserID records the authenticated user in the access log line of the request, when there is one.
+func setAccessUserID(ctx context.Context, userID uint64) {
+	if entry, ok := ctx.Value(accessEntryCtxKey).(*accessEntry); ok {
+		entry.userID = userID
+	}
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/logger"
+
+	"github.com/go-chi/chi"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessLog(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		exp    map[string]any
+	}{
+		{
+			name:   "Authenticated",
+			method: http.MethodGet,
+			path:   "/api/v0/users/42",
+			exp: map[string]any{
+				"level": "info", "method": "GET", "route": "/api/v0/users/{id}", "path": "/api/v0/users/42",
+				"status": 200.0, "bytes": 2.0, "remote_ip": "192.0.2.1", "user_id": 42.0,
+			},
+		},
+		{
+			name:   "Anonymous",
+			method: http.MethodPost,
+			path:   "/api/v0/login",
+			exp: map[string]any{
+				"level": "warn", "method": "POST", "route": "/api/v0/login", "path": "/api/v0/login",
+				"status": 401.0, "bytes": 0.0, "remote_ip": "192.0.2.1",
+			},
+		},
+		{
+			name:   "Unmatched route",
+			method: http.MethodGet,
+			path:   "/unknown",
+			exp: map[string]any{
+				"level": "warn", "method": "GET", "route": "unmatched", "path": "/unknown",
+				"status": 404.0, "bytes": 19.0, "remote_ip": "192.0.2.1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := logger.NewZeroLogFrom(zerolog.New(&buf))
+
+			r := chi.NewRouter()
+			r.Use(chimiddleware.RequestID)
+			r.Use(AccessLog(l))
+			r.Route("/api/v0", func(r chi.Router) {
+				r.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+					ctx := ContextWithClaims(r.Context(), Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "42"}})
+					logger.FromContext(ctx).Log().Info().Msg("user read")
+					w.Write([]byte("{}"))
+				})
+				r.Post("/login", func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusUnauthorized)
+				})
+			})
+
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+
+			lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+			var access map[string]any
+			require.NoError(t, json.Unmarshal(lines[len(lines)-1], &access))
+			assert.NotEmpty(t, access["request_id"])
+			assert.Contains(t, access, "latency")
+			for key, value := range tt.exp {
+				assert.Equal(t, value, access[key], key)
+			}
+			if _, ok := tt.exp["user_id"]; !ok {
+				assert.NotContains(t, access, "user_id")
+			}
+
+			for _, line := range lines[:len(lines)-1] {
+				var handler map[string]any
+				require.NoError(t, json.Unmarshal(line, &handler))
+				assert.Equal(t, access["request_id"], handler["request_id"], "the handler lines carry the request ID")
+			}
+		})
+	}
+}
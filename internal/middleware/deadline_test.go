@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadline(t *testing.T) {
+	tests := []struct {
+		name        string
+		timeout     time.Duration
+		hasDeadline bool
+	}{
+		{
+			name:    "Disabled",
+			timeout: 0,
+		},
+		{
+			name:        "Enabled",
+			timeout:     time.Second,
+			hasDeadline: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				deadline    time.Time
+				hasDeadline bool
+			)
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				deadline, hasDeadline = r.Context().Deadline()
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/users", nil)
+			rec := httptest.NewRecorder()
+
+			Deadline(tt.timeout)(next).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.hasDeadline, hasDeadline)
+			if tt.hasDeadline {
+				assert.WithinDuration(t, time.Now().Add(tt.timeout), deadline, tt.timeout)
+			}
+		})
+	}
+}
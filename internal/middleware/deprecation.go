@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Deprecation returns a middleware that flags the responses of a route scheduled to be removed.
+// It sets the Deprecation (RFC 9745) and Sunset (RFC 8594) headers with the given dates.
+func Deprecation(deprecatedAt, sunsetAt time.Time) func(http.Handler) http.Handler {
+	deprecation := fmt.Sprintf("@%d", deprecatedAt.Unix())
+	sunset := sunsetAt.UTC().Format(http.TimeFormat)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", deprecation)
+			w.Header().Set("Sunset", sunset)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
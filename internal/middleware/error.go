@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+const (
+	authnErrStatus    = "AuthenticationError"
+	authzErrStatus    = "AuthorizationError"
+	internalErrStatus = "InternalError"
+)
+
+// problemTitles holds the problem details titles of the error statuses written by the middlewares.
+var problemTitles = map[string]string{
+	authnErrStatus:    "Authentication failed",
+	authzErrStatus:    "Permission denied",
+	internalErrStatus: "Internal server error",
+}
+
+var (
+	ErrNotSupported = errors.New("not supported")
+	ErrEmptyValue   = errors.New("empty value")
+	ErrKeyTooShort  = errors.New("key too short")
+	ErrTokenMissing = errors.New("bearer token missing")
+	ErrTokenInvalid = errors.New("invalid token")
+
+	ErrUnauthenticated  = errors.New("unauthenticated request")
+	ErrUserInactive     = errors.New("user inactive")
+	ErrPermissionDenied = errors.New("permission denied")
+)
+
+// errHTTP represents the http error responses written by the middlewares.
+type errHTTP struct {
+	Code    int    `json:"code"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+type AuthenticationErr struct {
+	Err error
+}
+
+func (e AuthenticationErr) Error() string {
+	return fmt.Sprintf("authentication failed: %s", e.Err)
+}
+
+func (e AuthenticationErr) Unwrap() error {
+	return e.Err
+}
+
+type AuthorizationErr struct {
+	Permission string
+	Err        error
+}
+
+func (e AuthorizationErr) Error() string {
+	return fmt.Sprintf("authorization failed for %q: %s", e.Permission, e.Err)
+}
+
+func (e AuthorizationErr) Unwrap() error {
+	return e.Err
+}
+
+type AlgorithmErr struct {
+	Alg string
+	Err error
+}
+
+func (e AlgorithmErr) Error() string {
+	return fmt.Sprintf("signing algorithm %q: %s", e.Alg, e.Err)
+}
+
+func (e AlgorithmErr) Unwrap() error {
+	return e.Err
+}
+
+type KeyErr struct {
+	Path string
+	Err  error
+}
+
+func (e KeyErr) Error() string {
+	return fmt.Sprintf("invalid key %v: %s", e.Path, e.Err)
+}
+
+func (e KeyErr) Unwrap() error {
+	return e.Err
+}
+
+// errJSON returns an error JSON response, or a problem details response when the request wants it
+func errJSON(w http.ResponseWriter, r *http.Request, code int, status string, err error) {
+	if WantsProblem(r) {
+		WriteProblem(w, r, Problem{
+			Type:   ProblemType(status),
+			Title:  problemTitles[status],
+			Status: code,
+			Detail: err.Error(),
+		})
+		return
+	}
+	render.Status(r, code)
+	render.JSON(w, r, errHTTP{
+		Code:    code,
+		Status:  status,
+		Message: err.Error(),
+	})
+}
@@ -1 +1,189 @@
 package middleware
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported signing algorithms.
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+)
+
+const bearerPrefix = "Bearer "
+
+// minSecretLen is the minimum length in bytes of the HS256 secret, RFC 7518 requires a key as long as the hash output.
+const minSecretLen = 32
+
+// ctxKey is the type of the keys used to store values into the request context.
+type ctxKey string
+
+const (
+	userIDCtxKey ctxKey = "userID"
+	claimsCtxKey ctxKey = "claims"
+)
+
+// Claims represents the claims carried by the access tokens.
+type Claims struct {
+	Username string `json:"username"`
+	Role     string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// UserID returns the ID of the user the token was issued for.
+func (c Claims) UserID() (uint64, error) {
+	return strconv.ParseUint(c.Subject, 10, 64)
+}
+
+// JWT issues and verifies signed access tokens.
+type JWT struct {
+	method    jwt.SigningMethod
+	signKey   any
+	verifyKey any
+	issuer    string
+	ttl       time.Duration
+}
+
+// NewJWT returns a JWT implementation configured with the signing algorithm and keys given.
+func NewJWT(cfg config.JWT) (JWT, error) {
+	j := JWT{
+		issuer: cfg.Issuer(),
+		ttl:    cfg.AccessTokenTTL(),
+	}
+
+	switch cfg.Algorithm() {
+	case AlgHS256:
+		if cfg.Secret() == "" {
+			return JWT{}, &KeyErr{Path: "secret", Err: ErrEmptyValue}
+		}
+		if len(cfg.Secret()) < minSecretLen {
+			return JWT{}, &KeyErr{Path: "secret", Err: ErrKeyTooShort}
+		}
+		j.method = jwt.SigningMethodHS256
+		j.signKey = []byte(cfg.Secret())
+		j.verifyKey = []byte(cfg.Secret())
+
+	case AlgRS256:
+		pemKey, err := os.ReadFile(cfg.PrivateKeyFile())
+		if err != nil {
+			return JWT{}, &KeyErr{Path: cfg.PrivateKeyFile(), Err: err}
+		}
+		privKey, err := jwt.ParseRSAPrivateKeyFromPEM(pemKey)
+		if err != nil {
+			return JWT{}, &KeyErr{Path: cfg.PrivateKeyFile(), Err: err}
+		}
+		pemKey, err = os.ReadFile(cfg.PublicKeyFile())
+		if err != nil {
+			return JWT{}, &KeyErr{Path: cfg.PublicKeyFile(), Err: err}
+		}
+		pubKey, err := jwt.ParseRSAPublicKeyFromPEM(pemKey)
+		if err != nil {
+			return JWT{}, &KeyErr{Path: cfg.PublicKeyFile(), Err: err}
+		}
+		j.method = jwt.SigningMethodRS256
+		j.signKey = privKey
+		j.verifyKey = pubKey
+
+	default:
+		return JWT{}, &AlgorithmErr{Alg: cfg.Algorithm(), Err: ErrNotSupported}
+	}
+
+	return j, nil
+}
+
+// IssueAccessToken returns a signed access token for the given user along with its expiration time.
+func (j JWT) IssueAccessToken(userID uint64, username, role string) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(j.ttl)
+	claims := Claims{
+		Username: username,
+		Role:     role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    j.issuer,
+			Subject:   strconv.FormatUint(userID, 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(j.method, claims).SignedString(j.signKey)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// Parse verifies the signature and the registered claims of the given token and returns its claims.
+func (j JWT) Parse(token string) (Claims, error) {
+	var claims Claims
+	_, err := jwt.ParseWithClaims(token, &claims,
+		func(t *jwt.Token) (any, error) {
+			return j.verifyKey, nil
+		},
+		jwt.WithValidMethods([]string{j.method.Alg()}),
+		jwt.WithIssuer(j.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return Claims{}, err
+	}
+	if _, err := claims.UserID(); err != nil {
+		return Claims{}, ErrTokenInvalid
+	}
+	return claims, nil
+}
+
+// Authenticate is a middleware that verifies the bearer token of the requests.
+// The authenticated user ID and the token claims are stored into the request context.
+func (j JWT) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if !strings.HasPrefix(header, bearerPrefix) {
+			unauthorized(w, r, &AuthenticationErr{Err: ErrTokenMissing})
+			return
+		}
+
+		claims, err := j.Parse(strings.TrimPrefix(header, bearerPrefix))
+		if err != nil {
+			unauthorized(w, r, &AuthenticationErr{Err: err})
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(ContextWithClaims(r.Context(), claims)))
+	})
+}
+
+// ContextWithClaims returns a copy of ctx carrying the given claims and the user ID they were issued for.
+// The user ID is recorded in the access log line of the request as well.
+func ContextWithClaims(ctx context.Context, claims Claims) context.Context {
+	userID, _ := claims.UserID()
+	setAccessUserID(ctx, userID)
+	ctx = context.WithValue(ctx, userIDCtxKey, userID)
+	return context.WithValue(ctx, claimsCtxKey, claims)
+}
+
+// UserIDFromContext returns the authenticated user ID stored into the context.
+func UserIDFromContext(ctx context.Context) (uint64, bool) {
+	id, ok := ctx.Value(userIDCtxKey).(uint64)
+	return id, ok
+}
+
+// ClaimsFromContext returns the access token claims stored into the context.
+func ClaimsFromContext(ctx context.Context) (Claims, bool) {
+	claims, ok := ctx.Value(claimsCtxKey).(Claims)
+	return claims, ok
+}
+
+func unauthorized(w http.ResponseWriter, r *http.Request, err error) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+	errJSON(w, r, http.StatusUnauthorized, authnErrStatus, err)
+}
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "camgo-jwt-test-secret-0123456789abcdef"
+
+func TestNewJWT_secret(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+		expErr error
+	}{
+		{name: "Missing", secret: "", expErr: ErrEmptyValue},
+		{name: "Too short", secret: "s3cR3tK3y", expErr: ErrKeyTooShort},
+		{name: "Valid", secret: testSecret},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CAMGO_AUTH_JWT_SECRET", tt.secret)
+			_, err := NewJWT(config.NewConfig().Auth.JWT)
+			if tt.expErr != nil {
+				assert.ErrorIs(t, err, tt.expErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestJWT_Authenticate(t *testing.T) {
+	t.Setenv("CAMGO_AUTH_JWT_SECRET", testSecret)
+	j, err := NewJWT(config.NewConfig().Auth.JWT)
+	require.NoError(t, err)
+
+	token, _, err := j.IssueAccessToken(7, "foouser", "user")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		header string
+		code   int
+		userID uint64
+	}{
+		{
+			name:   "Missing token",
+			header: "",
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:   "Bad scheme",
+			header: "Basic " + token,
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:   "Invalid token",
+			header: "Bearer some.invalid.token",
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:   "Valid",
+			header: "Bearer " + token,
+			code:   http.StatusOK,
+			userID: 7,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				userID uint64
+				claims Claims
+			)
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				userID, _ = UserIDFromContext(r.Context())
+				claims, _ = ClaimsFromContext(r.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/users", nil)
+			req.Header.Set("Authorization", tt.header)
+			rec := httptest.NewRecorder()
+
+			j.Authenticate(next).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.code, rec.Code)
+			if tt.code != http.StatusOK {
+				assert.NotEmpty(t, rec.Header().Get("WWW-Authenticate"))
+				return
+			}
+			assert.Equal(t, tt.userID, userID)
+			assert.Equal(t, "foouser", claims.Username)
+			assert.Equal(t, "user", claims.Role)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// unmatchedRoute labels the requests matching no route, so the unknown paths don't create new series.
+const unmatchedRoute = "unmatched"
+
+// HTTPMetrics records the measures of the served requests.
+type HTTPMetrics interface {
+	ObserveHTTPRequest(route, method string, status int, elapsed time.Duration)
+}
+
+// Metrics returns a middleware that records every request by route pattern, method and status.
+// The route pattern is the one matched by the router, e.g. "/api/v0/users/{id}", so the requests of a route share their series.
+func Metrics(m HTTPMetrics) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			defer func() {
+				// The route pattern is only known once the router has matched the request.
+				route := unmatchedRoute
+				if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+					route = rctx.RoutePattern()
+				}
+				status := ww.Status()
+				if status == 0 {
+					status = http.StatusOK
+				}
+				m.ObserveHTTPRequest(route, r.Method, status, time.Since(start))
+			}()
+			next.ServeHTTP(ww, r)
+		})
+	}
+}
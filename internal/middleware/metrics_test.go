@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+// httpMetricsStub records the labels of the observed requests.
+type httpMetricsStub struct {
+	requests []string
+}
+
+func (m *httpMetricsStub) ObserveHTTPRequest(route, method string, status int, _ time.Duration) {
+	m.requests = append(m.requests, method+" "+route+" "+http.StatusText(status))
+}
+
+func TestMetrics(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		exp    string
+	}{
+		{name: "Route pattern", method: http.MethodGet, path: "/api/v0/users/42", exp: "GET /api/v0/users/{id} OK"},
+		{name: "Status written", method: http.MethodDelete, path: "/api/v0/users/42", exp: "DELETE /api/v0/users/{id} Forbidden"},
+		{name: "Status not written", method: http.MethodGet, path: "/api/v0/roles", exp: "GET /api/v0/roles OK"},
+		{name: "Unmatched route", method: http.MethodGet, path: "/unknown", exp: "GET unmatched Not Found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := &httpMetricsStub{}
+			r := chi.NewRouter()
+			r.Use(Metrics(metrics))
+			r.Route("/api/v0", func(r chi.Router) {
+				r.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte("{}"))
+				})
+				r.Delete("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusForbidden)
+				})
+				r.Get("/roles", func(w http.ResponseWriter, r *http.Request) {})
+			})
+
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, []string{tt.exp}, metrics.requests)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// ProblemContentType is the media type of the RFC 7807 problem details responses.
+const ProblemContentType = "application/problem+json"
+
+// problemTypePrefix prefixes the error status to build the URI identifying the problem type.
+const problemTypePrefix = "urn:problem-type:"
+
+// problemCtxKey is the context key of the problem details preference.
+type problemCtxKey struct{}
+
+// Problem is the RFC 7807 problem details representation of an error response.
+type Problem struct {
+	Type     string         `json:"type"`
+	Title    string         `json:"title"`
+	Status   int            `json:"status"`
+	Detail   string         `json:"detail,omitempty"`
+	Instance string         `json:"instance,omitempty"`
+	Errors   []ProblemField `json:"errors,omitempty"`
+}
+
+// ProblemField is the "errors" extension member describing a field level validation failure.
+type ProblemField struct {
+	Field  string `json:"field"`
+	Detail string `json:"detail"`
+}
+
+// ProblemType returns the URI identifying the problem type of the given error status.
+func ProblemType(status string) string {
+	return problemTypePrefix + status
+}
+
+// ProblemDetails returns a middleware that selects the RFC 7807 format for the error responses of every request.
+// When disabled, the format is only selected for the clients accepting the "application/problem+json" media type.
+func ProblemDetails(enabled bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !enabled {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), problemCtxKey{}, true)))
+		})
+	}
+}
+
+// WantsProblem reports whether the error response of the request must be written as problem details.
+func WantsProblem(r *http.Request) bool {
+	if enabled, _ := r.Context().Value(problemCtxKey{}).(bool); enabled {
+		return true
+	}
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err == nil && mediaType == ProblemContentType {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteProblem writes the problem details response, the request ID is set as the problem instance.
+func WriteProblem(w http.ResponseWriter, r *http.Request, p Problem) {
+	if p.Instance == "" {
+		p.Instance = chimiddleware.GetReqID(r.Context())
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
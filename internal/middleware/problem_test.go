@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWantsProblem(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		accept  string
+		exp     bool
+	}{
+		{
+			name: "Default",
+			exp:  false,
+		},
+		{
+			name:   "Accept JSON",
+			accept: "application/json",
+			exp:    false,
+		},
+		{
+			name:   "Accept problem details",
+			accept: "application/json;q=0.9, application/problem+json",
+			exp:    true,
+		},
+		{
+			name:    "Enabled by config",
+			enabled: true,
+			accept:  "application/json",
+			exp:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bool
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				out = WantsProblem(r)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/users", nil)
+			req.Header.Set("Accept", tt.accept)
+			rec := httptest.NewRecorder()
+
+			ProblemDetails(tt.enabled)(next).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.exp, out)
+		})
+	}
+}
+
+func TestWriteProblem(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		WriteProblem(w, r, Problem{
+			Type:   ProblemType(authzErrStatus),
+			Title:  "Permission denied",
+			Status: http.StatusForbidden,
+			Detail: "authorization failed",
+		})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set(chimiddleware.RequestIDHeader, "some-request-id")
+	rec := httptest.NewRecorder()
+
+	chimiddleware.RequestID(next).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, ProblemContentType, rec.Header().Get("Content-Type"))
+	assert.Equal(t, "{\"type\":\"urn:problem-type:AuthorizationError\",\"title\":\"Permission denied\",\"status\":403,\"detail\":\"authorization failed\",\"instance\":\"some-request-id\"}\n", rec.Body.String())
+}
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/logger"
+)
+
+// PermissionChecker is an abstraction of the permission matrix used by the RBAC.
+type PermissionChecker interface {
+	HasPermission(ctx context.Context, role, permission string) (bool, error)
+}
+
+// UserRoleReader is an abstraction of the user accounts used by the RBAC.
+type UserRoleReader interface {
+	// UserRole returns the current role of the user and whether the user is active.
+	UserRole(ctx context.Context, userID uint64) (string, bool, error)
+}
+
+// RBAC authorizes the authenticated requests based on the permissions granted to the user's role.
+// The role is read from the user account rather than from the access token, so a role change or a deactivation
+// takes effect right away instead of once the token expires. It must run after the JWT.Authenticate middleware.
+type RBAC struct {
+	checker PermissionChecker
+	users   UserRoleReader
+}
+
+// NewRBAC returns a RBAC implementation backed by the given permission matrix and user accounts.
+func NewRBAC(checker PermissionChecker, users UserRoleReader) RBAC {
+	return RBAC{
+		checker: checker,
+		users:   users,
+	}
+}
+
+// RequirePermission returns a middleware that only lets through the requests whose role has been granted the permission.
+func (a RBAC) RequirePermission(permission string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := a.authorize(r.Context(), permission); err != nil {
+				authzErrJSON(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// AuthorizeOwner verifies the authenticated user either owns the resource or has been granted the permission.
+// It implements the "owner or admin" rule for the resources that belong to a single user.
+func (a RBAC) AuthorizeOwner(ctx context.Context, ownerID uint64, permission string) error {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return &AuthenticationErr{Err: ErrUnauthenticated}
+	}
+	role, err := a.currentRole(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if userID == ownerID {
+		return nil
+	}
+	return a.checkPermission(ctx, role, permission)
+}
+
+func (a RBAC) authorize(ctx context.Context, permission string) error {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return &AuthenticationErr{Err: ErrUnauthenticated}
+	}
+	role, err := a.currentRole(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return a.checkPermission(ctx, role, permission)
+}
+
+// currentRole returns the role the user holds now, the deactivated users are not authenticated anymore.
+func (a RBAC) currentRole(ctx context.Context, userID uint64) (string, error) {
+	role, active, err := a.users.UserRole(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if !active {
+		return "", &AuthenticationErr{Err: ErrUserInactive}
+	}
+	return role, nil
+}
+
+func (a RBAC) checkPermission(ctx context.Context, role, permission string) error {
+	granted, err := a.checker.HasPermission(ctx, role, permission)
+	if err != nil {
+		return err
+	}
+	if !granted {
+		return &AuthorizationErr{Permission: permission, Err: ErrPermissionDenied}
+	}
+	return nil
+}
+
+func authzErrJSON(w http.ResponseWriter, r *http.Request, err error) {
+	var (
+		authnErr *AuthenticationErr
+		authzErr *AuthorizationErr
+	)
+	switch {
+	case errors.As(err, &authnErr):
+		unauthorized(w, r, err)
+	case errors.As(err, &authzErr):
+		errJSON(w, r, http.StatusForbidden, authzErrStatus, err)
+	default:
+		logger.FromContext(r.Context()).Log().Error().Ctx(r.Context()).Err(err).Msg("authorization failed")
+		errJSON(w, r, http.StatusInternalServerError, internalErrStatus, errors.New(http.StatusText(http.StatusInternalServerError)))
+	}
+}
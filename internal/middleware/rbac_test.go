@@ -0,0 +1,181 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// permissionMatrix is a PermissionChecker stub backed by a map of roles and permissions.
+type permissionMatrix map[string][]string
+
+func (m permissionMatrix) HasPermission(_ context.Context, role, permission string) (bool, error) {
+	if role == "broken" {
+		return false, errors.New("some checker error")
+	}
+	for _, p := range m[role] {
+		if p == permission {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+var testMatrix = permissionMatrix{
+	"admin": {"users:delete", "users:update"},
+	"user":  {"users:read"},
+}
+
+// account is the role and the active flag of a user account.
+type account struct {
+	role   string
+	active bool
+}
+
+// userAccounts is a UserRoleReader stub backed by a map of user IDs and accounts.
+type userAccounts map[uint64]account
+
+func (u userAccounts) UserRole(_ context.Context, userID uint64) (string, bool, error) {
+	acc, ok := u[userID]
+	if !ok {
+		return "", false, errors.New("some repository error")
+	}
+	return acc.role, acc.active, nil
+}
+
+var testAccounts = userAccounts{
+	1: {role: "user", active: true},
+	2: {role: "admin", active: true},
+	3: {role: "broken", active: true},
+	4: {role: "admin", active: false},
+}
+
+func claimsCtx(userID, role string) context.Context {
+	return ContextWithClaims(context.Background(), Claims{
+		Role:             role,
+		RegisteredClaims: jwt.RegisteredClaims{Subject: userID},
+	})
+}
+
+func TestRBAC_RequirePermission(t *testing.T) {
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		code   int
+		status string
+	}{
+		{
+			name:   "Unauthenticated",
+			ctx:    context.Background(),
+			code:   http.StatusUnauthorized,
+			status: authnErrStatus,
+		},
+		{
+			name:   "Checker error",
+			ctx:    claimsCtx("3", "broken"),
+			code:   http.StatusInternalServerError,
+			status: internalErrStatus,
+		},
+		{
+			name:   "Account error",
+			ctx:    claimsCtx("9", "admin"),
+			code:   http.StatusInternalServerError,
+			status: internalErrStatus,
+		},
+		{
+			name:   "Denied",
+			ctx:    claimsCtx("1", "user"),
+			code:   http.StatusForbidden,
+			status: authzErrStatus,
+		},
+		{
+			name:   "Demoted since the token was issued",
+			ctx:    claimsCtx("1", "admin"),
+			code:   http.StatusForbidden,
+			status: authzErrStatus,
+		},
+		{
+			name:   "Deactivated since the token was issued",
+			ctx:    claimsCtx("4", "admin"),
+			code:   http.StatusUnauthorized,
+			status: authnErrStatus,
+		},
+		{
+			name: "Granted",
+			ctx:  claimsCtx("2", "admin"),
+			code: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			req := httptest.NewRequest(http.MethodDelete, "/users", nil).WithContext(tt.ctx)
+			rec := httptest.NewRecorder()
+
+			NewRBAC(testMatrix, testAccounts).RequirePermission("users:delete")(next).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.code, rec.Code)
+			if tt.code != http.StatusOK {
+				var resp errHTTP
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.Equal(t, tt.status, resp.Status)
+				assert.NotContains(t, resp.Message, "some", "the server errors are not disclosed")
+			}
+		})
+	}
+}
+
+func TestRBAC_AuthorizeOwner(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		ownerID uint64
+		err     error
+	}{
+		{
+			name:    "Unauthenticated",
+			ctx:     context.Background(),
+			ownerID: 1,
+			err:     &AuthenticationErr{Err: ErrUnauthenticated},
+		},
+		{
+			name:    "Owner",
+			ctx:     claimsCtx("1", "user"),
+			ownerID: 1,
+		},
+		{
+			name:    "Deactivated owner",
+			ctx:     claimsCtx("4", "admin"),
+			ownerID: 4,
+			err:     &AuthenticationErr{Err: ErrUserInactive},
+		},
+		{
+			name:    "Not owner",
+			ctx:     claimsCtx("1", "user"),
+			ownerID: 2,
+			err:     &AuthorizationErr{Permission: "users:update", Err: ErrPermissionDenied},
+		},
+		{
+			name:    "Admin",
+			ctx:     claimsCtx("2", "admin"),
+			ownerID: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewRBAC(testMatrix, testAccounts).AuthorizeOwner(tt.ctx, tt.ownerID, "users:update")
+			if tt.err != nil {
+				assert.EqualError(t, err, tt.err.Error())
+				return
+			}
+			assert.Nil(t, err)
+		})
+	}
+}
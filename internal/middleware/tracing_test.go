@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		traceparent string
+		spanName    string
+		status      int
+		code        codes.Code
+	}{
+		{name: "Route pattern", path: "/api/v0/users/42", spanName: "GET /api/v0/users/{id}", status: http.StatusOK},
+		{name: "Server error", path: "/api/v0/fail", spanName: "GET /api/v0/fail", status: http.StatusInternalServerError, code: codes.Error},
+		{name: "Unmatched route", path: "/unknown", spanName: "GET", status: http.StatusNotFound},
+		{
+			name:        "Caller trace",
+			path:        "/api/v0/users/42",
+			traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			spanName:    "GET /api/v0/users/{id}",
+			status:      http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+			var handlerSpan trace.SpanContext
+			r := chi.NewRouter()
+			r.Use(Tracing(tp.Tracer("test"), propagation.TraceContext{}))
+			r.Route("/api/v0", func(r chi.Router) {
+				r.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+					handlerSpan = trace.SpanContextFromContext(r.Context())
+				})
+				r.Get("/fail", func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusInternalServerError)
+				})
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+			span := spans[0]
+			assert.Equal(t, tt.spanName, span.Name())
+			assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+			assert.Contains(t, span.Attributes(), attribute.Int("http.response.status_code", tt.status))
+			assert.Equal(t, tt.code, span.Status().Code)
+			if handlerSpan.IsValid() {
+				assert.Equal(t, span.SpanContext().SpanID(), handlerSpan.SpanID(), "the handlers get the server span")
+			}
+			if tt.traceparent != "" {
+				assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String(), "the caller trace goes on")
+				assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+			}
+		})
+	}
+}
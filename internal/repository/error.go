@@ -7,6 +7,9 @@ import (
 
 var (
 	ErrFieldEmpty = errors.New("field value empty")
+
+	ErrDuplicated        = errors.New("already exists")
+	ErrReferenceViolated = errors.New("reference violated")
 )
 
 type Err struct {
@@ -41,3 +44,46 @@ func (e InvalidFieldErr) Error() string {
 func (e InvalidFieldErr) Unwrap() error {
 	return e.Err
 }
+
+// NotFoundErr is returned when the requested entity does not exist.
+type NotFoundErr struct {
+	Entity string
+}
+
+func (e NotFoundErr) Error() string {
+	return fmt.Sprintf("%v not found", e.Entity)
+}
+
+// NotFound reports the entity is missing, the callers match it without depending on this package.
+func (e NotFoundErr) NotFound() bool {
+	return true
+}
+
+// ConflictErr is returned when storing the entity violates a uniqueness or a reference constraint.
+type ConflictErr struct {
+	Entity     string
+	Field      string
+	Constraint string
+	Err        error
+}
+
+func (e ConflictErr) Error() string {
+	return fmt.Sprintf("%v conflict on field %v: %s", e.Entity, e.Field, e.Err)
+}
+
+func (e ConflictErr) Unwrap() error {
+	return e.Err
+}
+
+// ConnectionErr is returned when the storage cannot be reached.
+type ConnectionErr struct {
+	Err error
+}
+
+func (e ConnectionErr) Error() string {
+	return fmt.Sprintf("repository connection: %s", e.Err)
+}
+
+func (e ConnectionErr) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// The MySQL server error numbers translated into the repository error types.
+const (
+	mysqlDupEntry         = 1062 // ER_DUP_ENTRY
+	mysqlRowIsReferenced  = 1451 // ER_ROW_IS_REFERENCED_2
+	mysqlNoReferencedRow  = 1452 // ER_NO_REFERENCED_ROW_2
+	mysqlTooManyConns     = 1040 // ER_CON_COUNT_ERROR
+	mysqlServerShutdown   = 1053 // ER_SERVER_SHUTDOWN
+	mysqlTooManyUserConns = 1203 // ER_TOO_MANY_USER_CONNECTIONS
+	mysqlConnectionKilled = 1927 // ER_CONNECTION_KILLED, MariaDB only
+)
+
+// mysqlErr translates the errors returned by the MySQL driver into the repository error types,
+// the same way as pgErr does.
+func mysqlErr(ctx context.Context, entityName string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return &Err{Err: ctxErr}
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return &Err{Err: err}
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return &NotFoundErr{Entity: entityName}
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.Is(err, mysql.ErrInvalidConn) {
+		return &ConnectionErr{Err: err}
+	}
+
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) {
+		switch myErr.Number {
+		case mysqlDupEntry:
+			constraint := mysqlDupEntryKey(myErr.Message)
+			return &ConflictErr{Entity: entityName, Field: constraintField(constraint), Constraint: constraint, Err: ErrDuplicated}
+		case mysqlRowIsReferenced, mysqlNoReferencedRow:
+			constraint := mysqlForeignKey(myErr.Message)
+			return &ConflictErr{Entity: entityName, Field: constraintField(constraint), Constraint: constraint, Err: ErrReferenceViolated}
+		case mysqlTooManyConns, mysqlServerShutdown, mysqlTooManyUserConns, mysqlConnectionKilled:
+			return &ConnectionErr{Err: err}
+		}
+		return &Err{Err: err}
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return &ConnectionErr{Err: err}
+	}
+	return &Err{Err: err}
+}
+
+// mysqlDupEntryKey returns the unique key of a duplicate entry message.
+// e.g. "Duplicate entry 'jdoe' for key 'users.users_username_key'" returns "users_username_key"
+// The primary keys are named after the PostgreSQL ones, "roles.PRIMARY" returns "roles_pkey".
+func mysqlDupEntryKey(msg string) string {
+	const prefix = "for key '"
+	i := strings.LastIndex(msg, prefix)
+	if i < 0 {
+		return ""
+	}
+	key := strings.TrimSuffix(msg[i+len(prefix):], "'")
+	table, name, qualified := strings.Cut(key, ".")
+	if !qualified {
+		return key
+	}
+	if name == "PRIMARY" {
+		return table + "_pkey"
+	}
+	return name
+}
+
+// mysqlForeignKey returns the foreign key of a reference violation message.
+// e.g. "Cannot add or update a child row: a foreign key constraint fails (`camgo`.`users`, CONSTRAINT `users_role_fkey` ...)"
+func mysqlForeignKey(msg string) string {
+	_, after, ok := strings.Cut(msg, "CONSTRAINT `")
+	if !ok {
+		return ""
+	}
+	name, _, _ := strings.Cut(after, "`")
+	return name
+}
+
+// mysqlRowsAffected returns a NotFoundErr when the statement did not match any row.
+func mysqlRowsAffected(ctx context.Context, entityName string, res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return mysqlErr(ctx, entityName, err)
+	}
+	if n == 0 {
+		return &NotFoundErr{Entity: entityName}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMySQLErr(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	dbErr := errors.New("some db error")
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		exp  error
+	}{
+		{
+			name: "Nil",
+			ctx:  context.Background(),
+			err:  nil,
+			exp:  nil,
+		},
+		{
+			name: "No rows",
+			ctx:  context.Background(),
+			err:  sql.ErrNoRows,
+			exp:  &NotFoundErr{Entity: userEntity},
+		},
+		{
+			name: "Duplicate entry",
+			ctx:  context.Background(),
+			err:  &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'jdoe@camgo.dev' for key 'users.users_email_key'"},
+			exp:  &ConflictErr{Entity: userEntity, Field: "email", Constraint: "users_email_key", Err: ErrDuplicated},
+		},
+		{
+			name: "Duplicate entry without table",
+			ctx:  context.Background(),
+			err:  &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'jdoe' for key 'users_username_key'"},
+			exp:  &ConflictErr{Entity: userEntity, Field: "username", Constraint: "users_username_key", Err: ErrDuplicated},
+		},
+		{
+			name: "Duplicate primary key",
+			ctx:  context.Background(),
+			err:  &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'admin' for key 'roles.PRIMARY'"},
+			exp:  &ConflictErr{Entity: userEntity, Field: "name", Constraint: "roles_pkey", Err: ErrDuplicated},
+		},
+		{
+			name: "Missing reference",
+			ctx:  context.Background(),
+			err: &mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row: a foreign key constraint fails " +
+				"(`camgo`.`users`, CONSTRAINT `users_role_fkey` FOREIGN KEY (`role`) REFERENCES `roles` (`name`))"},
+			exp: &ConflictErr{Entity: userEntity, Field: "role", Constraint: "users_role_fkey", Err: ErrReferenceViolated},
+		},
+		{
+			name: "Referenced row",
+			ctx:  context.Background(),
+			err: &mysql.MySQLError{Number: 1451, Message: "Cannot delete or update a parent row: a foreign key constraint fails " +
+				"(`camgo`.`users`, CONSTRAINT `users_role_fkey` FOREIGN KEY (`role`) REFERENCES `roles` (`name`))"},
+			exp: &ConflictErr{Entity: userEntity, Field: "role", Constraint: "users_role_fkey", Err: ErrReferenceViolated},
+		},
+		{
+			name: "Too many connections",
+			ctx:  context.Background(),
+			err:  &mysql.MySQLError{Number: 1040},
+			exp:  &ConnectionErr{Err: &mysql.MySQLError{Number: 1040}},
+		},
+		{
+			name: "Invalid connection",
+			ctx:  context.Background(),
+			err:  mysql.ErrInvalidConn,
+			exp:  &ConnectionErr{Err: mysql.ErrInvalidConn},
+		},
+		{
+			name: "Cancelled request",
+			ctx:  canceled,
+			err:  &mysql.MySQLError{Number: 1317},
+			exp:  &Err{Err: context.Canceled},
+		},
+		{
+			name: "Other driver error",
+			ctx:  context.Background(),
+			err:  &mysql.MySQLError{Number: 1064},
+			exp:  &Err{Err: &mysql.MySQLError{Number: 1064}},
+		},
+		{
+			name: "Unknown error",
+			ctx:  context.Background(),
+			err:  dbErr,
+			exp:  &Err{Err: dbErr},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := mysqlErr(tt.ctx, userEntity, tt.err)
+			assert.Equal(t, tt.exp, err)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+
+	"github.com/lib/pq"
+)
+
+const (
+	userEntity  = "user"
+	tokenEntity = "refresh token"
+	roleEntity  = "role"
+)
+
+// constraintFields maps the constraints to the entity fields they guard.
+// The MySQL migrations name their keys and foreign keys after the PostgreSQL constraints.
+var constraintFields = map[string]string{
+	"users_email_key":               "email",
+	"users_username_key":            "username",
+	"users_role_fkey":               "role",
+	"refresh_tokens_token_hash_key": "token_hash",
+	"refresh_tokens_user_id_fkey":   "user_id",
+	"roles_pkey":                    "name",
+	"role_permissions_pkey":         "permission",
+	"role_permissions_role_fkey":    "role",
+}
+
+// pgErr translates the errors returned by the PostgreSQL driver into the repository error types.
+// The context errors are kept as they are, so the callers can tell a cancelled or expired request apart.
+func pgErr(ctx context.Context, entityName string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return &Err{Err: ctxErr}
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return &Err{Err: err}
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return &NotFoundErr{Entity: entityName}
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) {
+		return &ConnectionErr{Err: err}
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch {
+		case pqErr.Code.Name() == "unique_violation":
+			return &ConflictErr{Entity: entityName, Field: constraintField(pqErr.Constraint), Constraint: pqErr.Constraint, Err: ErrDuplicated}
+		case pqErr.Code.Name() == "foreign_key_violation":
+			return &ConflictErr{Entity: entityName, Field: constraintField(pqErr.Constraint), Constraint: pqErr.Constraint, Err: ErrReferenceViolated}
+		case pqErr.Code.Class() == "08", // connection exception
+			pqErr.Code.Class() == "53", // insufficient resources, e.g. too many connections
+			pqErr.Code.Class() == "57" && pqErr.Code.Name() != "query_canceled": // operator intervention, e.g. admin shutdown
+			return &ConnectionErr{Err: err}
+		}
+		return &Err{Err: err}
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return &ConnectionErr{Err: err}
+	}
+	return &Err{Err: err}
+}
+
+// constraintField returns the field guarded by the constraint, the constraint name is used when unknown.
+func constraintField(constraint string) string {
+	if field, ok := constraintFields[constraint]; ok {
+		return field
+	}
+	return constraint
+}
+
+// rowsAffected returns a NotFoundErr when the statement did not change any row.
+func rowsAffected(ctx context.Context, entityName string, res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return pgErr(ctx, entityName, err)
+	}
+	if n == 0 {
+		return &NotFoundErr{Entity: entityName}
+	}
+	return nil
+}
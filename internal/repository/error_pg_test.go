@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPgErr(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	dbErr := errors.New("some db error")
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		exp  error
+	}{
+		{
+			name: "Nil",
+			ctx:  context.Background(),
+			err:  nil,
+			exp:  nil,
+		},
+		{
+			name: "No rows",
+			ctx:  context.Background(),
+			err:  sql.ErrNoRows,
+			exp:  &NotFoundErr{Entity: userEntity},
+		},
+		{
+			name: "Unique violation",
+			ctx:  context.Background(),
+			err:  &pq.Error{Code: "23505", Constraint: "users_email_key"},
+			exp:  &ConflictErr{Entity: userEntity, Field: "email", Constraint: "users_email_key", Err: ErrDuplicated},
+		},
+		{
+			name: "Foreign key violation",
+			ctx:  context.Background(),
+			err:  &pq.Error{Code: "23503", Constraint: "users_role_fkey"},
+			exp:  &ConflictErr{Entity: userEntity, Field: "role", Constraint: "users_role_fkey", Err: ErrReferenceViolated},
+		},
+		{
+			name: "Unknown constraint",
+			ctx:  context.Background(),
+			err:  &pq.Error{Code: "23505", Constraint: "users_nickname_key"},
+			exp:  &ConflictErr{Entity: userEntity, Field: "users_nickname_key", Constraint: "users_nickname_key", Err: ErrDuplicated},
+		},
+		{
+			name: "Too many connections",
+			ctx:  context.Background(),
+			err:  &pq.Error{Code: "53300"},
+			exp:  &ConnectionErr{Err: &pq.Error{Code: "53300"}},
+		},
+		{
+			name: "Admin shutdown",
+			ctx:  context.Background(),
+			err:  &pq.Error{Code: "57P01"},
+			exp:  &ConnectionErr{Err: &pq.Error{Code: "57P01"}},
+		},
+		{
+			name: "Bad connection",
+			ctx:  context.Background(),
+			err:  driver.ErrBadConn,
+			exp:  &ConnectionErr{Err: driver.ErrBadConn},
+		},
+		{
+			name: "Network error",
+			ctx:  context.Background(),
+			err:  &net.OpError{Op: "dial", Err: dbErr},
+			exp:  &ConnectionErr{Err: &net.OpError{Op: "dial", Err: dbErr}},
+		},
+		{
+			name: "Cancelled request",
+			ctx:  canceled,
+			err:  &pq.Error{Code: "57014"},
+			exp:  &Err{Err: context.Canceled},
+		},
+		{
+			name: "Other driver error",
+			ctx:  context.Background(),
+			err:  &pq.Error{Code: "42601"},
+			exp:  &Err{Err: &pq.Error{Code: "42601"}},
+		},
+		{
+			name: "Unknown error",
+			ctx:  context.Background(),
+			err:  dbErr,
+			exp:  &Err{Err: dbErr},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := pgErr(tt.ctx, userEntity, tt.err)
+			assert.Equal(t, tt.exp, err)
+		})
+	}
+}
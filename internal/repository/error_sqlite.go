@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// sqliteFKMessage starts the messages of the foreign key violations, including the ones raised by the triggers
+// enforcing the references SQLite cannot declare. e.g. "FOREIGN KEY constraint failed: users.role"
+const sqliteFKMessage = "FOREIGN KEY constraint failed"
+
+// sqliteErr translates the errors returned by the SQLite driver into the repository error types,
+// the same way as pgErr does.
+func sqliteErr(ctx context.Context, entityName string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return &Err{Err: ctxErr}
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return &Err{Err: err}
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return &NotFoundErr{Entity: entityName}
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) {
+		return &ConnectionErr{Err: err}
+	}
+
+	var liteErr *sqlite.Error
+	if errors.As(err, &liteErr) {
+		switch liteErr.Code() {
+		case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+			constraint := sqliteConstraint(liteErr.Error(), "UNIQUE constraint failed")
+			return &ConflictErr{Entity: entityName, Field: sqliteConstraintField(constraint), Constraint: constraint, Err: ErrDuplicated}
+		case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY, sqlite3.SQLITE_CONSTRAINT_TRIGGER:
+			if strings.Contains(liteErr.Error(), sqliteFKMessage) {
+				constraint := sqliteConstraint(liteErr.Error(), sqliteFKMessage)
+				return &ConflictErr{Entity: entityName, Field: sqliteConstraintField(constraint), Constraint: constraint, Err: ErrReferenceViolated}
+			}
+		}
+		switch liteErr.Code() & 0xff {
+		case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED, sqlite3.SQLITE_CANTOPEN, sqlite3.SQLITE_IOERR:
+			return &ConnectionErr{Err: err}
+		}
+	}
+	return &Err{Err: err}
+}
+
+// sqliteConstraint returns the columns reported after the message prefix. e.g. "users.email"
+// SQLite does not report the columns of the foreign keys it enforces itself, an empty string is returned then.
+func sqliteConstraint(msg, prefix string) string {
+	_, after, ok := strings.Cut(msg, prefix+": ")
+	if !ok {
+		return ""
+	}
+	constraint, _, _ := strings.Cut(after, " (")
+	return constraint
+}
+
+// sqliteConstraintField returns the field of the first "table.column" of the constraint.
+func sqliteConstraintField(constraint string) string {
+	first, _, _ := strings.Cut(constraint, ", ")
+	_, field, ok := strings.Cut(first, ".")
+	if !ok {
+		return first
+	}
+	return field
+}
+
+// sqliteRowsAffected returns a NotFoundErr when the statement did not change any row.
+func sqliteRowsAffected(ctx context.Context, entityName string, res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return sqliteErr(ctx, entityName, err)
+	}
+	if n == 0 {
+		return &NotFoundErr{Entity: entityName}
+	}
+	return nil
+}
@@ -0,0 +1,4 @@
+package repository
+
+// NewSQLiteDB is exported for the contract tests of the repository_test package.
+var NewSQLiteDB = newSQLiteDB
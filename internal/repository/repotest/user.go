@@ -0,0 +1,228 @@
+// Package repotest holds the contract test suites run by every repository implementation.
+package repotest
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+	"github.com/wizeline/CA-Microservices-Go/internal/repository"
+	"github.com/wizeline/CA-Microservices-Go/internal/service"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// unknownID is the ID of a user never stored by the suite.
+const unknownID uint64 = 999999
+
+// UserRepoSuite checks the contract of a service.UserRepo implementation.
+// NewRepo returns an empty repository storing the built-in roles, it is called before every test.
+// e.g. suite.Run(t, &repotest.UserRepoSuite{NewRepo: func(t *testing.T) service.UserRepo { ... }})
+type UserRepoSuite struct {
+	suite.Suite
+	NewRepo func(t *testing.T) service.UserRepo
+
+	ctx  context.Context
+	repo service.UserRepo
+}
+
+func (s *UserRepoSuite) SetupTest() {
+	s.Require().NotNil(s.NewRepo, "the repository factory is required")
+	s.ctx = context.Background()
+	s.repo = s.NewRepo(s.T())
+}
+
+// create stores the user and returns it as read back, with its generated ID.
+func (s *UserRepoSuite) create(user entity.User) entity.User {
+	s.T().Helper()
+	s.Require().NoError(s.repo.Create(s.ctx, user))
+
+	page, err := s.repo.Search(s.ctx, entity.UserQuery{Filters: []entity.Filter{
+		{Field: entity.UserFieldUsername, Op: entity.FilterEq, Value: user.Username},
+	}})
+	s.Require().NoError(err)
+	s.Require().Len(page.Users, 1, "the created user is found by username")
+	return page.Users[0]
+}
+
+// newUser returns a valid user, the email and the username are derived from the given name.
+func newUser(name string) entity.User {
+	return entity.User{
+		FirstName: "John",
+		LastName:  "Doe",
+		Email:     name + "@camgo.dev",
+		BirthDay:  time.Date(1990, 4, 21, 0, 0, 0, 0, time.UTC),
+		Username:  name,
+		Passwd:    "$2a$10$hash",
+		Role:      entity.RoleUser,
+	}
+}
+
+func (s *UserRepoSuite) TestCreateAndRead() {
+	before := time.Now()
+	created := s.create(newUser("jdoe"))
+
+	user, err := s.repo.Read(s.ctx, created.ID)
+	s.Require().NoError(err)
+	s.Equal(created.ID, user.ID)
+	s.Equal("John", user.FirstName)
+	s.Equal("Doe", user.LastName)
+	s.Equal("jdoe@camgo.dev", user.Email)
+	s.Equal("jdoe", user.Username)
+	s.Equal("$2a$10$hash", user.Passwd)
+	s.Equal(entity.RoleUser, user.Role)
+	s.True(time.Date(1990, 4, 21, 0, 0, 0, 0, time.UTC).Equal(user.BirthDay), "the birthday is kept, got %s", user.BirthDay)
+	s.False(user.Active, "the users are created inactive")
+	s.False(user.LastLogin.Valid)
+	s.WithinDuration(before, user.CreatedAt, time.Minute, "the creation time is set by the repository")
+	s.False(user.UpdatedAt.Valid, "a created user has not been updated")
+}
+
+func (s *UserRepoSuite) TestReadAll() {
+	users, err := s.repo.ReadAll(s.ctx)
+	s.Require().NoError(err)
+	s.NotNil(users, "an empty repository returns an empty slice")
+	s.Empty(users)
+
+	s.create(newUser("jdoe"))
+	s.create(newUser("jroe"))
+	users, err = s.repo.ReadAll(s.ctx)
+	s.Require().NoError(err)
+	usernames := make([]string, 0, len(users))
+	for _, u := range users {
+		usernames = append(usernames, u.Username)
+	}
+	s.ElementsMatch([]string{"jdoe", "jroe"}, usernames)
+}
+
+func (s *UserRepoSuite) TestCreate_conflicts() {
+	s.create(newUser("jdoe"))
+
+	tests := []struct {
+		name  string
+		user  entity.User
+		field string
+	}{
+		{name: "Duplicated email", user: func() entity.User { u := newUser("jroe"); u.Email = "jdoe@camgo.dev"; return u }(), field: "email"},
+		{name: "Duplicated username", user: func() entity.User { u := newUser("jroe"); u.Username = "jdoe"; return u }(), field: "username"},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			err := s.repo.Create(s.ctx, tt.user)
+			var conflict *repository.ConflictErr
+			s.Require().ErrorAs(err, &conflict)
+			s.Equal(tt.field, conflict.Field)
+			s.ErrorIs(err, repository.ErrDuplicated)
+		})
+	}
+
+	users, err := s.repo.ReadAll(s.ctx)
+	s.Require().NoError(err)
+	s.Len(users, 1, "the conflicting users are not stored")
+}
+
+func (s *UserRepoSuite) TestUpdate() {
+	user := s.create(newUser("jdoe"))
+	lastLogin := time.Now().UTC().Truncate(time.Microsecond)
+
+	user.FirstName = "Johnny"
+	user.Email = "johnny@camgo.dev"
+	user.BirthDay = time.Date(1991, 5, 22, 0, 0, 0, 0, time.UTC)
+	user.Username = "johnny"
+	user.Passwd = "$2a$10$other"
+	user.Role = entity.RoleAdmin
+	user.Active = true
+	user.LastLogin = sql.NullTime{Time: lastLogin, Valid: true}
+	s.Require().NoError(s.repo.Update(s.ctx, user))
+
+	updated, err := s.repo.Read(s.ctx, user.ID)
+	s.Require().NoError(err)
+	s.Equal("Johnny", updated.FirstName)
+	s.Equal("johnny@camgo.dev", updated.Email)
+	s.True(user.BirthDay.Equal(updated.BirthDay), "the birthday is updated, got %s", updated.BirthDay)
+	s.Equal("johnny", updated.Username)
+	s.Equal("$2a$10$other", updated.Passwd)
+	s.Equal(entity.RoleAdmin, updated.Role)
+	s.True(updated.Active, "the active flag is updated")
+	s.Require().True(updated.LastLogin.Valid)
+	s.True(lastLogin.Equal(updated.LastLogin.Time), "the last login round-trips, expected %s got %s", lastLogin, updated.LastLogin.Time)
+	s.True(user.CreatedAt.Equal(updated.CreatedAt), "the creation time is kept")
+	s.Require().True(updated.UpdatedAt.Valid, "the update time is set by the repository")
+	s.False(updated.UpdatedAt.Time.Before(updated.CreatedAt))
+
+	updated.Active = false
+	updated.LastLogin = sql.NullTime{}
+	s.Require().NoError(s.repo.Update(s.ctx, updated))
+	updated, err = s.repo.Read(s.ctx, user.ID)
+	s.Require().NoError(err)
+	s.False(updated.Active, "the active flag is cleared")
+	s.False(updated.LastLogin.Valid, "the last login is cleared")
+}
+
+func (s *UserRepoSuite) TestUpdate_unchanged() {
+	user := s.create(newUser("jdoe"))
+	s.NoError(s.repo.Update(s.ctx, user), "updating a user with its stored values is not a not found error")
+}
+
+func (s *UserRepoSuite) TestUpdate_conflict() {
+	s.create(newUser("jdoe"))
+	user := s.create(newUser("jroe"))
+
+	user.Email = "jdoe@camgo.dev"
+	err := s.repo.Update(s.ctx, user)
+	var conflict *repository.ConflictErr
+	s.Require().ErrorAs(err, &conflict)
+	s.Equal("email", conflict.Field)
+
+	stored, err := s.repo.Read(s.ctx, user.ID)
+	s.Require().NoError(err)
+	s.Equal("jroe@camgo.dev", stored.Email, "the conflicting update is not stored")
+}
+
+func (s *UserRepoSuite) TestDelete() {
+	user := s.create(newUser("jdoe"))
+	s.Require().NoError(s.repo.Delete(s.ctx, user.ID))
+
+	_, err := s.repo.Read(s.ctx, user.ID)
+	s.ErrorAs(err, new(*repository.NotFoundErr))
+	s.ErrorAs(s.repo.Delete(s.ctx, user.ID), new(*repository.NotFoundErr), "a user is deleted once")
+
+	s.create(newUser("jdoe"))
+	users, err := s.repo.ReadAll(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(users, 1, "the email and the username are released")
+	s.NotEqual(user.ID, users[0].ID, "the IDs are not reused")
+}
+
+func (s *UserRepoSuite) TestNotFound() {
+	_, err := s.repo.Read(s.ctx, unknownID)
+	s.ErrorAs(err, new(*repository.NotFoundErr), "Read")
+
+	user := newUser("jdoe")
+	user.ID = unknownID
+	s.ErrorAs(s.repo.Update(s.ctx, user), new(*repository.NotFoundErr), "Update")
+	s.ErrorAs(s.repo.Delete(s.ctx, unknownID), new(*repository.NotFoundErr), "Delete")
+}
+
+func (s *UserRepoSuite) TestSearch() {
+	for _, name := range []string{"ada", "bob", "jdoe", "jroe"} {
+		s.create(newUser(name))
+	}
+
+	page, err := s.repo.Search(s.ctx, entity.UserQuery{
+		Filters: []entity.Filter{{Field: entity.UserFieldUsername, Op: entity.FilterPrefix, Value: "j"}},
+		Sort:    []entity.Sort{{Field: entity.UserFieldUsername, Desc: true}},
+		Limit:   1,
+	})
+	s.Require().NoError(err)
+	s.Equal(2, page.Total, "the total counts every matching user")
+	s.Require().Len(page.Users, 1)
+	s.Equal("jroe", page.Users[0].Username)
+
+	_, err = s.repo.Search(s.ctx, entity.UserQuery{
+		Filters: []entity.Filter{{Field: "passwd", Op: entity.FilterEq, Value: "x"}},
+	})
+	s.ErrorAs(err, new(*repository.InvalidFieldErr), "the unsupported fields are rejected")
+}
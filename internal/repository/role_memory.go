@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+)
+
+// RoleRepositoryMemory is a thread-safe in-memory storage of roles, starting with the built-in roles
+// and the default permission matrix loaded by the migrations.
+// It is intended for local development and testing purposes, the roles referenced by users can be deleted.
+type RoleRepositoryMemory struct {
+	mu    *sync.RWMutex
+	roles map[string]entity.Role
+}
+
+func NewRoleRepositoryMemory() RoleRepositoryMemory {
+	now := time.Now()
+	return RoleRepositoryMemory{
+		mu: &sync.RWMutex{},
+		roles: map[string]entity.Role{
+			entity.RoleAdmin: {
+				Name:        entity.RoleAdmin,
+				Description: "Manages every user and the roles permission matrix",
+				Permissions: []string{entity.PermRolesManage, entity.PermUsersDelete, entity.PermUsersRead, entity.PermUsersUpdate},
+				CreatedAt:   now,
+			},
+			entity.RoleUser: {
+				Name:        entity.RoleUser,
+				Description: "Default role of the registered users",
+				Permissions: []string{entity.PermUsersRead},
+				CreatedAt:   now,
+			},
+		},
+	}
+}
+
+func (r RoleRepositoryMemory) Create(ctx context.Context, role entity.Role) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.roles[role.Name]; ok {
+		return &ConflictErr{Entity: roleEntity, Field: "name", Constraint: "roles_pkey", Err: ErrDuplicated}
+	}
+	role.Permissions = sortedPermissions(role.Permissions)
+	role.CreatedAt = time.Now()
+	role.UpdatedAt = sql.NullTime{}
+	r.roles[role.Name] = role
+	return nil
+}
+
+func (r RoleRepositoryMemory) Read(ctx context.Context, name string) (entity.Role, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	role, ok := r.roles[name]
+	if !ok {
+		return entity.Role{}, &NotFoundErr{Entity: roleEntity}
+	}
+	role.Permissions = slices.Clone(role.Permissions)
+	return role, nil
+}
+
+func (r RoleRepositoryMemory) ReadAll(ctx context.Context) ([]entity.Role, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	roles := make([]entity.Role, 0, len(r.roles))
+	for _, role := range r.roles {
+		role.Permissions = slices.Clone(role.Permissions)
+		roles = append(roles, role)
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+	return roles, nil
+}
+
+// Update replaces the description and the permissions of the role.
+func (r RoleRepositoryMemory) Update(ctx context.Context, role entity.Role) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.roles[role.Name]
+	if !ok {
+		return &NotFoundErr{Entity: roleEntity}
+	}
+	stored.Description = role.Description
+	stored.Permissions = sortedPermissions(role.Permissions)
+	stored.UpdatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	r.roles[role.Name] = stored
+	return nil
+}
+
+func (r RoleRepositoryMemory) Delete(ctx context.Context, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.roles[name]; !ok {
+		return &NotFoundErr{Entity: roleEntity}
+	}
+	delete(r.roles, name)
+	return nil
+}
+
+// HasPermission reports whether the role has been granted the given permission.
+func (r RoleRepositoryMemory) HasPermission(ctx context.Context, role, permission string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return slices.Contains(r.roles[role].Permissions, permission), nil
+}
+
+// sortedPermissions returns the permissions sorted and without duplicates, as they are read from the database.
+func sortedPermissions(permissions []string) []string {
+	sorted := slices.Clone(permissions)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+	if sorted == nil {
+		sorted = make([]string, 0)
+	}
+	return sorted
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleRepositoryMemory(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRoleRepositoryMemory()
+
+	granted, err := repo.HasPermission(ctx, entity.RoleAdmin, entity.PermRolesManage)
+	require.NoError(t, err)
+	assert.True(t, granted, "the built-in roles are loaded")
+
+	auditor := entity.Role{Name: "auditor", Permissions: []string{entity.PermUsersRead, entity.PermUsersRead}}
+	require.NoError(t, repo.Create(ctx, auditor))
+	assert.ErrorAs(t, repo.Create(ctx, auditor), new(*ConflictErr))
+
+	role, err := repo.Read(ctx, "auditor")
+	require.NoError(t, err)
+	assert.Equal(t, []string{entity.PermUsersRead}, role.Permissions)
+
+	role.Description = "Reads and updates the users"
+	role.Permissions = []string{entity.PermUsersUpdate, entity.PermUsersRead}
+	require.NoError(t, repo.Update(ctx, role))
+	role, err = repo.Read(ctx, "auditor")
+	require.NoError(t, err)
+	assert.Equal(t, []string{entity.PermUsersRead, entity.PermUsersUpdate}, role.Permissions)
+	assert.True(t, role.UpdatedAt.Valid)
+
+	roles, err := repo.ReadAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, roles, 3)
+	assert.Equal(t, []string{"admin", "auditor", "user"}, []string{roles[0].Name, roles[1].Name, roles[2].Name})
+
+	require.NoError(t, repo.Delete(ctx, "auditor"))
+	_, err = repo.Read(ctx, "auditor")
+	assert.ErrorAs(t, err, new(*NotFoundErr))
+	assert.ErrorAs(t, repo.Update(ctx, auditor), new(*NotFoundErr))
+	assert.ErrorAs(t, repo.Delete(ctx, "auditor"), new(*NotFoundErr))
+
+	granted, err = repo.HasPermission(ctx, "auditor", entity.PermUsersRead)
+	require.NoError(t, err)
+	assert.False(t, granted)
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+)
+
+type RoleRepositoryPg struct {
+	db *sql.DB
+}
+
+func NewRoleRepositoryPg(db *sql.DB) RoleRepositoryPg {
+	return RoleRepositoryPg{
+		db: db,
+	}
+}
+
+func (r RoleRepositoryPg) Create(ctx context.Context, role entity.Role) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return pgErr(ctx, roleEntity, err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO roles (name, description) VALUES ($1, $2)", role.Name, role.Description)
+	if err != nil {
+		return pgErr(ctx, roleEntity, err)
+	}
+	if err := insertPermissions(ctx, tx, role.Name, role.Permissions); err != nil {
+		return pgErr(ctx, roleEntity, err)
+	}
+	return pgErr(ctx, roleEntity, tx.Commit())
+}
+
+func (r RoleRepositoryPg) Read(ctx context.Context, name string) (entity.Role, error) {
+	var role entity.Role
+	row := r.db.QueryRowContext(ctx, `
+		SELECT name, description, created_at, updated_at
+		FROM roles WHERE name = $1`, name)
+	err := row.Scan(&role.Name, &role.Description, &role.CreatedAt, &role.UpdatedAt)
+	if err != nil {
+		return entity.Role{}, pgErr(ctx, roleEntity, err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, "SELECT permission FROM role_permissions WHERE role = $1 ORDER BY permission", name)
+	if err != nil {
+		return entity.Role{}, pgErr(ctx, roleEntity, err)
+	}
+	defer rows.Close()
+
+	role.Permissions = make([]string, 0)
+	for rows.Next() {
+		var perm string
+		if err := rows.Scan(&perm); err != nil {
+			return entity.Role{}, pgErr(ctx, roleEntity, err)
+		}
+		role.Permissions = append(role.Permissions, perm)
+	}
+	if err := rows.Err(); err != nil {
+		return entity.Role{}, pgErr(ctx, roleEntity, err)
+	}
+	return role, nil
+}
+
+func (r RoleRepositoryPg) ReadAll(ctx context.Context) ([]entity.Role, error) {
+	rows, err := r.db.QueryContext(ctx, `
+	SELECT r.name, r.description, r.created_at, r.updated_at, p.permission
+	FROM roles r
+	LEFT JOIN role_permissions p ON p.role = r.name
+	ORDER BY r.name, p.permission
+	`)
+	if err != nil {
+		return nil, pgErr(ctx, roleEntity, err)
+	}
+	defer rows.Close()
+
+	roles := make([]entity.Role, 0)
+	for rows.Next() {
+		var (
+			role entity.Role
+			perm sql.NullString
+		)
+		err := rows.Scan(&role.Name, &role.Description, &role.CreatedAt, &role.UpdatedAt, &perm)
+		if err != nil {
+			return nil, pgErr(ctx, roleEntity, err)
+		}
+		if last := len(roles) - 1; last < 0 || roles[last].Name != role.Name {
+			role.Permissions = make([]string, 0)
+			roles = append(roles, role)
+		}
+		if perm.Valid {
+			last := len(roles) - 1
+			roles[last].Permissions = append(roles[last].Permissions, perm.String)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, pgErr(ctx, roleEntity, err)
+	}
+
+	return roles, nil
+}
+
+// Update replaces the description and the permissions of the role.
+func (r RoleRepositoryPg) Update(ctx context.Context, role entity.Role) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return pgErr(ctx, roleEntity, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, "UPDATE roles SET description = $1, updated_at = NOW() WHERE name = $2", role.Description, role.Name)
+	if err != nil {
+		return pgErr(ctx, roleEntity, err)
+	}
+	if err := rowsAffected(ctx, roleEntity, res); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM role_permissions WHERE role = $1", role.Name); err != nil {
+		return pgErr(ctx, roleEntity, err)
+	}
+	if err := insertPermissions(ctx, tx, role.Name, role.Permissions); err != nil {
+		return pgErr(ctx, roleEntity, err)
+	}
+	return pgErr(ctx, roleEntity, tx.Commit())
+}
+
+func (r RoleRepositoryPg) Delete(ctx context.Context, name string) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM roles WHERE name = $1", name)
+	if err != nil {
+		return pgErr(ctx, roleEntity, err)
+	}
+	return rowsAffected(ctx, roleEntity, res)
+}
+
+// HasPermission reports whether the role has been granted the given permission.
+func (r RoleRepositoryPg) HasPermission(ctx context.Context, role, permission string) (bool, error) {
+	var granted bool
+	row := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM role_permissions WHERE role = $1 AND permission = $2
+		)`, role, permission)
+	if err := row.Scan(&granted); err != nil {
+		return false, pgErr(ctx, roleEntity, err)
+	}
+	return granted, nil
+}
+
+func insertPermissions(ctx context.Context, tx *sql.Tx, role string, permissions []string) error {
+	for _, perm := range permissions {
+		_, err := tx.ExecContext(ctx, "INSERT INTO role_permissions (role, permission) VALUES ($1, $2) ON CONFLICT DO NOTHING", role, perm)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
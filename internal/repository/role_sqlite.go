@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+)
+
+// RoleRepositorySQLite stores the roles and their permissions in a SQLite database file.
+// The users reference their role through triggers, SQLite reports their violations as foreign key ones.
+type RoleRepositorySQLite struct {
+	db *sql.DB
+}
+
+func NewRoleRepositorySQLite(db *sql.DB) RoleRepositorySQLite {
+	return RoleRepositorySQLite{
+		db: db,
+	}
+}
+
+func (r RoleRepositorySQLite) Create(ctx context.Context, role entity.Role) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return sqliteErr(ctx, roleEntity, err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO roles (name, description, created_at) VALUES ($1, $2, $3)", role.Name, role.Description, time.Now().UTC())
+	if err != nil {
+		return sqliteErr(ctx, roleEntity, err)
+	}
+	if err := insertPermissions(ctx, tx, role.Name, role.Permissions); err != nil {
+		return sqliteErr(ctx, roleEntity, err)
+	}
+	return sqliteErr(ctx, roleEntity, tx.Commit())
+}
+
+func (r RoleRepositorySQLite) Read(ctx context.Context, name string) (entity.Role, error) {
+	var role entity.Role
+	row := r.db.QueryRowContext(ctx, `
+		SELECT name, description, created_at, updated_at
+		FROM roles WHERE name = $1`, name)
+	err := row.Scan(&role.Name, &role.Description, &role.CreatedAt, &role.UpdatedAt)
+	if err != nil {
+		return entity.Role{}, sqliteErr(ctx, roleEntity, err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, "SELECT permission FROM role_permissions WHERE role = $1 ORDER BY permission", name)
+	if err != nil {
+		return entity.Role{}, sqliteErr(ctx, roleEntity, err)
+	}
+	defer rows.Close()
+
+	role.Permissions = make([]string, 0)
+	for rows.Next() {
+		var perm string
+		if err := rows.Scan(&perm); err != nil {
+			return entity.Role{}, sqliteErr(ctx, roleEntity, err)
+		}
+		role.Permissions = append(role.Permissions, perm)
+	}
+	if err := rows.Err(); err != nil {
+		return entity.Role{}, sqliteErr(ctx, roleEntity, err)
+	}
+	return role, nil
+}
+
+func (r RoleRepositorySQLite) ReadAll(ctx context.Context) ([]entity.Role, error) {
+	rows, err := r.db.QueryContext(ctx, `
+	SELECT r.name, r.description, r.created_at, r.updated_at, p.permission
+	FROM roles r
+	LEFT JOIN role_permissions p ON p.role = r.name
+	ORDER BY r.name, p.permission
+	`)
+	if err != nil {
+		return nil, sqliteErr(ctx, roleEntity, err)
+	}
+	defer rows.Close()
+
+	roles := make([]entity.Role, 0)
+	for rows.Next() {
+		var (
+			role entity.Role
+			perm sql.NullString
+		)
+		err := rows.Scan(&role.Name, &role.Description, &role.CreatedAt, &role.UpdatedAt, &perm)
+		if err != nil {
+			return nil, sqliteErr(ctx, roleEntity, err)
+		}
+		if last := len(roles) - 1; last < 0 || roles[last].Name != role.Name {
+			role.Permissions = make([]string, 0)
+			roles = append(roles, role)
+		}
+		if perm.Valid {
+			last := len(roles) - 1
+			roles[last].Permissions = append(roles[last].Permissions, perm.String)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, sqliteErr(ctx, roleEntity, err)
+	}
+
+	return roles, nil
+}
+
+// Update replaces the description and the permissions of the role.
+func (r RoleRepositorySQLite) Update(ctx context.Context, role entity.Role) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return sqliteErr(ctx, roleEntity, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, "UPDATE roles SET description = $1, updated_at = $2 WHERE name = $3", role.Description, time.Now().UTC(), role.Name)
+	if err != nil {
+		return sqliteErr(ctx, roleEntity, err)
+	}
+	if err := sqliteRowsAffected(ctx, roleEntity, res); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM role_permissions WHERE role = $1", role.Name); err != nil {
+		return sqliteErr(ctx, roleEntity, err)
+	}
+	if err := insertPermissions(ctx, tx, role.Name, role.Permissions); err != nil {
+		return sqliteErr(ctx, roleEntity, err)
+	}
+	return sqliteErr(ctx, roleEntity, tx.Commit())
+}
+
+func (r RoleRepositorySQLite) Delete(ctx context.Context, name string) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM roles WHERE name = $1", name)
+	if err != nil {
+		return sqliteErr(ctx, roleEntity, err)
+	}
+	return sqliteRowsAffected(ctx, roleEntity, res)
+}
+
+// HasPermission reports whether the role has been granted the given permission.
+func (r RoleRepositorySQLite) HasPermission(ctx context.Context, role, permission string) (bool, error) {
+	var granted bool
+	row := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM role_permissions WHERE role = $1 AND permission = $2
+		)`, role, permission)
+	if err := row.Scan(&granted); err != nil {
+		return false, sqliteErr(ctx, roleEntity, err)
+	}
+	return granted, nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+)
+
+// TokenRepositoryMemory is a thread-safe in-memory storage of refresh tokens.
+// It is intended for local development and testing purposes.
+type TokenRepositoryMemory struct {
+	mu     *sync.RWMutex
+	lastID *uint64
+	tokens map[uint64]entity.RefreshToken
+}
+
+func NewTokenRepositoryMemory() TokenRepositoryMemory {
+	return TokenRepositoryMemory{
+		mu:     &sync.RWMutex{},
+		lastID: new(uint64),
+		tokens: make(map[uint64]entity.RefreshToken),
+	}
+}
+
+func (r TokenRepositoryMemory) Create(ctx context.Context, token entity.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	*r.lastID++
+	token.ID = *r.lastID
+	token.RevokedAt = sql.NullTime{}
+	token.CreatedAt = time.Now()
+	r.tokens[token.ID] = token
+	return nil
+}
+
+func (r TokenRepositoryMemory) ReadByHash(ctx context.Context, hash string) (entity.RefreshToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, token := range r.tokens {
+		if token.TokenHash == hash {
+			return token, nil
+		}
+	}
+	return entity.RefreshToken{}, &NotFoundErr{Entity: tokenEntity}
+}
+
+func (r TokenRepositoryMemory) Revoke(ctx context.Context, id uint64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.tokens[id]
+	if !ok || token.RevokedAt.Valid {
+		return false, nil
+	}
+	token.RevokedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	r.tokens[id] = token
+	return true, nil
+}
+
+func (r TokenRepositoryMemory) RevokeFamily(ctx context.Context, familyID string) error {
+	r.revokeWhere(func(token entity.RefreshToken) bool {
+		return token.FamilyID == familyID
+	})
+	return nil
+}
+
+func (r TokenRepositoryMemory) RevokeAll(ctx context.Context, userID uint64) error {
+	r.revokeWhere(func(token entity.RefreshToken) bool {
+		return token.UserID == userID
+	})
+	return nil
+}
+
+func (r TokenRepositoryMemory) revokeWhere(match func(token entity.RefreshToken) bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for id, token := range r.tokens {
+		if token.RevokedAt.Valid || !match(token) {
+			continue
+		}
+		token.RevokedAt = sql.NullTime{Time: now, Valid: true}
+		r.tokens[id] = token
+	}
+}
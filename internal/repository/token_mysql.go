@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+)
+
+type TokenRepositoryMySQL struct {
+	db *sql.DB
+}
+
+func NewTokenRepositoryMySQL(db *sql.DB) TokenRepositoryMySQL {
+	return TokenRepositoryMySQL{
+		db: db,
+	}
+}
+
+func (r TokenRepositoryMySQL) Create(ctx context.Context, token entity.RefreshToken) error {
+	_, err := r.db.ExecContext(ctx, "INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at) VALUES (?, ?, ?, ?)",
+		token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt,
+	)
+	return mysqlErr(ctx, tokenEntity, err)
+}
+
+func (r TokenRepositoryMySQL) ReadByHash(ctx context.Context, hash string) (entity.RefreshToken, error) {
+	var token entity.RefreshToken
+	row := r.db.QueryRowContext(ctx, `
+		SELECT id, user_id, family_id, token_hash, expires_at, revoked_at, created_at
+		FROM refresh_tokens WHERE token_hash = ?`, hash)
+	err := row.Scan(
+		&token.ID, &token.UserID, &token.FamilyID, &token.TokenHash, &token.ExpiresAt, &token.RevokedAt, &token.CreatedAt,
+	)
+	if err != nil {
+		return entity.RefreshToken{}, mysqlErr(ctx, tokenEntity, err)
+	}
+	return token, nil
+}
+
+func (r TokenRepositoryMySQL) Revoke(ctx context.Context, id uint64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, "UPDATE refresh_tokens SET revoked_at = NOW(6) WHERE id = ? AND revoked_at IS NULL", id)
+	if err != nil {
+		return false, mysqlErr(ctx, tokenEntity, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, mysqlErr(ctx, tokenEntity, err)
+	}
+	return n == 1, nil
+}
+
+func (r TokenRepositoryMySQL) RevokeFamily(ctx context.Context, familyID string) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE refresh_tokens SET revoked_at = NOW(6) WHERE family_id = ? AND revoked_at IS NULL", familyID)
+	return mysqlErr(ctx, tokenEntity, err)
+}
+
+func (r TokenRepositoryMySQL) RevokeAll(ctx context.Context, userID uint64) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE refresh_tokens SET revoked_at = NOW(6) WHERE user_id = ? AND revoked_at IS NULL", userID)
+	return mysqlErr(ctx, tokenEntity, err)
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+)
+
+type TokenRepositoryPg struct {
+	db *sql.DB
+}
+
+func NewTokenRepositoryPg(db *sql.DB) TokenRepositoryPg {
+	return TokenRepositoryPg{
+		db: db,
+	}
+}
+
+func (r TokenRepositoryPg) Create(ctx context.Context, token entity.RefreshToken) error {
+	_, err := r.db.ExecContext(ctx, "INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at) VALUES ($1, $2, $3, $4)",
+		token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt,
+	)
+	return pgErr(ctx, tokenEntity, err)
+}
+
+func (r TokenRepositoryPg) ReadByHash(ctx context.Context, hash string) (entity.RefreshToken, error) {
+	var token entity.RefreshToken
+	row := r.db.QueryRowContext(ctx, `
+		SELECT id, user_id, family_id, token_hash, expires_at, revoked_at, created_at
+		FROM refresh_tokens WHERE token_hash = $1`, hash)
+	err := row.Scan(
+		&token.ID, &token.UserID, &token.FamilyID, &token.TokenHash, &token.ExpiresAt, &token.RevokedAt, &token.CreatedAt,
+	)
+	if err != nil {
+		return entity.RefreshToken{}, pgErr(ctx, tokenEntity, err)
+	}
+	return token, nil
+}
+
+func (r TokenRepositoryPg) Revoke(ctx context.Context, id uint64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, "UPDATE refresh_tokens SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL", id)
+	if err != nil {
+		return false, pgErr(ctx, tokenEntity, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, pgErr(ctx, tokenEntity, err)
+	}
+	return n == 1, nil
+}
+
+func (r TokenRepositoryPg) RevokeFamily(ctx context.Context, familyID string) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL", familyID)
+	return pgErr(ctx, tokenEntity, err)
+}
+
+func (r TokenRepositoryPg) RevokeAll(ctx context.Context, userID uint64) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL", userID)
+	return pgErr(ctx, tokenEntity, err)
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+)
+
+type TokenRepositorySQLite struct {
+	db *sql.DB
+}
+
+func NewTokenRepositorySQLite(db *sql.DB) TokenRepositorySQLite {
+	return TokenRepositorySQLite{
+		db: db,
+	}
+}
+
+func (r TokenRepositorySQLite) Create(ctx context.Context, token entity.RefreshToken) error {
+	_, err := r.db.ExecContext(ctx, "INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at, created_at) VALUES ($1, $2, $3, $4, $5)",
+		token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt.UTC(), time.Now().UTC(),
+	)
+	return sqliteErr(ctx, tokenEntity, err)
+}
+
+func (r TokenRepositorySQLite) ReadByHash(ctx context.Context, hash string) (entity.RefreshToken, error) {
+	var token entity.RefreshToken
+	row := r.db.QueryRowContext(ctx, `
+		SELECT id, user_id, family_id, token_hash, expires_at, revoked_at, created_at
+		FROM refresh_tokens WHERE token_hash = $1`, hash)
+	err := row.Scan(
+		&token.ID, &token.UserID, &token.FamilyID, &token.TokenHash, &token.ExpiresAt, &token.RevokedAt, &token.CreatedAt,
+	)
+	if err != nil {
+		return entity.RefreshToken{}, sqliteErr(ctx, tokenEntity, err)
+	}
+	return token, nil
+}
+
+func (r TokenRepositorySQLite) Revoke(ctx context.Context, id uint64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, "UPDATE refresh_tokens SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL", time.Now().UTC(), id)
+	if err != nil {
+		return false, sqliteErr(ctx, tokenEntity, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, sqliteErr(ctx, tokenEntity, err)
+	}
+	return n == 1, nil
+}
+
+func (r TokenRepositorySQLite) RevokeFamily(ctx context.Context, familyID string) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE refresh_tokens SET revoked_at = $1 WHERE family_id = $2 AND revoked_at IS NULL", time.Now().UTC(), familyID)
+	return sqliteErr(ctx, tokenEntity, err)
+}
+
+func (r TokenRepositorySQLite) RevokeAll(ctx context.Context, userID uint64) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE refresh_tokens SET revoked_at = $1 WHERE user_id = $2 AND revoked_at IS NULL", time.Now().UTC(), userID)
+	return sqliteErr(ctx, tokenEntity, err)
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenRepositorySQLite(t *testing.T) {
+	ctx := context.Background()
+	sqlDB := newSQLiteDB(t)
+	users := NewUserRepositorySQLite(sqlDB)
+	repo := NewTokenRepositorySQLite(sqlDB)
+	require.NoError(t, users.Create(ctx, entity.User{Email: "jdoe@camgo.dev", Username: "jdoe", Role: entity.RoleUser}))
+	require.NoError(t, users.Create(ctx, entity.User{Email: "jroe@camgo.dev", Username: "jroe", Role: entity.RoleUser}))
+	expiresAt := time.Now().Add(time.Hour)
+
+	require.NoError(t, repo.Create(ctx, entity.RefreshToken{UserID: 1, FamilyID: "a", TokenHash: "a1", ExpiresAt: expiresAt}))
+	require.NoError(t, repo.Create(ctx, entity.RefreshToken{UserID: 1, FamilyID: "a", TokenHash: "a2", ExpiresAt: expiresAt}))
+	require.NoError(t, repo.Create(ctx, entity.RefreshToken{UserID: 1, FamilyID: "b", TokenHash: "b1", ExpiresAt: expiresAt}))
+	require.NoError(t, repo.Create(ctx, entity.RefreshToken{UserID: 2, FamilyID: "c", TokenHash: "c1", ExpiresAt: expiresAt}))
+	assert.ErrorAs(t, repo.Create(ctx, entity.RefreshToken{UserID: 2, FamilyID: "c", TokenHash: "c1", ExpiresAt: expiresAt}), new(*ConflictErr))
+	assert.ErrorIs(t, repo.Create(ctx, entity.RefreshToken{UserID: 3, FamilyID: "d", TokenHash: "d1", ExpiresAt: expiresAt}), ErrReferenceViolated)
+
+	_, err := repo.ReadByHash(ctx, "unknown")
+	assert.ErrorAs(t, err, new(*NotFoundErr))
+
+	token, err := repo.ReadByHash(ctx, "a1")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), token.ID)
+	assert.True(t, expiresAt.Equal(token.ExpiresAt))
+	assert.False(t, token.CreatedAt.IsZero())
+
+	revoked, err := repo.Revoke(ctx, token.ID)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+	revoked, err = repo.Revoke(ctx, token.ID)
+	require.NoError(t, err)
+	assert.False(t, revoked, "a token can only be revoked once")
+
+	require.NoError(t, repo.RevokeFamily(ctx, "a"))
+	token, err = repo.ReadByHash(ctx, "a2")
+	require.NoError(t, err)
+	assert.True(t, token.RevokedAt.Valid)
+	token, err = repo.ReadByHash(ctx, "b1")
+	require.NoError(t, err)
+	assert.False(t, token.RevokedAt.Valid)
+
+	require.NoError(t, repo.RevokeAll(ctx, 1))
+	token, err = repo.ReadByHash(ctx, "b1")
+	require.NoError(t, err)
+	assert.True(t, token.RevokedAt.Valid)
+	token, err = repo.ReadByHash(ctx, "c1")
+	require.NoError(t, err)
+	assert.False(t, token.RevokedAt.Valid)
+
+	require.NoError(t, users.Delete(ctx, 2))
+	_, err = repo.ReadByHash(ctx, "c1")
+	assert.ErrorAs(t, err, new(*NotFoundErr), "the tokens are deleted with their user")
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// dbSystemPostgres identifies PostgreSQL in the db.system span attribute.
+const dbSystemPostgres = "postgresql"
+
+// tracedDB runs the statements of a repository, each one within a client span child of the span carried by the context.
+// The span of a query ends once the database answers, the time spent scanning the rows is left to the caller span.
+type tracedDB struct {
+	db     *sql.DB
+	system string
+	tracer trace.Tracer
+}
+
+func newTracedDB(db *sql.DB, system string) tracedDB {
+	return tracedDB{
+		db:     db,
+		system: system,
+		tracer: otel.Tracer("github.com/wizeline/CA-Microservices-Go/internal/repository"),
+	}
+}
+
+func (t tracedDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := t.start(ctx, query)
+	defer span.End()
+	res, err := t.db.ExecContext(ctx, query, args...)
+	recordErr(span, err)
+	return res, err
+}
+
+func (t tracedDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	ctx, span := t.start(ctx, query)
+	defer span.End()
+	rows, err := t.db.QueryContext(ctx, query, args...)
+	recordErr(span, err)
+	return rows, err
+}
+
+func (t tracedDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, span := t.start(ctx, query)
+	defer span.End()
+	row := t.db.QueryRowContext(ctx, query, args...)
+	// The rows not found are only known when scanning, row.Err reports the failures of the query.
+	recordErr(span, row.Err())
+	return row
+}
+
+// start starts the span of the statement, it is named after the SQL operation. e.g. "SELECT"
+// The statement is recorded as is, the values are bound through placeholders so they are never part of it.
+func (t tracedDB) start(ctx context.Context, query string) (context.Context, trace.Span) {
+	operation := "SQL"
+	if fields := strings.Fields(query); len(fields) > 0 {
+		operation = strings.ToUpper(fields[0])
+	}
+	return t.tracer.Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", t.system),
+			attribute.String("db.operation.name", operation),
+			attribute.String("db.query.text", strings.Join(strings.Fields(query), " ")),
+		),
+	)
+}
+
+// recordErr records the error the statement failed with.
+func recordErr(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
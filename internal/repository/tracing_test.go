@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracedDB(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	db := newTracedDB(newSQLiteDB(t), "sqlite")
+	db.tracer = tp.Tracer("test")
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "UserService.Get")
+	_, err := db.ExecContext(ctx, "DELETE FROM users WHERE id = $1", 1)
+	require.NoError(t, err)
+	var total int
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT COUNT(*)\n\t\tFROM users").Scan(&total))
+	_, err = db.QueryContext(ctx, "SELECT unknown FROM users")
+	require.Error(t, err)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 4)
+	tests := []struct {
+		name      string
+		statement string
+		failed    bool
+	}{
+		{name: "DELETE", statement: "DELETE FROM users WHERE id = $1"},
+		{name: "SELECT", statement: "SELECT COUNT(*) FROM users"},
+		{name: "SELECT", statement: "SELECT unknown FROM users", failed: true},
+	}
+	for i, tt := range tests {
+		span := spans[i]
+		assert.Equal(t, tt.name, span.Name())
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID(), "the statements are children of the caller span")
+		assert.Contains(t, span.Attributes(), attribute.String("db.system", "sqlite"))
+		assert.Contains(t, span.Attributes(), attribute.String("db.query.text", tt.statement), "the statement is recorded on a single line")
+		if tt.failed {
+			assert.Equal(t, codes.Error, span.Status().Code)
+		} else {
+			assert.Equal(t, codes.Unset, span.Status().Code)
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+)
+
+var ErrValueMismatch = errors.New("value type mismatch")
+
+// UserRepositoryMemory is a thread-safe in-memory storage of users following the UserRepositoryPg semantics:
+// the IDs are auto-incremented, the emails and usernames are unique and the same error types are returned.
+// It is intended for local development and testing purposes, the role references are not checked.
+type UserRepositoryMemory struct {
+	mu     *sync.RWMutex
+	lastID *uint64
+	users  map[uint64]entity.User
+}
+
+func NewUserRepositoryMemory() UserRepositoryMemory {
+	return UserRepositoryMemory{
+		mu:     &sync.RWMutex{},
+		lastID: new(uint64),
+		users:  make(map[uint64]entity.User),
+	}
+}
+
+func (r UserRepositoryMemory) Create(ctx context.Context, user entity.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.unique(user); err != nil {
+		return err
+	}
+	*r.lastID++
+	user.ID = *r.lastID
+	user.Active = false
+	user.LastLogin = sql.NullTime{}
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = sql.NullTime{}
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r UserRepositoryMemory) Read(ctx context.Context, id uint64) (entity.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return entity.User{}, &NotFoundErr{Entity: userEntity}
+	}
+	return user, nil
+}
+
+func (r UserRepositoryMemory) ReadAll(ctx context.Context) ([]entity.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]entity.User, 0, len(r.users))
+	for _, user := range r.users {
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users, nil
+}
+
+// Search returns the page of users matching the query filters, and the total of users matched.
+func (r UserRepositoryMemory) Search(ctx context.Context, q entity.UserQuery) (entity.UserPage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]entity.User, 0)
+	for _, user := range r.users {
+		ok, err := matchUser(user, q.Filters)
+		if err != nil {
+			return entity.UserPage{}, err
+		}
+		if ok {
+			users = append(users, user)
+		}
+	}
+	total := len(users)
+
+	sorts := userSortKeys(q.Sort)
+	for _, srt := range sorts {
+		if _, ok := userColumns[srt.Field]; !ok {
+			return entity.UserPage{}, &InvalidFieldErr{Name: srt.Field, Err: ErrFieldNotSupported}
+		}
+	}
+	// A backward page is read in the reverse order and flipped back once sliced, the same way as the SQL queries.
+	backward := q.Cursor != nil && q.Cursor.Backward
+	var sortErr error
+	sort.SliceStable(users, func(i, j int) bool {
+		c, err := compareUsers(users[i], sorts, keysetValues(users[j], sorts), backward)
+		if err != nil {
+			sortErr = err
+		}
+		return c < 0
+	})
+	if sortErr != nil {
+		return entity.UserPage{}, sortErr
+	}
+
+	if q.Cursor != nil {
+		if len(q.Cursor.Values) != len(sorts) {
+			return entity.UserPage{}, &InvalidFieldErr{Name: "cursor", Err: fmt.Errorf("expected %d values got %d", len(sorts), len(q.Cursor.Values))}
+		}
+		after := make([]entity.User, 0, len(users))
+		for _, user := range users {
+			c, err := compareUsers(user, sorts, q.Cursor.Values, backward)
+			if err != nil {
+				return entity.UserPage{}, err
+			}
+			if c > 0 {
+				after = append(after, user)
+			}
+		}
+		users = after
+	}
+
+	users = users[min(q.Offset, len(users)):]
+	if q.Limit > 0 && q.Limit < len(users) {
+		users = users[:q.Limit]
+	}
+	if backward {
+		for i, j := 0, len(users)-1; i < j; i, j = i+1, j-1 {
+			users[i], users[j] = users[j], users[i]
+		}
+	}
+	return entity.UserPage{Users: users, Total: total}, nil
+}
+
+func (r UserRepositoryMemory) Update(ctx context.Context, user entity.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[user.ID]
+	if !ok {
+		return &NotFoundErr{Entity: userEntity}
+	}
+	if err := r.unique(user); err != nil {
+		return err
+	}
+	user.CreatedAt = stored.CreatedAt
+	user.UpdatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r UserRepositoryMemory) Delete(ctx context.Context, id uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[id]; !ok {
+		return &NotFoundErr{Entity: userEntity}
+	}
+	delete(r.users, id)
+	return nil
+}
+
+// unique returns a ConflictErr when another user holds the email or the username of the user.
+// The caller must hold the lock.
+func (r UserRepositoryMemory) unique(user entity.User) error {
+	for _, stored := range r.users {
+		if stored.ID == user.ID {
+			continue
+		}
+		if stored.Email == user.Email {
+			return &ConflictErr{Entity: userEntity, Field: "email", Constraint: "users_email_key", Err: ErrDuplicated}
+		}
+		if stored.Username == user.Username {
+			return &ConflictErr{Entity: userEntity, Field: "username", Constraint: "users_username_key", Err: ErrDuplicated}
+		}
+	}
+	return nil
+}
+
+// matchUser reports whether the user matches every filter.
+func matchUser(user entity.User, filters []entity.Filter) (bool, error) {
+	for _, f := range filters {
+		if _, ok := userColumns[f.Field]; !ok {
+			return false, &InvalidFieldErr{Name: f.Field, Err: ErrFieldNotSupported}
+		}
+		value := userFieldValue(user, f.Field)
+
+		var match bool
+		switch f.Op {
+		case entity.FilterPrefix:
+			match = strings.HasPrefix(fmt.Sprint(value), fmt.Sprint(f.Value))
+		case entity.FilterContains:
+			match = strings.Contains(fmt.Sprint(value), fmt.Sprint(f.Value))
+		case entity.FilterEq, entity.FilterGte, entity.FilterLte:
+			c, err := compareValue(value, f.Value)
+			if err != nil {
+				return false, &InvalidFieldErr{Name: f.Field, Err: err}
+			}
+			match = (f.Op == entity.FilterEq && c == 0) ||
+				(f.Op == entity.FilterGte && c >= 0) ||
+				(f.Op == entity.FilterLte && c <= 0)
+		default:
+			return false, &InvalidFieldErr{Name: fmt.Sprintf("%s[%s]", f.Field, f.Op), Err: ErrFieldNotSupported}
+		}
+		if !match {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// compareUsers compares the sort fields of the user to the keyset values, in the order of the sort fields.
+// The comparison is inverted on the descending fields, and on every field when reading backward.
+func compareUsers(user entity.User, sorts []entity.Sort, values []any, backward bool) (int, error) {
+	for i, srt := range sorts {
+		c, err := compareValue(userFieldValue(user, srt.Field), values[i])
+		if err != nil {
+			return 0, &InvalidFieldErr{Name: srt.Field, Err: err}
+		}
+		if srt.Desc != backward {
+			c = -c
+		}
+		if c != 0 {
+			return c, nil
+		}
+	}
+	return 0, nil
+}
+
+// keysetValues returns the values of the sort fields of the user.
+func keysetValues(user entity.User, sorts []entity.Sort) []any {
+	values := make([]any, len(sorts))
+	for i, srt := range sorts {
+		values[i] = userFieldValue(user, srt.Field)
+	}
+	return values
+}
+
+// userFieldValue returns the value of the searchable user field.
+func userFieldValue(user entity.User, field string) any {
+	switch field {
+	case entity.UserFieldID:
+		return user.ID
+	case entity.UserFieldFirstName:
+		return user.FirstName
+	case entity.UserFieldLastName:
+		return user.LastName
+	case entity.UserFieldEmail:
+		return user.Email
+	case entity.UserFieldUsername:
+		return user.Username
+	case entity.UserFieldRole:
+		return user.Role
+	case entity.UserFieldActive:
+		return user.Active
+	case entity.UserFieldBirthDay:
+		return user.BirthDay
+	case entity.UserFieldCreatedAt:
+		return user.CreatedAt
+	}
+	return nil
+}
+
+// compareValue compares the field value to the filter or cursor value, the IDs may be given as strings.
+func compareValue(value, other any) (int, error) {
+	switch v := value.(type) {
+	case uint64:
+		switch o := other.(type) {
+		case uint64:
+			return cmp.Compare(v, o), nil
+		case string:
+			id, err := strconv.ParseUint(o, 10, 64)
+			if err != nil {
+				return 0, ErrValueMismatch
+			}
+			return cmp.Compare(v, id), nil
+		}
+	case string:
+		if o, ok := other.(string); ok {
+			return strings.Compare(v, o), nil
+		}
+	case bool:
+		if o, ok := other.(bool); ok {
+			switch {
+			case v == o:
+				return 0, nil
+			case o:
+				return -1, nil
+			default:
+				return 1, nil
+			}
+		}
+	case time.Time:
+		if o, ok := other.(time.Time); ok {
+			return v.Compare(o), nil
+		}
+	}
+	return 0, ErrValueMismatch
+}
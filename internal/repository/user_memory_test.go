@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepositoryMemory(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepositoryMemory()
+
+	require.NoError(t, repo.Create(ctx, entity.User{Email: "jdoe@camgo.dev", Username: "jdoe", Role: entity.RoleUser, Active: true}))
+	require.NoError(t, repo.Create(ctx, entity.User{Email: "jroe@camgo.dev", Username: "jroe", Role: entity.RoleUser}))
+
+	err := repo.Create(ctx, entity.User{Email: "jdoe@camgo.dev", Username: "other"})
+	assert.EqualError(t, err, "user conflict on field email: already exists")
+	err = repo.Create(ctx, entity.User{Email: "other@camgo.dev", Username: "jroe"})
+	assert.ErrorAs(t, err, new(*ConflictErr))
+
+	user, err := repo.Read(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "jdoe", user.Username)
+	assert.False(t, user.Active, "the users are created inactive")
+	assert.False(t, user.CreatedAt.IsZero())
+	assert.False(t, user.UpdatedAt.Valid)
+
+	_, err = repo.Read(ctx, 3)
+	assert.ErrorAs(t, err, new(*NotFoundErr))
+
+	user.Email = "jroe@camgo.dev"
+	assert.ErrorAs(t, repo.Update(ctx, user), new(*ConflictErr))
+	user.Email = "john.doe@camgo.dev"
+	require.NoError(t, repo.Update(ctx, user))
+	user, err = repo.Read(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "john.doe@camgo.dev", user.Email)
+	assert.True(t, user.UpdatedAt.Valid)
+	assert.ErrorAs(t, repo.Update(ctx, entity.User{ID: 3}), new(*NotFoundErr))
+
+	require.NoError(t, repo.Delete(ctx, 1))
+	assert.ErrorAs(t, repo.Delete(ctx, 1), new(*NotFoundErr))
+	require.NoError(t, repo.Create(ctx, entity.User{Email: "jdoe@camgo.dev", Username: "jdoe"}))
+
+	users, err := repo.ReadAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	assert.Equal(t, uint64(2), users[0].ID)
+	assert.Equal(t, uint64(3), users[1].ID, "the IDs are not reused")
+}
+
+func TestUserRepositoryMemory_Search(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepositoryMemory()
+	for _, u := range []entity.User{
+		{FirstName: "John", LastName: "Doe", Email: "jdoe@camgo.dev", Username: "jdoe", BirthDay: time.Date(1990, 4, 21, 0, 0, 0, 0, time.UTC)},
+		{FirstName: "Jane", LastName: "Roe", Email: "jroe@camgo.dev", Username: "jroe", BirthDay: time.Date(1993, 8, 2, 0, 0, 0, 0, time.UTC)},
+		{FirstName: "Ada", LastName: "Doe", Email: "ada@camgo.dev", Username: "ada", BirthDay: time.Date(1985, 12, 10, 0, 0, 0, 0, time.UTC)},
+		{FirstName: "Bob", LastName: "Smith", Email: "bob@camgo.dev", Username: "bob", BirthDay: time.Date(1990, 4, 21, 0, 0, 0, 0, time.UTC)},
+	} {
+		require.NoError(t, repo.Create(ctx, u))
+	}
+
+	tests := []struct {
+		name      string
+		query     entity.UserQuery
+		usernames []string
+		total     int
+		err       bool
+	}{
+		{
+			name:      "Every user sorted by ID",
+			query:     entity.UserQuery{},
+			usernames: []string{"jdoe", "jroe", "ada", "bob"},
+			total:     4,
+		},
+		{
+			name: "Filters",
+			query: entity.UserQuery{Filters: []entity.Filter{
+				{Field: entity.UserFieldLastName, Op: entity.FilterEq, Value: "Doe"},
+				{Field: entity.UserFieldBirthDay, Op: entity.FilterGte, Value: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)},
+			}},
+			usernames: []string{"jdoe"},
+			total:     1,
+		},
+		{
+			name: "Prefix and contains",
+			query: entity.UserQuery{Filters: []entity.Filter{
+				{Field: entity.UserFieldFirstName, Op: entity.FilterPrefix, Value: "J"},
+				{Field: entity.UserFieldEmail, Op: entity.FilterContains, Value: "roe"},
+			}},
+			usernames: []string{"jroe"},
+			total:     1,
+		},
+		{
+			name:      "ID given as string",
+			query:     entity.UserQuery{Filters: []entity.Filter{{Field: entity.UserFieldID, Op: entity.FilterEq, Value: "3"}}},
+			usernames: []string{"ada"},
+			total:     1,
+		},
+		{
+			name: "Sorted with the ID as tiebreaker, limited and offset",
+			query: entity.UserQuery{
+				Sort:   []entity.Sort{{Field: entity.UserFieldBirthDay, Desc: true}},
+				Limit:  2,
+				Offset: 1,
+			},
+			usernames: []string{"jdoe", "bob"},
+			total:     4,
+		},
+		{
+			name: "Forward cursor",
+			query: entity.UserQuery{
+				Sort:   []entity.Sort{{Field: entity.UserFieldBirthDay}},
+				Limit:  2,
+				Cursor: &entity.Cursor{Values: []any{time.Date(1990, 4, 21, 0, 0, 0, 0, time.UTC), uint64(1)}},
+			},
+			usernames: []string{"bob", "jroe"},
+			total:     4,
+		},
+		{
+			name: "Backward cursor",
+			query: entity.UserQuery{
+				Sort:   []entity.Sort{{Field: entity.UserFieldBirthDay}},
+				Limit:  2,
+				Cursor: &entity.Cursor{Values: []any{time.Date(1990, 4, 21, 0, 0, 0, 0, time.UTC), uint64(4)}, Backward: true},
+			},
+			usernames: []string{"ada", "jdoe"},
+			total:     4,
+		},
+		{
+			name:  "Unsupported field",
+			query: entity.UserQuery{Filters: []entity.Filter{{Field: "passwd", Op: entity.FilterEq, Value: "secret"}}},
+			err:   true,
+		},
+		{
+			name: "Cursor values mismatch",
+			query: entity.UserQuery{
+				Sort:   []entity.Sort{{Field: entity.UserFieldBirthDay}},
+				Cursor: &entity.Cursor{Values: []any{uint64(1)}},
+			},
+			err: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := repo.Search(ctx, tt.query)
+			if tt.err {
+				assert.ErrorAs(t, err, new(*InvalidFieldErr))
+				return
+			}
+			require.NoError(t, err)
+
+			usernames := make([]string, 0, len(page.Users))
+			for _, u := range page.Users {
+				usernames = append(usernames, u.Username)
+			}
+			assert.Equal(t, tt.usernames, usernames)
+			assert.Equal(t, tt.total, page.Total)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+)
+
+// UserRepositoryMySQL stores the users in a MySQL or MariaDB database.
+type UserRepositoryMySQL struct {
+	db *sql.DB
+}
+
+func NewUserRepositoryMySQL(db *sql.DB) UserRepositoryMySQL {
+	return UserRepositoryMySQL{
+		db: db,
+	}
+}
+
+func (r UserRepositoryMySQL) Create(ctx context.Context, user entity.User) error {
+	_, err := r.db.ExecContext(ctx, "INSERT INTO users (first_name, last_name, birthday, email, username, passwd, role) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		user.FirstName, user.LastName, user.BirthDay, user.Email, user.Username, user.Passwd, user.Role,
+	)
+	if err != nil {
+		return mysqlErr(ctx, userEntity, err)
+	}
+
+	return nil
+}
+
+func (r UserRepositoryMySQL) Read(ctx context.Context, id uint64) (entity.User, error) {
+	var user entity.User
+	row := r.db.QueryRowContext(ctx, `
+		SELECT id, first_name, last_name, email, birthday,
+			username, passwd, role, active, last_login,
+			created_at, updated_at
+		FROM users WHERE id = ?`, id)
+	err := row.Scan(
+		&user.ID, &user.FirstName, &user.LastName, &user.Email, &user.BirthDay,
+		&user.Username, &user.Passwd, &user.Role, &user.Active, &user.LastLogin,
+		&user.CreatedAt, &user.UpdatedAt,
+	)
+	if err != nil {
+		return entity.User{}, mysqlErr(ctx, userEntity, err)
+	}
+	return user, nil
+}
+
+func (r UserRepositoryMySQL) ReadAll(ctx context.Context) ([]entity.User, error) {
+	rows, err := r.db.QueryContext(ctx, `
+	SELECT id, first_name, last_name, email, birthday,
+		username, passwd, role, active, last_login,
+		created_at, updated_at
+	FROM users
+	`)
+	if err != nil {
+		return nil, mysqlErr(ctx, userEntity, err)
+	}
+	defer rows.Close()
+
+	users, err := scanUsers(rows)
+	if err != nil {
+		return nil, mysqlErr(ctx, userEntity, err)
+	}
+	return users, nil
+}
+
+// Search returns the page of users matching the query filters, and the total of users matched.
+func (r UserRepositoryMySQL) Search(ctx context.Context, q entity.UserQuery) (entity.UserPage, error) {
+	search := userSearch{positional: true}
+	where, err := search.where(q.Filters)
+	if err != nil {
+		return entity.UserPage{}, err
+	}
+
+	var total int
+	row := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users"+where, search.args...)
+	if err := row.Scan(&total); err != nil {
+		return entity.UserPage{}, mysqlErr(ctx, userEntity, err)
+	}
+
+	page, err := search.page(q, where != "")
+	if err != nil {
+		return entity.UserPage{}, err
+	}
+	rows, err := r.db.QueryContext(ctx, `
+	SELECT id, first_name, last_name, email, birthday,
+		username, passwd, role, active, last_login,
+		created_at, updated_at
+	FROM users`+where+page, search.args...)
+	if err != nil {
+		return entity.UserPage{}, mysqlErr(ctx, userEntity, err)
+	}
+	defer rows.Close()
+
+	users, err := scanUsers(rows)
+	if err != nil {
+		return entity.UserPage{}, mysqlErr(ctx, userEntity, err)
+	}
+	if q.Cursor != nil && q.Cursor.Backward {
+		for i, j := 0, len(users)-1; i < j; i, j = i+1, j-1 {
+			users[i], users[j] = users[j], users[i]
+		}
+	}
+	return entity.UserPage{Users: users, Total: total}, nil
+}
+
+func (r UserRepositoryMySQL) Update(ctx context.Context, user entity.User) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE users SET
+			first_name = ?,
+			last_name = ?,
+			email = ?,
+			birthday = ?,
+
+			username = ?,
+			passwd = ?,
+			role = ?,
+			active = ?,
+			last_login = ?,
+			updated_at = NOW(6)
+		WHERE
+			id = ?`,
+		user.FirstName, user.LastName, user.Email, user.BirthDay,
+		user.Username, user.Passwd, user.Role, user.Active, user.LastLogin,
+		user.ID,
+	)
+	if err != nil {
+		return mysqlErr(ctx, userEntity, err)
+	}
+	return mysqlRowsAffected(ctx, userEntity, res)
+}
+
+func (r UserRepositoryMySQL) Delete(ctx context.Context, id uint64) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM users WHERE id = ?", id)
+	if err != nil {
+		return mysqlErr(ctx, userEntity, err)
+	}
+	return mysqlRowsAffected(ctx, userEntity, res)
+}
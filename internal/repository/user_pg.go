@@ -1,84 +1,113 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/wizeline/CA-Microservices-Go/internal/entity"
 )
 
+// UserRepositoryPg stores the users in a PostgreSQL database, every statement is traced within its own span.
 type UserRepositoryPg struct {
-	db *sql.DB
+	db tracedDB
 }
 
 func NewUserRepositoryPg(db *sql.DB) UserRepositoryPg {
 	return UserRepositoryPg{
-		db: db,
+		db: newTracedDB(db, dbSystemPostgres),
 	}
 }
 
-func (r UserRepositoryPg) Create(user entity.User) error {
-	_, err := r.db.Exec("INSERT INTO users (first_name, last_name, birthday, email, username, passwd) VALUES ($1, $2, $3, $4, $5, $6)",
-		user.FirstName, user.LastName, user.BirthDay, user.Email, user.Username, user.Passwd,
+func (r UserRepositoryPg) Create(ctx context.Context, user entity.User) error {
+	_, err := r.db.ExecContext(ctx, "INSERT INTO users (first_name, last_name, birthday, email, username, passwd, role) VALUES ($1, $2, $3, $4, $5, $6, $7)",
+		user.FirstName, user.LastName, user.BirthDay, user.Email, user.Username, user.Passwd, user.Role,
 	)
 	if err != nil {
-		return err
+		return pgErr(ctx, userEntity, err)
 	}
 
 	return nil
 }
 
-func (r UserRepositoryPg) Read(id uint64) (entity.User, error) {
+func (r UserRepositoryPg) Read(ctx context.Context, id uint64) (entity.User, error) {
 	var user entity.User
-	row := r.db.QueryRow(`
+	row := r.db.QueryRowContext(ctx, `
 		SELECT id, first_name, last_name, email, birthday,
-			username, passwd, active, last_login,
+			username, passwd, role, active, last_login,
 			created_at, updated_at
 		FROM users WHERE id = $1`, id)
 	err := row.Scan(
 		&user.ID, &user.FirstName, &user.LastName, &user.Email, &user.BirthDay,
-		&user.Username, &user.Passwd, &user.Active, &user.LastLogin,
+		&user.Username, &user.Passwd, &user.Role, &user.Active, &user.LastLogin,
 		&user.CreatedAt, &user.UpdatedAt,
 	)
 	if err != nil {
-		return entity.User{}, err
+		return entity.User{}, pgErr(ctx, userEntity, err)
 	}
 	return user, nil
 }
 
-func (r UserRepositoryPg) ReadAll() ([]entity.User, error) {
-	rows, err := r.db.Query(`
+func (r UserRepositoryPg) ReadAll(ctx context.Context) ([]entity.User, error) {
+	rows, err := r.db.QueryContext(ctx, `
 	SELECT id, first_name, last_name, email, birthday, 
-		username, passwd, active, last_login,
+		username, passwd, role, active, last_login,
 		created_at, updated_at
 	FROM users 
 	`)
 	if err != nil {
-		return nil, err
+		return nil, pgErr(ctx, userEntity, err)
 	}
 	defer rows.Close()
 
-	users := make([]entity.User, 0)
-	for rows.Next() {
-		var user entity.User
-		err := rows.Scan(
-			&user.ID, &user.FirstName, &user.LastName, &user.Email, &user.BirthDay,
-			&user.Username, &user.Passwd, &user.Active, &user.LastLogin,
-			&user.CreatedAt, &user.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		users = append(users, user)
+	users, err := scanUsers(rows)
+	if err != nil {
+		return nil, pgErr(ctx, userEntity, err)
 	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+	return users, nil
+}
+
+// Search returns the page of users matching the query filters, and the total of users matched.
+func (r UserRepositoryPg) Search(ctx context.Context, q entity.UserQuery) (entity.UserPage, error) {
+	var search userSearch
+	where, err := search.where(q.Filters)
+	if err != nil {
+		return entity.UserPage{}, err
 	}
 
-	return users, nil
+	var total int
+	row := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users"+where, search.args...)
+	if err := row.Scan(&total); err != nil {
+		return entity.UserPage{}, pgErr(ctx, userEntity, err)
+	}
+
+	page, err := search.page(q, where != "")
+	if err != nil {
+		return entity.UserPage{}, err
+	}
+	rows, err := r.db.QueryContext(ctx, `
+	SELECT id, first_name, last_name, email, birthday,
+		username, passwd, role, active, last_login,
+		created_at, updated_at
+	FROM users`+where+page, search.args...)
+	if err != nil {
+		return entity.UserPage{}, pgErr(ctx, userEntity, err)
+	}
+	defer rows.Close()
+
+	users, err := scanUsers(rows)
+	if err != nil {
+		return entity.UserPage{}, pgErr(ctx, userEntity, err)
+	}
+	if q.Cursor != nil && q.Cursor.Backward {
+		for i, j := 0, len(users)-1; i < j; i, j = i+1, j-1 {
+			users[i], users[j] = users[j], users[i]
+		}
+	}
+	return entity.UserPage{Users: users, Total: total}, nil
 }
 
-func (r UserRepositoryPg) Update(user entity.User) error {
-	_, err := r.db.Exec(`
+func (r UserRepositoryPg) Update(ctx context.Context, user entity.User) error {
+	res, err := r.db.ExecContext(ctx, `
 		UPDATE users SET 
 			first_name = $1,
 			last_name = $2, 
@@ -87,19 +116,47 @@ func (r UserRepositoryPg) Update(user entity.User) error {
 
 			username = $5,
 			passwd = $6, 
-			active = $7,
-			last_login = $8,
+			role = $7,
+			active = $8,
+			last_login = $9,
 			updated_at = NOW()
 		WHERE 
-			id = $9`,
+			id = $10`,
 		user.FirstName, user.LastName, user.Email, user.BirthDay,
-		user.Username, user.Passwd, user.Active, user.LastLogin,
+		user.Username, user.Passwd, user.Role, user.Active, user.LastLogin,
 		user.ID,
 	)
-	return err
+	if err != nil {
+		return pgErr(ctx, userEntity, err)
+	}
+	return rowsAffected(ctx, userEntity, res)
 }
 
-func (r UserRepositoryPg) Delete(id uint64) error {
-	_, err := r.db.Exec("DELETE FROM users WHERE id = $1", id)
-	return err
+func (r UserRepositoryPg) Delete(ctx context.Context, id uint64) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM users WHERE id = $1", id)
+	if err != nil {
+		return pgErr(ctx, userEntity, err)
+	}
+	return rowsAffected(ctx, userEntity, res)
+}
+
+func scanUsers(rows *sql.Rows) ([]entity.User, error) {
+	users := make([]entity.User, 0)
+	for rows.Next() {
+		var user entity.User
+		err := rows.Scan(
+			&user.ID, &user.FirstName, &user.LastName, &user.Email, &user.BirthDay,
+			&user.Username, &user.Passwd, &user.Role, &user.Active, &user.LastLogin,
+			&user.CreatedAt, &user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
 }
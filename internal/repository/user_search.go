@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+)
+
+var ErrFieldNotSupported = errors.New("field not supported")
+
+// userColumns maps the searchable user fields to their column, only the fields listed here reach the SQL statements.
+var userColumns = map[string]string{
+	entity.UserFieldID:        "id",
+	entity.UserFieldFirstName: "first_name",
+	entity.UserFieldLastName:  "last_name",
+	entity.UserFieldEmail:     "email",
+	entity.UserFieldUsername:  "username",
+	entity.UserFieldRole:      "role",
+	entity.UserFieldActive:    "active",
+	entity.UserFieldBirthDay:  "birthday",
+	entity.UserFieldCreatedAt: "created_at",
+}
+
+// userSearch builds the parameterized clauses of a users search.
+type userSearch struct {
+	args []any
+	// positional binds the arguments to "?" placeholders instead of the numbered "$n" ones,
+	// a value used several times is then bound once per use.
+	positional bool
+	// likeEscape declares the escape character of the LIKE patterns, for the engines without a default one.
+	likeEscape string
+}
+
+// bind appends the value to the statement arguments and returns its placeholder.
+func (s *userSearch) bind(value any) string {
+	s.args = append(s.args, value)
+	if s.positional {
+		return "?"
+	}
+	return fmt.Sprintf("$%d", len(s.args))
+}
+
+// where returns the WHERE clause matching every filter, or an empty string when there are no filters.
+func (s *userSearch) where(filters []entity.Filter) (string, error) {
+	conds := make([]string, 0, len(filters))
+	for _, f := range filters {
+		col, ok := userColumns[f.Field]
+		if !ok {
+			return "", &InvalidFieldErr{Name: f.Field, Err: ErrFieldNotSupported}
+		}
+		switch f.Op {
+		case entity.FilterEq:
+			conds = append(conds, fmt.Sprintf("%s = %s", col, s.bind(f.Value)))
+		case entity.FilterPrefix:
+			conds = append(conds, fmt.Sprintf("%s LIKE %s%s", col, s.bind(escapeLike(f.Value)+"%"), s.likeEscape))
+		case entity.FilterContains:
+			conds = append(conds, fmt.Sprintf("%s LIKE %s%s", col, s.bind("%"+escapeLike(f.Value)+"%"), s.likeEscape))
+		case entity.FilterGte:
+			conds = append(conds, fmt.Sprintf("%s >= %s", col, s.bind(f.Value)))
+		case entity.FilterLte:
+			conds = append(conds, fmt.Sprintf("%s <= %s", col, s.bind(f.Value)))
+		default:
+			return "", &InvalidFieldErr{Name: fmt.Sprintf("%s[%s]", f.Field, f.Op), Err: ErrFieldNotSupported}
+		}
+	}
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), nil
+}
+
+// page returns the keyset condition, the ORDER BY and the LIMIT/OFFSET clauses of the query.
+// The ID is appended to the sort fields as tiebreaker so the order is total.
+// The keyset condition must be joined to the WHERE clause with AND, or starts it when there are no filters.
+func (s *userSearch) page(q entity.UserQuery, hasWhere bool) (string, error) {
+	sorts := userSortKeys(q.Sort)
+
+	var b strings.Builder
+	if q.Cursor != nil {
+		if len(q.Cursor.Values) != len(sorts) {
+			return "", &InvalidFieldErr{Name: "cursor", Err: fmt.Errorf("expected %d values got %d", len(sorts), len(q.Cursor.Values))}
+		}
+		keyset, err := s.keyset(sorts, q.Cursor)
+		if err != nil {
+			return "", err
+		}
+		if hasWhere {
+			b.WriteString(" AND ")
+		} else {
+			b.WriteString(" WHERE ")
+		}
+		b.WriteString(keyset)
+	}
+
+	order := make([]string, 0, len(sorts))
+	for _, srt := range sorts {
+		col, ok := userColumns[srt.Field]
+		if !ok {
+			return "", &InvalidFieldErr{Name: srt.Field, Err: ErrFieldNotSupported}
+		}
+		// A backward page is read in the reverse order and flipped back once scanned.
+		desc := srt.Desc
+		if q.Cursor != nil && q.Cursor.Backward {
+			desc = !desc
+		}
+		if desc {
+			col += " DESC"
+		}
+		order = append(order, col)
+	}
+	b.WriteString(" ORDER BY " + strings.Join(order, ", "))
+
+	if q.Limit > 0 {
+		b.WriteString(" LIMIT " + s.bind(q.Limit))
+	}
+	if q.Offset > 0 {
+		b.WriteString(" OFFSET " + s.bind(q.Offset))
+	}
+	return b.String(), nil
+}
+
+// keyset returns the condition matching the rows placed after the cursor, or before it on backward pages.
+// e.g. for (a ASC, id ASC): (a > $1 OR (a = $1 AND id > $2))
+func (s *userSearch) keyset(sorts []entity.Sort, cursor *entity.Cursor) (string, error) {
+	placeholders := make([]string, len(sorts))
+	placeholder := func(i int) string {
+		if placeholders[i] == "" || s.positional {
+			placeholders[i] = s.bind(cursor.Values[i])
+		}
+		return placeholders[i]
+	}
+
+	alts := make([]string, 0, len(sorts))
+	for i, srt := range sorts {
+		col, ok := userColumns[srt.Field]
+		if !ok {
+			return "", &InvalidFieldErr{Name: srt.Field, Err: ErrFieldNotSupported}
+		}
+		op := ">"
+		if srt.Desc != cursor.Backward {
+			op = "<"
+		}
+		conds := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			conds = append(conds, fmt.Sprintf("%s = %s", userColumns[sorts[j].Field], placeholder(j)))
+		}
+		conds = append(conds, fmt.Sprintf("%s %s %s", col, op, placeholder(i)))
+		alts = append(alts, "("+strings.Join(conds, " AND ")+")")
+	}
+	return "(" + strings.Join(alts, " OR ") + ")", nil
+}
+
+// userSortKeys returns the sort fields closed by the ID, unless the ID is already sorted.
+func userSortKeys(sorts []entity.Sort) []entity.Sort {
+	for _, srt := range sorts {
+		if srt.Field == entity.UserFieldID {
+			return sorts
+		}
+	}
+	keys := make([]entity.Sort, 0, len(sorts)+1)
+	keys = append(keys, sorts...)
+	return append(keys, entity.Sort{Field: entity.UserFieldID})
+}
+
+// escapeLike escapes the LIKE wildcards of the value so they match literally.
+func escapeLike(value any) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(fmt.Sprint(value))
+}
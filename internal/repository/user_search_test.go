@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserSearch(t *testing.T) {
+	from := time.Date(1990, time.January, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		query entity.UserQuery
+		where string
+		page  string
+		args  []any
+		// positional builds the clauses with the "?" placeholders.
+		positional bool
+		// errField is the name of the field rejected by the builder.
+		errField string
+	}{
+		{
+			name:  "Default",
+			query: entity.UserQuery{},
+			page:  " ORDER BY id",
+			args:  nil,
+		},
+		{
+			name: "Unsupported field",
+			query: entity.UserQuery{
+				Filters: []entity.Filter{{Field: "passwd", Op: entity.FilterEq, Value: "x"}},
+			},
+			errField: "passwd",
+		},
+		{
+			name: "Filters",
+			query: entity.UserQuery{
+				Filters: []entity.Filter{
+					{Field: entity.UserFieldRole, Op: entity.FilterEq, Value: "admin"},
+					{Field: entity.UserFieldFirstName, Op: entity.FilterPrefix, Value: "fo"},
+					{Field: entity.UserFieldEmail, Op: entity.FilterContains, Value: "50%_off"},
+					{Field: entity.UserFieldBirthDay, Op: entity.FilterGte, Value: from},
+				},
+				Limit:  10,
+				Offset: 20,
+			},
+			where: " WHERE role = $1 AND first_name LIKE $2 AND email LIKE $3 AND birthday >= $4",
+			page:  " ORDER BY id LIMIT $5 OFFSET $6",
+			args:  []any{"admin", "fo%", `%50\%\_off%`, from, 10, 20},
+		},
+		{
+			name: "Multi-column sort",
+			query: entity.UserQuery{
+				Sort: []entity.Sort{
+					{Field: entity.UserFieldLastName},
+					{Field: entity.UserFieldCreatedAt, Desc: true},
+				},
+			},
+			page: " ORDER BY last_name, created_at DESC, id",
+		},
+		{
+			name: "Forward cursor",
+			query: entity.UserQuery{
+				Sort:   []entity.Sort{{Field: entity.UserFieldLastName, Desc: true}},
+				Limit:  5,
+				Cursor: &entity.Cursor{Values: []any{"baz", uint64(7)}},
+			},
+			page: " WHERE ((last_name < $1) OR (last_name = $1 AND id > $2)) ORDER BY last_name DESC, id LIMIT $3",
+			args: []any{"baz", uint64(7), 5},
+		},
+		{
+			name: "Forward cursor with positional placeholders",
+			query: entity.UserQuery{
+				Sort:   []entity.Sort{{Field: entity.UserFieldLastName, Desc: true}},
+				Limit:  5,
+				Cursor: &entity.Cursor{Values: []any{"baz", uint64(7)}},
+			},
+			positional: true,
+			page:       " WHERE ((last_name < ?) OR (last_name = ? AND id > ?)) ORDER BY last_name DESC, id LIMIT ?",
+			args:       []any{"baz", "baz", uint64(7), 5},
+		},
+		{
+			name: "Backward cursor",
+			query: entity.UserQuery{
+				Filters: []entity.Filter{{Field: entity.UserFieldActive, Op: entity.FilterEq, Value: true}},
+				Limit:   5,
+				Cursor:  &entity.Cursor{Values: []any{uint64(7)}, Backward: true},
+			},
+			where: " WHERE active = $1",
+			page:  " AND ((id < $2)) ORDER BY id DESC LIMIT $3",
+			args:  []any{true, uint64(7), 5},
+		},
+		{
+			name: "Cursor values mismatch",
+			query: entity.UserQuery{
+				Sort:   []entity.Sort{{Field: entity.UserFieldLastName}},
+				Cursor: &entity.Cursor{Values: []any{uint64(7)}},
+			},
+			errField: "cursor",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			search := userSearch{positional: tt.positional}
+			where, err := search.where(tt.query.Filters)
+			if err == nil {
+				var page string
+				page, err = search.page(tt.query, where != "")
+				if err == nil {
+					assert.Equal(t, tt.where, where)
+					assert.Equal(t, tt.page, page)
+					assert.Equal(t, tt.args, search.args)
+				}
+			}
+
+			if tt.errField != "" {
+				var fieldErr *InvalidFieldErr
+				assert.ErrorAs(t, err, &fieldErr)
+				assert.Equal(t, tt.errField, fieldErr.Name)
+				return
+			}
+			assert.Nil(t, err)
+		})
+	}
+}
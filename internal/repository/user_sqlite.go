@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+)
+
+// UserRepositorySQLite stores the users in a SQLite database file.
+// SQLite compares the timestamps as text, so they are all written in UTC by the repository instead of the database.
+type UserRepositorySQLite struct {
+	db *sql.DB
+}
+
+func NewUserRepositorySQLite(db *sql.DB) UserRepositorySQLite {
+	return UserRepositorySQLite{
+		db: db,
+	}
+}
+
+func (r UserRepositorySQLite) Create(ctx context.Context, user entity.User) error {
+	_, err := r.db.ExecContext(ctx, "INSERT INTO users (first_name, last_name, birthday, email, username, passwd, role, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+		user.FirstName, user.LastName, user.BirthDay.UTC(), user.Email, user.Username, user.Passwd, user.Role, time.Now().UTC(),
+	)
+	if err != nil {
+		return sqliteErr(ctx, userEntity, err)
+	}
+
+	return nil
+}
+
+func (r UserRepositorySQLite) Read(ctx context.Context, id uint64) (entity.User, error) {
+	var user entity.User
+	row := r.db.QueryRowContext(ctx, `
+		SELECT id, first_name, last_name, email, birthday,
+			username, passwd, role, active, last_login,
+			created_at, updated_at
+		FROM users WHERE id = $1`, id)
+	err := row.Scan(
+		&user.ID, &user.FirstName, &user.LastName, &user.Email, &user.BirthDay,
+		&user.Username, &user.Passwd, &user.Role, &user.Active, &user.LastLogin,
+		&user.CreatedAt, &user.UpdatedAt,
+	)
+	if err != nil {
+		return entity.User{}, sqliteErr(ctx, userEntity, err)
+	}
+	return user, nil
+}
+
+func (r UserRepositorySQLite) ReadAll(ctx context.Context) ([]entity.User, error) {
+	rows, err := r.db.QueryContext(ctx, `
+	SELECT id, first_name, last_name, email, birthday,
+		username, passwd, role, active, last_login,
+		created_at, updated_at
+	FROM users
+	`)
+	if err != nil {
+		return nil, sqliteErr(ctx, userEntity, err)
+	}
+	defer rows.Close()
+
+	users, err := scanUsers(rows)
+	if err != nil {
+		return nil, sqliteErr(ctx, userEntity, err)
+	}
+	return users, nil
+}
+
+// Search returns the page of users matching the query filters, and the total of users matched.
+func (r UserRepositorySQLite) Search(ctx context.Context, q entity.UserQuery) (entity.UserPage, error) {
+	search := userSearch{likeEscape: ` ESCAPE '\'`}
+	where, err := search.where(q.Filters)
+	if err != nil {
+		return entity.UserPage{}, err
+	}
+
+	var total int
+	row := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users"+where, utcArgs(search.args)...)
+	if err := row.Scan(&total); err != nil {
+		return entity.UserPage{}, sqliteErr(ctx, userEntity, err)
+	}
+
+	page, err := search.page(q, where != "")
+	if err != nil {
+		return entity.UserPage{}, err
+	}
+	rows, err := r.db.QueryContext(ctx, `
+	SELECT id, first_name, last_name, email, birthday,
+		username, passwd, role, active, last_login,
+		created_at, updated_at
+	FROM users`+where+page, utcArgs(search.args)...)
+	if err != nil {
+		return entity.UserPage{}, sqliteErr(ctx, userEntity, err)
+	}
+	defer rows.Close()
+
+	users, err := scanUsers(rows)
+	if err != nil {
+		return entity.UserPage{}, sqliteErr(ctx, userEntity, err)
+	}
+	if q.Cursor != nil && q.Cursor.Backward {
+		for i, j := 0, len(users)-1; i < j; i, j = i+1, j-1 {
+			users[i], users[j] = users[j], users[i]
+		}
+	}
+	return entity.UserPage{Users: users, Total: total}, nil
+}
+
+func (r UserRepositorySQLite) Update(ctx context.Context, user entity.User) error {
+	if user.LastLogin.Valid {
+		user.LastLogin.Time = user.LastLogin.Time.UTC()
+	}
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE users SET
+			first_name = $1,
+			last_name = $2,
+			email = $3,
+			birthday = $4,
+
+			username = $5,
+			passwd = $6,
+			role = $7,
+			active = $8,
+			last_login = $9,
+			updated_at = $10
+		WHERE
+			id = $11`,
+		user.FirstName, user.LastName, user.Email, user.BirthDay.UTC(),
+		user.Username, user.Passwd, user.Role, user.Active, user.LastLogin,
+		time.Now().UTC(), user.ID,
+	)
+	if err != nil {
+		return sqliteErr(ctx, userEntity, err)
+	}
+	return sqliteRowsAffected(ctx, userEntity, res)
+}
+
+func (r UserRepositorySQLite) Delete(ctx context.Context, id uint64) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM users WHERE id = $1", id)
+	if err != nil {
+		return sqliteErr(ctx, userEntity, err)
+	}
+	return sqliteRowsAffected(ctx, userEntity, res)
+}
+
+// utcArgs returns the statement arguments with the times converted to UTC, so they compare with the stored ones.
+func utcArgs(args []any) []any {
+	converted := make([]any, len(args))
+	for i, arg := range args {
+		if t, ok := arg.(time.Time); ok {
+			arg = t.UTC()
+		}
+		converted[i] = arg
+	}
+	return converted
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/config"
+	"github.com/wizeline/CA-Microservices-Go/internal/db"
+	"github.com/wizeline/CA-Microservices-Go/internal/db/migration"
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+	"github.com/wizeline/CA-Microservices-Go/internal/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSQLiteDB returns a migrated SQLite database stored in a temporary file.
+func newSQLiteDB(t *testing.T) *sql.DB {
+	t.Helper()
+	t.Setenv("CAMGO_DATABASE_SQLITE_PATH", filepath.Join(t.TempDir(), "camgo.db"))
+	conn, err := db.NewSQLiteConn(config.NewConfig().Database.SQLite)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	migrations, err := migration.Load(migration.SQLite, "")
+	require.NoError(t, err)
+	require.NoError(t, migration.Run(conn.DB(), migration.SQLite, migrations, 0, logger.NewZeroLog()))
+	return conn.DB()
+}
+
+func TestUserRepositorySQLite(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepositorySQLite(newSQLiteDB(t))
+	birthday := time.Date(1990, 4, 21, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, repo.Create(ctx, entity.User{Email: "jdoe@camgo.dev", Username: "jdoe", BirthDay: birthday, Role: entity.RoleUser}))
+	require.NoError(t, repo.Create(ctx, entity.User{Email: "jroe@camgo.dev", Username: "jroe", BirthDay: birthday, Role: entity.RoleUser}))
+
+	err := repo.Create(ctx, entity.User{Email: "jdoe@camgo.dev", Username: "other", BirthDay: birthday, Role: entity.RoleUser})
+	assert.EqualError(t, err, "user conflict on field email: already exists")
+	err = repo.Create(ctx, entity.User{Email: "other@camgo.dev", Username: "other", BirthDay: birthday, Role: "unknown"})
+	assert.ErrorIs(t, err, ErrReferenceViolated)
+	var conflict *ConflictErr
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, "role", conflict.Field)
+
+	user, err := repo.Read(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "jdoe", user.Username)
+	assert.True(t, birthday.Equal(user.BirthDay))
+	assert.False(t, user.Active)
+	assert.False(t, user.CreatedAt.IsZero())
+	assert.False(t, user.UpdatedAt.Valid)
+
+	_, err = repo.Read(ctx, 3)
+	assert.ErrorAs(t, err, new(*NotFoundErr))
+
+	user.Username = "jroe"
+	assert.ErrorAs(t, repo.Update(ctx, user), new(*ConflictErr))
+	user.Username = "john.doe"
+	user.Active = true
+	user.LastLogin = sql.NullTime{Time: time.Now(), Valid: true}
+	require.NoError(t, repo.Update(ctx, user))
+	user, err = repo.Read(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "john.doe", user.Username)
+	assert.True(t, user.Active)
+	assert.True(t, user.LastLogin.Valid)
+	assert.True(t, user.UpdatedAt.Valid)
+	assert.ErrorAs(t, repo.Update(ctx, entity.User{ID: 3, Role: entity.RoleUser}), new(*NotFoundErr))
+
+	require.NoError(t, repo.Delete(ctx, 1))
+	assert.ErrorAs(t, repo.Delete(ctx, 1), new(*NotFoundErr))
+
+	users, err := repo.ReadAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, "jroe", users[0].Username)
+}
+
+func TestUserRepositorySQLite_Search(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepositorySQLite(newSQLiteDB(t))
+	for _, u := range []entity.User{
+		{FirstName: "John", LastName: "Doe", Email: "jdoe@camgo.dev", Username: "jdoe", BirthDay: time.Date(1990, 4, 21, 0, 0, 0, 0, time.UTC)},
+		{FirstName: "Jane", LastName: "Roe", Email: "jroe@camgo.dev", Username: "jroe", BirthDay: time.Date(1993, 8, 2, 0, 0, 0, 0, time.UTC)},
+		{FirstName: "Ada", LastName: "Doe", Email: "ada_doe@camgo.dev", Username: "ada", BirthDay: time.Date(1985, 12, 10, 0, 0, 0, 0, time.UTC)},
+		{FirstName: "Bob", LastName: "Smith", Email: "bob@camgo.dev", Username: "bob", BirthDay: time.Date(1990, 4, 21, 0, 0, 0, 0, time.UTC)},
+	} {
+		u.Role = entity.RoleUser
+		require.NoError(t, repo.Create(ctx, u))
+	}
+
+	tests := []struct {
+		name      string
+		query     entity.UserQuery
+		usernames []string
+		total     int
+	}{
+		{
+			name:      "Every user sorted by ID",
+			query:     entity.UserQuery{},
+			usernames: []string{"jdoe", "jroe", "ada", "bob"},
+			total:     4,
+		},
+		{
+			name: "Birthday in another time zone",
+			query: entity.UserQuery{Filters: []entity.Filter{
+				{Field: entity.UserFieldLastName, Op: entity.FilterEq, Value: "Doe"},
+				{Field: entity.UserFieldBirthDay, Op: entity.FilterGte, Value: time.Date(1989, 12, 31, 18, 0, 0, 0, time.FixedZone("CST", -6*3600))},
+			}},
+			usernames: []string{"jdoe"},
+			total:     1,
+		},
+		{
+			name: "Escaped wildcards",
+			query: entity.UserQuery{Filters: []entity.Filter{
+				{Field: entity.UserFieldEmail, Op: entity.FilterContains, Value: "_doe"},
+			}},
+			usernames: []string{"ada"},
+			total:     1,
+		},
+		{
+			name: "Case-sensitive prefix",
+			query: entity.UserQuery{Filters: []entity.Filter{
+				{Field: entity.UserFieldFirstName, Op: entity.FilterPrefix, Value: "j"},
+			}},
+			usernames: []string{},
+			total:     0,
+		},
+		{
+			name: "Forward cursor",
+			query: entity.UserQuery{
+				Sort:   []entity.Sort{{Field: entity.UserFieldBirthDay}},
+				Limit:  2,
+				Cursor: &entity.Cursor{Values: []any{time.Date(1990, 4, 21, 0, 0, 0, 0, time.UTC), uint64(1)}},
+			},
+			usernames: []string{"bob", "jroe"},
+			total:     4,
+		},
+		{
+			name: "Backward cursor",
+			query: entity.UserQuery{
+				Sort:   []entity.Sort{{Field: entity.UserFieldBirthDay}},
+				Limit:  2,
+				Cursor: &entity.Cursor{Values: []any{time.Date(1990, 4, 21, 0, 0, 0, 0, time.UTC), uint64(4)}, Backward: true},
+			},
+			usernames: []string{"ada", "jdoe"},
+			total:     4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := repo.Search(ctx, tt.query)
+			require.NoError(t, err)
+
+			usernames := make([]string, 0, len(page.Users))
+			for _, u := range page.Users {
+				usernames = append(usernames, u.Username)
+			}
+			assert.Equal(t, tt.usernames, usernames)
+			assert.Equal(t, tt.total, page.Total)
+		})
+	}
+}
@@ -6,11 +6,16 @@ import (
 	"github.com/wizeline/CA-Microservices-Go/internal/config"
 	"github.com/wizeline/CA-Microservices-Go/internal/controller"
 	"github.com/wizeline/CA-Microservices-Go/internal/logger"
+	appmiddleware "github.com/wizeline/CA-Microservices-Go/internal/middleware"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel"
 )
 
+// tracerName is the instrumentation scope of the server spans.
+const tracerName = "github.com/wizeline/CA-Microservices-Go/internal/router"
+
 // Chi configures a chi.Mux instance.
 type Chi struct {
 	basePath    string
@@ -20,12 +25,18 @@ type Chi struct {
 }
 
 // NewChi returns a Chi implementation.
-// It allocates a pre-configured chi.Mux instance.
-func NewChi(cfg config.Application, l logger.ZeroLog) Chi {
+// It allocates a pre-configured chi.Mux instance, every request is served within a span of the global tracer provider,
+// recorded in the given metrics and in the access log, bounded by the configured request timeout and the errors are written
+// in the configured format.
+func NewChi(cfg config.Application, srv config.HTTPServer, metrics appmiddleware.HTTPMetrics, l logger.ZeroLog) Chi {
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
-	r.Use(middleware.Logger)
+	r.Use(appmiddleware.Tracing(otel.Tracer(tracerName), otel.GetTextMapPropagator()))
+	r.Use(appmiddleware.Metrics(metrics))
+	r.Use(appmiddleware.AccessLog(l))
 	r.Use(middleware.Recoverer)
+	r.Use(appmiddleware.Deadline(srv.RequestTimeout()))
+	r.Use(appmiddleware.ProblemDetails(srv.ProblemDetails()))
 
 	return Chi{
 		basePath:    cfg.BasePath(),
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+)
+
+// cursorPayload is the content of the opaque cursors returned by the users search.
+type cursorPayload struct {
+	// Query is the fingerprint of the filters and sorting the cursor was issued for.
+	Query    string `json:"q"`
+	Values   []any  `json:"v"`
+	Backward bool   `json:"b,omitempty"`
+}
+
+// encodeCursor returns the payload as base64url encoded JSON followed by its HMAC-SHA256 signature.
+func encodeCursor(secret []byte, p cursorPayload) (string, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + signCursor(secret, payload), nil
+}
+
+// decodeCursor verifies the signature of the cursor and returns its payload.
+func decodeCursor(secret []byte, cursor string) (cursorPayload, error) {
+	payload, sig, ok := strings.Cut(cursor, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(signCursor(secret, payload))) {
+		return cursorPayload{}, ErrCursorInvalid
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return cursorPayload{}, ErrCursorInvalid
+	}
+
+	var p cursorPayload
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&p); err != nil {
+		return cursorPayload{}, ErrCursorInvalid
+	}
+	return p, nil
+}
+
+func signCursor(secret []byte, payload string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// queryFingerprint identifies the filters and sorting of a search, so a cursor is only accepted by the search it belongs to.
+func queryFingerprint(q entity.UserQuery) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%v|%v", q.Filters, q.Sort)))
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+// cursorValues returns the values of the sort fields of the user, which is the keyset position of the user.
+func cursorValues(sorts []entity.Sort, user entity.User) []any {
+	values := make([]any, 0, len(sorts))
+	for _, srt := range sorts {
+		switch srt.Field {
+		case entity.UserFieldID:
+			values = append(values, user.ID)
+		case entity.UserFieldFirstName:
+			values = append(values, user.FirstName)
+		case entity.UserFieldLastName:
+			values = append(values, user.LastName)
+		case entity.UserFieldEmail:
+			values = append(values, user.Email)
+		case entity.UserFieldUsername:
+			values = append(values, user.Username)
+		case entity.UserFieldBirthDay:
+			values = append(values, user.BirthDay)
+		case entity.UserFieldCreatedAt:
+			values = append(values, user.CreatedAt)
+		}
+	}
+	return values
+}
+
+// parseCursorValues converts the decoded values of a cursor to the type of the sort fields.
+func parseCursorValues(sorts []entity.Sort, raw []any) ([]any, error) {
+	if len(raw) != len(sorts) {
+		return nil, ErrCursorInvalid
+	}
+	values := make([]any, len(raw))
+	for i, srt := range sorts {
+		switch srt.Field {
+		case entity.UserFieldID:
+			n, ok := raw[i].(json.Number)
+			if !ok {
+				return nil, ErrCursorInvalid
+			}
+			id, err := strconv.ParseUint(n.String(), 10, 64)
+			if err != nil {
+				return nil, ErrCursorInvalid
+			}
+			values[i] = id
+		case entity.UserFieldBirthDay, entity.UserFieldCreatedAt:
+			s, ok := raw[i].(string)
+			if !ok {
+				return nil, ErrCursorInvalid
+			}
+			t, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return nil, ErrCursorInvalid
+			}
+			values[i] = t
+		default:
+			s, ok := raw[i].(string)
+			if !ok {
+				return nil, ErrCursorInvalid
+			}
+			values[i] = s
+		}
+	}
+	return values, nil
+}
@@ -10,12 +10,38 @@ var (
 	ErrZeroValue    = errors.New("zero value")
 	ErrEmptyValue   = errors.New("empty value")
 
-	ErrEmptyArgs        = errors.New("empty arguments")
-	ErrInvalidEmail     = errors.New("invalid email")
-	ErrInvalidPasswd    = errors.New("invalid password")
-	ErrPasswdDoNotMatch = errors.New("passwords do not match")
+	ErrEmptyArgs          = errors.New("empty arguments")
+	ErrInvalidEmail       = errors.New("invalid email")
+	ErrInvalidPasswd      = errors.New("invalid password")
+	ErrPasswdDoNotMatch   = errors.New("passwords do not match")
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrInvalidFormat      = errors.New("invalid format")
+	ErrOutOfRange         = errors.New("out of range")
+
+	ErrBuiltInRole        = errors.New("built-in role")
+	ErrRoleUnknown        = errors.New("unknown role")
+	ErrPermissionRequired = errors.New("required permission missing")
+
+	ErrTokenInvalid = errors.New("invalid token")
+	ErrTokenExpired = errors.New("token expired")
+	ErrTokenReused  = errors.New("token reused")
+	ErrUserInactive = errors.New("user inactive")
+
+	ErrCursorInvalid = errors.New("invalid cursor")
 )
 
+// notFoundErr is implemented by the errors reporting a missing entity, such as the repository ones.
+type notFoundErr interface {
+	error
+	NotFound() bool
+}
+
+// isNotFound reports whether any error in the chain of err reports a missing entity.
+func isNotFound(err error) bool {
+	var nf notFoundErr
+	return errors.As(err, &nf) && nf.NotFound()
+}
+
 type Err struct {
 	Err error
 }
@@ -53,3 +79,15 @@ func (e InvalidFilterErr) Error() string {
 func (e InvalidFilterErr) Unwrap() error {
 	return e.Err
 }
+
+type UnauthorizedErr struct {
+	Err error
+}
+
+func (e UnauthorizedErr) Error() string {
+	return fmt.Sprintf("unauthorized: %s", e.Err)
+}
+
+func (e UnauthorizedErr) Unwrap() error {
+	return e.Err
+}
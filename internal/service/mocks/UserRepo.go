@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	mock "github.com/stretchr/testify/mock"
 	entity "github.com/wizeline/CA-Microservices-Go/internal/entity"
 )
@@ -12,17 +14,17 @@ type UserRepo struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: user
-func (_m *UserRepo) Create(user entity.User) error {
-	ret := _m.Called(user)
+// Create provides a mock function with given fields: ctx, user
+func (_m *UserRepo) Create(ctx context.Context, user entity.User) error {
+	ret := _m.Called(ctx, user)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Create")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(entity.User) error); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, entity.User) error); ok {
+		r0 = rf(ctx, user)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -30,17 +32,17 @@ func (_m *UserRepo) Create(user entity.User) error {
 	return r0
 }
 
-// Delete provides a mock function with given fields: id
-func (_m *UserRepo) Delete(id uint64) error {
-	ret := _m.Called(id)
+// Delete provides a mock function with given fields: ctx, id
+func (_m *UserRepo) Delete(ctx context.Context, id uint64) error {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Delete")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(uint64) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -48,9 +50,9 @@ func (_m *UserRepo) Delete(id uint64) error {
 	return r0
 }
 
-// Read provides a mock function with given fields: id
-func (_m *UserRepo) Read(id uint64) (entity.User, error) {
-	ret := _m.Called(id)
+// Read provides a mock function with given fields: ctx, id
+func (_m *UserRepo) Read(ctx context.Context, id uint64) (entity.User, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Read")
@@ -58,17 +60,17 @@ func (_m *UserRepo) Read(id uint64) (entity.User, error) {
 
 	var r0 entity.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(uint64) (entity.User, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (entity.User, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(uint64) entity.User); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) entity.User); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Get(0).(entity.User)
 	}
 
-	if rf, ok := ret.Get(1).(func(uint64) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -76,9 +78,9 @@ func (_m *UserRepo) Read(id uint64) (entity.User, error) {
 	return r0, r1
 }
 
-// ReadAll provides a mock function with given fields:
-func (_m *UserRepo) ReadAll() ([]entity.User, error) {
-	ret := _m.Called()
+// ReadAll provides a mock function with given fields: ctx
+func (_m *UserRepo) ReadAll(ctx context.Context) ([]entity.User, error) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ReadAll")
@@ -86,19 +88,47 @@ func (_m *UserRepo) ReadAll() ([]entity.User, error) {
 
 	var r0 []entity.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]entity.User, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context) ([]entity.User, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func() []entity.User); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []entity.User); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Search provides a mock function with given fields: ctx, q
+func (_m *UserRepo) Search(ctx context.Context, q entity.UserQuery) (entity.UserPage, error) {
+	ret := _m.Called(ctx, q)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Search")
+	}
+
+	var r0 entity.UserPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.UserQuery) (entity.UserPage, error)); ok {
+		return rf(ctx, q)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.UserQuery) entity.UserPage); ok {
+		r0 = rf(ctx, q)
+	} else {
+		r0 = ret.Get(0).(entity.UserPage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.UserQuery) error); ok {
+		r1 = rf(ctx, q)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -106,17 +136,17 @@ func (_m *UserRepo) ReadAll() ([]entity.User, error) {
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: user
-func (_m *UserRepo) Update(user entity.User) error {
-	ret := _m.Called(user)
+// Update provides a mock function with given fields: ctx, user
+func (_m *UserRepo) Update(ctx context.Context, user entity.User) error {
+	ret := _m.Called(ctx, user)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Update")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(entity.User) error); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, entity.User) error); ok {
+		r0 = rf(ctx, user)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+)
+
+type RoleRepo interface {
+	Create(ctx context.Context, role entity.Role) error
+	Read(ctx context.Context, name string) (entity.Role, error)
+	ReadAll(ctx context.Context) ([]entity.Role, error)
+	Update(ctx context.Context, role entity.Role) error
+	Delete(ctx context.Context, name string) error
+	HasPermission(ctx context.Context, role, permission string) (bool, error)
+}
+
+type RoleArgs struct {
+	Name        string
+	Description string
+	Permissions []string
+}
+
+type RoleResponse struct {
+	Name        string
+	Description string
+	Permissions []string
+}
+
+type RoleService struct {
+	repo RoleRepo
+}
+
+func NewRoleService(repo RoleRepo) RoleService {
+	return RoleService{
+		repo: repo,
+	}
+}
+
+func (s RoleService) Create(ctx context.Context, args RoleArgs) error {
+	if err := validateRole(args); err != nil {
+		return err
+	}
+	return s.repo.Create(ctx, entity.Role{
+		Name:        args.Name,
+		Description: args.Description,
+		Permissions: args.Permissions,
+	})
+}
+
+func (s RoleService) Get(ctx context.Context, name string) (RoleResponse, error) {
+	if name == "" {
+		return RoleResponse{}, &InvalidInputErr{Field: "name", Err: ErrEmptyValue}
+	}
+	role, err := s.repo.Read(ctx, name)
+	if err != nil {
+		return RoleResponse{}, err
+	}
+	return parseRoleResp(role), nil
+}
+
+func (s RoleService) GetAll(ctx context.Context) ([]RoleResponse, error) {
+	roles, err := s.repo.ReadAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rolesResp := make([]RoleResponse, 0)
+	for _, r := range roles {
+		rolesResp = append(rolesResp, parseRoleResp(r))
+	}
+	return rolesResp, nil
+}
+
+// Update replaces the description and the permission set of the role.
+// The admin role must keep the permission to manage roles, so it can not lock itself out.
+func (s RoleService) Update(ctx context.Context, args RoleArgs) error {
+	if err := validateRole(args); err != nil {
+		return err
+	}
+	if args.Name == entity.RoleAdmin && !contains(args.Permissions, entity.PermRolesManage) {
+		return &InvalidInputErr{Field: "Permissions", Err: ErrPermissionRequired}
+	}
+	return s.repo.Update(ctx, entity.Role{
+		Name:        args.Name,
+		Description: args.Description,
+		Permissions: args.Permissions,
+	})
+}
+
+func (s RoleService) Delete(ctx context.Context, name string) error {
+	if name == "" {
+		return &InvalidInputErr{Field: "name", Err: ErrEmptyValue}
+	}
+	if name == entity.RoleAdmin || name == entity.RoleUser {
+		return &InvalidInputErr{Field: "name", Err: ErrBuiltInRole}
+	}
+	return s.repo.Delete(ctx, name)
+}
+
+// HasPermission reports whether the role has been granted the given permission.
+func (s RoleService) HasPermission(ctx context.Context, role, permission string) (bool, error) {
+	if role == "" || permission == "" {
+		return false, nil
+	}
+	return s.repo.HasPermission(ctx, role, permission)
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+	"github.com/wizeline/CA-Microservices-Go/internal/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// We ensure the RoleRepo mock object satisfies the RoleRepo signature.
+var _ RoleRepo = &mocks.RoleRepo{}
+
+func TestRoleService_Create(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    RoleArgs
+		repoErr error
+		err     error
+	}{
+		{
+			name: "Empty name",
+			args: RoleArgs{Name: ""},
+			err:  &InvalidInputErr{Field: "Name", Err: ErrEmptyValue},
+		},
+		{
+			name: "Invalid name",
+			args: RoleArgs{Name: "Super Admin"},
+			err:  &InvalidInputErr{Field: "Name", Err: ErrInvalidFormat},
+		},
+		{
+			name: "Invalid permission",
+			args: RoleArgs{Name: "auditor", Permissions: []string{"users"}},
+			err:  &InvalidInputErr{Field: "Permissions", Err: ErrInvalidFormat},
+		},
+		{
+			name:    "Repository error",
+			args:    RoleArgs{Name: "auditor", Permissions: []string{"users:read"}},
+			repoErr: errRepoTest,
+			err:     errRepoTest,
+		},
+		{
+			name: "Created",
+			args: RoleArgs{Name: "auditor", Description: "read only", Permissions: []string{"users:read"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mocks.RoleRepo{}
+			mockRepo.On("Create", mock.Anything, entity.Role{
+				Name:        tt.args.Name,
+				Description: tt.args.Description,
+				Permissions: tt.args.Permissions,
+			}).Return(tt.repoErr)
+			svc := NewRoleService(mockRepo)
+
+			err := svc.Create(context.Background(), tt.args)
+
+			if tt.err != nil {
+				assert.Error(t, err)
+				assert.EqualError(t, err, tt.err.Error())
+				return
+			}
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestRoleService_Update(t *testing.T) {
+	tests := []struct {
+		name string
+		args RoleArgs
+		err  error
+	}{
+		{
+			name: "Admin can not lose roles management",
+			args: RoleArgs{Name: entity.RoleAdmin, Permissions: []string{entity.PermUsersRead}},
+			err:  &InvalidInputErr{Field: "Permissions", Err: ErrPermissionRequired},
+		},
+		{
+			name: "Admin updated",
+			args: RoleArgs{Name: entity.RoleAdmin, Permissions: []string{entity.PermRolesManage}},
+		},
+		{
+			name: "Custom role updated",
+			args: RoleArgs{Name: "auditor", Permissions: []string{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mocks.RoleRepo{}
+			mockRepo.On("Update", mock.Anything, entity.Role{
+				Name:        tt.args.Name,
+				Permissions: tt.args.Permissions,
+			}).Return(nil)
+			svc := NewRoleService(mockRepo)
+
+			err := svc.Update(context.Background(), tt.args)
+
+			if tt.err != nil {
+				assert.Error(t, err)
+				assert.EqualError(t, err, tt.err.Error())
+				return
+			}
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestRoleService_Delete(t *testing.T) {
+	tests := []struct {
+		name     string
+		roleName string
+		err      error
+	}{
+		{
+			name:     "Empty name",
+			roleName: "",
+			err:      &InvalidInputErr{Field: "name", Err: ErrEmptyValue},
+		},
+		{
+			name:     "Built-in admin",
+			roleName: entity.RoleAdmin,
+			err:      &InvalidInputErr{Field: "name", Err: ErrBuiltInRole},
+		},
+		{
+			name:     "Built-in user",
+			roleName: entity.RoleUser,
+			err:      &InvalidInputErr{Field: "name", Err: ErrBuiltInRole},
+		},
+		{
+			name:     "Deleted",
+			roleName: "auditor",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mocks.RoleRepo{}
+			mockRepo.On("Delete", mock.Anything, tt.roleName).Return(nil)
+			svc := NewRoleService(mockRepo)
+
+			err := svc.Delete(context.Background(), tt.roleName)
+
+			if tt.err != nil {
+				assert.Error(t, err)
+				assert.EqualError(t, err, tt.err.Error())
+				return
+			}
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestRoleService_HasPermission(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       string
+		permission string
+		granted    bool
+		repoErr    error
+		exp        bool
+		err        error
+	}{
+		{
+			name:       "No role",
+			role:       "",
+			permission: entity.PermUsersRead,
+			exp:        false,
+		},
+		{
+			name:       "Repository error",
+			role:       entity.RoleUser,
+			permission: entity.PermUsersRead,
+			repoErr:    errRepoTest,
+			err:        errRepoTest,
+		},
+		{
+			name:       "Denied",
+			role:       entity.RoleUser,
+			permission: entity.PermUsersDelete,
+			granted:    false,
+			exp:        false,
+		},
+		{
+			name:       "Granted",
+			role:       entity.RoleAdmin,
+			permission: entity.PermUsersDelete,
+			granted:    true,
+			exp:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mocks.RoleRepo{}
+			mockRepo.On("HasPermission", mock.Anything, tt.role, tt.permission).Return(tt.granted, tt.repoErr)
+			svc := NewRoleService(mockRepo)
+
+			out, err := svc.HasPermission(context.Background(), tt.role, tt.permission)
+
+			if tt.err != nil {
+				assert.Error(t, err)
+				assert.EqualError(t, err, tt.err.Error())
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.exp, out)
+		})
+	}
+}
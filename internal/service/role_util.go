@@ -0,0 +1,54 @@
+package service
+
+import (
+	"regexp"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+)
+
+var (
+	roleNameRegex   = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,49}$`)
+	permissionRegex = regexp.MustCompile(`^[a-z][a-z0-9_-]*:[a-z][a-z0-9_-]*$`)
+)
+
+func validateRole(r RoleArgs) error {
+	if r.Name == "" {
+		return &InvalidInputErr{Field: "Name", Err: ErrEmptyValue}
+	}
+	if err := validateRoleName(r.Name); err != nil {
+		return &InvalidInputErr{Field: "Name", Err: err}
+	}
+	for _, perm := range r.Permissions {
+		if !permissionRegex.MatchString(perm) {
+			return &InvalidInputErr{Field: "Permissions", Err: ErrInvalidFormat}
+		}
+	}
+	return nil
+}
+
+func validateRoleName(name string) error {
+	if name == "" {
+		return ErrEmptyValue
+	}
+	if !roleNameRegex.MatchString(name) {
+		return ErrInvalidFormat
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func parseRoleResp(role entity.Role) RoleResponse {
+	return RoleResponse{
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: role.Permissions,
+	}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+)
+
+type TokenRepo interface {
+	Create(ctx context.Context, token entity.RefreshToken) error
+	ReadByHash(ctx context.Context, hash string) (entity.RefreshToken, error)
+	// Revoke revokes the given token and reports whether it was still active.
+	Revoke(ctx context.Context, id uint64) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeAll(ctx context.Context, userID uint64) error
+}
+
+type RefreshTokenResponse struct {
+	Token     string
+	UserID    uint64
+	ExpiresAt time.Time
+}
+
+type TokenService struct {
+	repo TokenRepo
+	ttl  time.Duration
+}
+
+func NewTokenService(repo TokenRepo, ttl time.Duration) TokenService {
+	return TokenService{
+		repo: repo,
+		ttl:  ttl,
+	}
+}
+
+// Issue creates a refresh token for the given user starting a new token family.
+func (s TokenService) Issue(ctx context.Context, userID uint64) (RefreshTokenResponse, error) {
+	if userID == 0 {
+		return RefreshTokenResponse{}, &InvalidInputErr{Field: "userID", Err: ErrZeroValue}
+	}
+	familyID, err := randomToken(familyIDSize)
+	if err != nil {
+		return RefreshTokenResponse{}, err
+	}
+	return s.create(ctx, userID, familyID)
+}
+
+// Verify returns the ID of the user the given refresh token was issued for, without exchanging it.
+// Reusing a token already exchanged or revoked revokes the whole token family.
+func (s TokenService) Verify(ctx context.Context, token string) (uint64, error) {
+	current, err := s.verify(ctx, token)
+	if err != nil {
+		return 0, err
+	}
+	return current.UserID, nil
+}
+
+// Rotate exchanges the given refresh token for a new one of the same family.
+// Reusing a token already exchanged or revoked revokes the whole token family.
+func (s TokenService) Rotate(ctx context.Context, token string) (RefreshTokenResponse, error) {
+	current, err := s.verify(ctx, token)
+	if err != nil {
+		return RefreshTokenResponse{}, err
+	}
+
+	revoked, err := s.repo.Revoke(ctx, current.ID)
+	if err != nil {
+		return RefreshTokenResponse{}, err
+	}
+	if !revoked {
+		// The token was exchanged concurrently by another request.
+		if err := s.repo.RevokeFamily(ctx, current.FamilyID); err != nil {
+			return RefreshTokenResponse{}, err
+		}
+		return RefreshTokenResponse{}, &UnauthorizedErr{Err: ErrTokenReused}
+	}
+	return s.create(ctx, current.UserID, current.FamilyID)
+}
+
+// Revoke revokes the token family of the given refresh token.
+func (s TokenService) Revoke(ctx context.Context, token string) error {
+	current, err := s.read(ctx, token)
+	if err != nil {
+		return err
+	}
+	return s.repo.RevokeFamily(ctx, current.FamilyID)
+}
+
+// RevokeAll revokes every refresh token issued for the given user.
+func (s TokenService) RevokeAll(ctx context.Context, userID uint64) error {
+	if userID == 0 {
+		return &InvalidInputErr{Field: "userID", Err: ErrZeroValue}
+	}
+	return s.repo.RevokeAll(ctx, userID)
+}
+
+// verify returns the given refresh token when it is still active, the token family is revoked when it is reused.
+func (s TokenService) verify(ctx context.Context, token string) (entity.RefreshToken, error) {
+	current, err := s.read(ctx, token)
+	if err != nil {
+		return entity.RefreshToken{}, err
+	}
+	if current.RevokedAt.Valid {
+		if err := s.repo.RevokeFamily(ctx, current.FamilyID); err != nil {
+			return entity.RefreshToken{}, err
+		}
+		return entity.RefreshToken{}, &UnauthorizedErr{Err: ErrTokenReused}
+	}
+	if time.Now().After(current.ExpiresAt) {
+		return entity.RefreshToken{}, &UnauthorizedErr{Err: ErrTokenExpired}
+	}
+	return current, nil
+}
+
+func (s TokenService) read(ctx context.Context, token string) (entity.RefreshToken, error) {
+	if token == "" {
+		return entity.RefreshToken{}, &InvalidInputErr{Field: "token", Err: ErrEmptyValue}
+	}
+	current, err := s.repo.ReadByHash(ctx, hashToken(token))
+	if isNotFound(err) {
+		return entity.RefreshToken{}, &UnauthorizedErr{Err: ErrTokenInvalid}
+	}
+	if err != nil {
+		return entity.RefreshToken{}, err
+	}
+	return current, nil
+}
+
+func (s TokenService) create(ctx context.Context, userID uint64, familyID string) (RefreshTokenResponse, error) {
+	token, err := randomToken(refreshTokenSize)
+	if err != nil {
+		return RefreshTokenResponse{}, err
+	}
+	expiresAt := time.Now().Add(s.ttl)
+	err = s.repo.Create(ctx, entity.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(token),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return RefreshTokenResponse{}, err
+	}
+	return RefreshTokenResponse{
+		Token:     token,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+	}, nil
+}
@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+	"github.com/wizeline/CA-Microservices-Go/internal/repository"
+	"github.com/wizeline/CA-Microservices-Go/internal/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// We ensure the TokenRepo mock object satisfies the TokenRepo signature.
+var _ TokenRepo = &mocks.TokenRepo{}
+
+func TestTokenService_Issue(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  uint64
+		repoErr error
+		err     error
+	}{
+		{
+			name:   "User ID zero value",
+			userID: 0,
+			err:    &InvalidInputErr{Field: "userID", Err: ErrZeroValue},
+		},
+		{
+			name:    "Repository error",
+			userID:  1,
+			repoErr: errRepoTest,
+			err:     errRepoTest,
+		},
+		{
+			name:   "Issued",
+			userID: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mocks.TokenRepo{}
+			mockRepo.On("Create", mock.Anything, mock.AnythingOfType("entity.RefreshToken")).Return(tt.repoErr)
+			svc := NewTokenService(mockRepo, time.Hour)
+
+			out, err := svc.Issue(context.Background(), tt.userID)
+
+			if tt.err != nil {
+				assert.Error(t, err)
+				assert.EqualError(t, err, tt.err.Error())
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.userID, out.UserID)
+			assert.NotEmpty(t, out.Token)
+			assert.WithinDuration(t, time.Now().Add(time.Hour), out.ExpiresAt, time.Minute)
+		})
+	}
+}
+
+func TestTokenService_Rotate(t *testing.T) {
+	const token = "some-refresh-token"
+	active := entity.RefreshToken{
+		ID:        1,
+		UserID:    7,
+		FamilyID:  "some-family",
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	revoked := active
+	revoked.RevokedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	expired := active
+	expired.ExpiresAt = time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name         string
+		token        string
+		stored       entity.RefreshToken
+		readErr      error
+		revoked      bool
+		revokeFamily bool
+		err          error
+	}{
+		{
+			name:  "Empty token",
+			token: "",
+			err:   &InvalidInputErr{Field: "token", Err: ErrEmptyValue},
+		},
+		{
+			name:    "Unknown token",
+			token:   token,
+			readErr: &repository.NotFoundErr{Entity: "refresh token"},
+			err:     &UnauthorizedErr{Err: ErrTokenInvalid},
+		},
+		{
+			name:    "Repository error",
+			token:   token,
+			readErr: errRepoTest,
+			err:     errRepoTest,
+		},
+		{
+			name:         "Reused token revokes the family",
+			token:        token,
+			stored:       revoked,
+			revokeFamily: true,
+			err:          &UnauthorizedErr{Err: ErrTokenReused},
+		},
+		{
+			name:   "Expired token",
+			token:  token,
+			stored: expired,
+			err:    &UnauthorizedErr{Err: ErrTokenExpired},
+		},
+		{
+			name:         "Concurrent reuse revokes the family",
+			token:        token,
+			stored:       active,
+			revoked:      false,
+			revokeFamily: true,
+			err:          &UnauthorizedErr{Err: ErrTokenReused},
+		},
+		{
+			name:    "Rotated",
+			token:   token,
+			stored:  active,
+			revoked: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewTokenRepo(t)
+			if tt.token != "" {
+				mockRepo.On("ReadByHash", mock.Anything, hashToken(tt.token)).Return(tt.stored, tt.readErr)
+			}
+			if tt.revokeFamily {
+				mockRepo.On("RevokeFamily", mock.Anything, tt.stored.FamilyID).Return(nil).Once()
+			}
+			if tt.stored == active {
+				mockRepo.On("Revoke", mock.Anything, active.ID).Return(tt.revoked, nil).Once()
+			}
+			if tt.revoked {
+				mockRepo.On("Create", mock.Anything, mock.AnythingOfType("entity.RefreshToken")).Return(nil).Once().Run(func(args mock.Arguments) {
+					created := args.Get(1).(entity.RefreshToken)
+					assert.Equal(t, active.UserID, created.UserID)
+					assert.Equal(t, active.FamilyID, created.FamilyID)
+					assert.NotEqual(t, active.TokenHash, created.TokenHash)
+				})
+			}
+			svc := NewTokenService(mockRepo, time.Hour)
+
+			out, err := svc.Rotate(context.Background(), tt.token)
+
+			if tt.err != nil {
+				assert.Error(t, err)
+				assert.EqualError(t, err, tt.err.Error())
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, active.UserID, out.UserID)
+			assert.NotEqual(t, token, out.Token)
+		})
+	}
+}
+
+func TestTokenService_Verify(t *testing.T) {
+	const token = "some-refresh-token"
+	active := entity.RefreshToken{
+		ID:        1,
+		UserID:    7,
+		FamilyID:  "some-family",
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	revoked := active
+	revoked.RevokedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	expired := active
+	expired.ExpiresAt = time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name         string
+		stored       entity.RefreshToken
+		readErr      error
+		revokeFamily bool
+		err          error
+	}{
+		{
+			name:    "Unknown token",
+			readErr: &repository.NotFoundErr{Entity: "refresh token"},
+			err:     &UnauthorizedErr{Err: ErrTokenInvalid},
+		},
+		{
+			name:         "Reused token revokes the family",
+			stored:       revoked,
+			revokeFamily: true,
+			err:          &UnauthorizedErr{Err: ErrTokenReused},
+		},
+		{
+			name:   "Expired token",
+			stored: expired,
+			err:    &UnauthorizedErr{Err: ErrTokenExpired},
+		},
+		{
+			name:   "Verified",
+			stored: active,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewTokenRepo(t)
+			mockRepo.On("ReadByHash", mock.Anything, hashToken(token)).Return(tt.stored, tt.readErr)
+			if tt.revokeFamily {
+				mockRepo.On("RevokeFamily", mock.Anything, tt.stored.FamilyID).Return(nil).Once()
+			}
+			svc := NewTokenService(mockRepo, time.Hour)
+
+			userID, err := svc.Verify(context.Background(), token)
+
+			if tt.err != nil {
+				assert.EqualError(t, err, tt.err.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, active.UserID, userID, "the token is not exchanged")
+		})
+	}
+}
+
+func TestTokenService_Revoke(t *testing.T) {
+	const token = "some-refresh-token"
+	tests := []struct {
+		name    string
+		stored  entity.RefreshToken
+		readErr error
+		err     error
+	}{
+		{
+			name:    "Unknown token",
+			readErr: &repository.NotFoundErr{Entity: "refresh token"},
+			err:     &UnauthorizedErr{Err: ErrTokenInvalid},
+		},
+		{
+			name:   "Revoked",
+			stored: entity.RefreshToken{ID: 1, FamilyID: "some-family"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewTokenRepo(t)
+			mockRepo.On("ReadByHash", mock.Anything, hashToken(token)).Return(tt.stored, tt.readErr)
+			if tt.readErr == nil {
+				mockRepo.On("RevokeFamily", mock.Anything, tt.stored.FamilyID).Return(nil)
+			}
+			svc := NewTokenService(mockRepo, time.Hour)
+
+			err := svc.Revoke(context.Background(), token)
+
+			if tt.err != nil {
+				assert.Error(t, err)
+				assert.EqualError(t, err, tt.err.Error())
+				return
+			}
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestTokenService_RevokeAll(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  uint64
+		repoErr error
+		err     error
+	}{
+		{
+			name:   "User ID zero value",
+			userID: 0,
+			err:    &InvalidInputErr{Field: "userID", Err: ErrZeroValue},
+		},
+		{
+			name:    "Repository error",
+			userID:  1,
+			repoErr: errRepoTest,
+			err:     errRepoTest,
+		},
+		{
+			name:   "Revoked",
+			userID: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mocks.TokenRepo{}
+			mockRepo.On("RevokeAll", mock.Anything, tt.userID).Return(tt.repoErr)
+			svc := NewTokenService(mockRepo, time.Hour)
+
+			err := svc.RevokeAll(context.Background(), tt.userID)
+
+			if tt.err != nil {
+				assert.Error(t, err)
+				assert.EqualError(t, err, tt.err.Error())
+				return
+			}
+			assert.Nil(t, err)
+		})
+	}
+}
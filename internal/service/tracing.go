@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// tracer starts the spans of the service operations through the global tracer provider.
+var tracer = otel.Tracer("github.com/wizeline/CA-Microservices-Go/internal/service")
+
+// startSpan starts the span of a service operation, it is a child of the span carried by the context.
+// The returned function ends the span, recording the error the operation failed with. e.g. defer func() { end(err) }()
+func startSpan(ctx context.Context, name string) (context.Context, func(err error)) {
+	ctx, span := tracer.Start(ctx, name)
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
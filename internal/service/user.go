@@ -1,18 +1,19 @@
 package service
 
 import (
-	"fmt"
+	"context"
 	"time"
 
 	"github.com/wizeline/CA-Microservices-Go/internal/entity"
 )
 
 type UserRepo interface {
-	Create(user entity.User) error
-	Read(id uint64) (entity.User, error)
-	ReadAll() ([]entity.User, error)
-	Update(user entity.User) error
-	Delete(id uint64) error
+	Create(ctx context.Context, user entity.User) error
+	Read(ctx context.Context, id uint64) (entity.User, error)
+	ReadAll(ctx context.Context) ([]entity.User, error)
+	Update(ctx context.Context, user entity.User) error
+	Delete(ctx context.Context, id uint64) error
+	Search(ctx context.Context, q entity.UserQuery) (entity.UserPage, error)
 }
 
 type UserResponse struct {
@@ -22,6 +23,17 @@ type UserResponse struct {
 	Email     string
 	BirthDay  time.Time
 	Username  string
+	Role      string
+	Active    bool
+}
+
+type UserSearchResponse struct {
+	Users      []UserResponse
+	Total      int
+	Limit      int
+	Offset     int
+	NextCursor string
+	PrevCursor string
 }
 
 type UserCreateArgs struct {
@@ -46,51 +58,101 @@ type UserLoginResponse struct {
 	LastName  string
 	Email     string
 	Username  string
+	Role      string
 	LastLogin time.Time
 }
 
+// unknownUserPasswdHash is compared when no user matches the login username,
+// so the login of an unknown user takes as long as the one of a wrong password.
+const unknownUserPasswdHash = "$2a$10$ZY.VehZqy3qyY9JxxbWAT.vwR9gEhPhJ7geyGkA0VqPkUpxHwJ7D6"
+
+// Password hashing operations measured by the UserMetrics.
+const (
+	PasswdHashOp    = "hash"
+	PasswdCompareOp = "compare"
+)
+
+// UserMetrics records the measures of the user operations.
+type UserMetrics interface {
+	// ObservePasswdHash records the time spent by a password hashing operation. e.g. PasswdHashOp
+	ObservePasswdHash(operation string, elapsed time.Duration)
+	// ObserveLogin records the outcome of a login attempt.
+	ObserveLogin(success bool)
+}
+
+// nopUserMetrics discards the measures, it is the UserMetrics of a UserService until WithMetrics is called.
+type nopUserMetrics struct{}
+
+func (nopUserMetrics) ObservePasswdHash(string, time.Duration) {}
+
+func (nopUserMetrics) ObserveLogin(bool) {}
+
 type UserService struct {
-	repo UserRepo
+	repo         UserRepo
+	roles        RoleRepo
+	cursorSecret []byte
+	metrics      UserMetrics
 }
 
-func NewUserService(repo UserRepo) UserService {
+// NewUserService returns a UserService, the roles given to the users are looked up in the roles repository
+// and the search cursors are signed with the cursorSecret.
+func NewUserService(repo UserRepo, roles RoleRepo, cursorSecret string) UserService {
 	return UserService{
-		repo: repo,
+		repo:         repo,
+		roles:        roles,
+		cursorSecret: []byte(cursorSecret),
+		metrics:      nopUserMetrics{},
 	}
 }
 
-func (s UserService) Create(args UserCreateArgs) error {
+// WithMetrics returns a copy of the UserService recording its measures in the given UserMetrics.
+func (s UserService) WithMetrics(m UserMetrics) UserService {
+	s.metrics = m
+	return s
+}
+
+func (s UserService) Create(ctx context.Context, args UserCreateArgs) (err error) {
+	ctx, end := startSpan(ctx, "UserService.Create")
+	defer func() { end(err) }()
+
 	if err := validateUserCreate(args); err != nil {
 		return err
 	}
-	hashedPwd, err := hashPasswd(args.Passwd)
+	hashedPwd, err := s.hashPasswd(args.Passwd)
 	if err != nil {
 		return err
 	}
-	return s.repo.Create(entity.User{
+	return s.repo.Create(ctx, entity.User{
 		FirstName: args.FirstName,
 		LastName:  args.LastName,
 		Email:     args.Email,
 		BirthDay:  args.BirthDay,
 		Username:  args.Username,
 		Passwd:    hashedPwd,
+		Role:      entity.RoleUser,
 	})
 }
 
-func (s UserService) Get(id uint64) (UserResponse, error) {
+func (s UserService) Get(ctx context.Context, id uint64) (_ UserResponse, err error) {
+	ctx, end := startSpan(ctx, "UserService.Get")
+	defer func() { end(err) }()
+
 	if id == 0 {
-		return UserResponse{}, ErrZeroValue
+		return UserResponse{}, &InvalidInputErr{Field: "id", Err: ErrZeroValue}
 	}
 
-	user, err := s.repo.Read(id)
+	user, err := s.repo.Read(ctx, id)
 	if err != nil {
 		return UserResponse{}, err
 	}
 	return parseUserResp(user), nil
 }
 
-func (s UserService) GetAll() ([]UserResponse, error) {
-	users, err := s.repo.ReadAll()
+func (s UserService) GetAll(ctx context.Context) (_ []UserResponse, err error) {
+	ctx, end := startSpan(ctx, "UserService.GetAll")
+	defer func() { end(err) }()
+
+	users, err := s.repo.ReadAll(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -102,45 +164,113 @@ func (s UserService) GetAll() ([]UserResponse, error) {
 	return usersResp, nil
 }
 
-func (s UserService) Find(filter, value string) ([]entity.User, error) {
+func (s UserService) Find(ctx context.Context, filter, value string) (_ []entity.User, err error) {
+	ctx, end := startSpan(ctx, "UserService.Find")
+	defer func() { end(err) }()
+
 	if err := validateUserFilter(filter); err != nil {
 		return nil, err
 	}
-	users, err := s.repo.ReadAll()
+	page, err := s.repo.Search(ctx, entity.UserQuery{
+		Filters: []entity.Filter{{Field: userFilterFields[filter], Op: entity.FilterEq, Value: value}},
+	})
 	if err != nil {
 		return nil, err
 	}
+	return page.Users, nil
+}
+
+// Search returns a page of the users matching the query. The page starts after the position of the cursor given,
+// or before it when the cursor points backward. An empty cursor starts at the query offset.
+func (s UserService) Search(ctx context.Context, query entity.UserQuery, cursor string) (_ UserSearchResponse, err error) {
+	ctx, end := startSpan(ctx, "UserService.Search")
+	defer func() { end(err) }()
 
-	filteredUsers := make([]entity.User, 0)
-	for _, user := range users {
-		switch {
-		case filter == "FirstName":
-			if user.FirstName == value {
-				filteredUsers = append(filteredUsers, user)
-			}
-		case filter == "LastName":
-			if user.LastName == value {
-				filteredUsers = append(filteredUsers, user)
-			}
-		case filter == "Email":
-			if user.Email == value {
-				filteredUsers = append(filteredUsers, user)
-			}
-		case filter == "Username":
-			if user.Username == value {
-				filteredUsers = append(filteredUsers, user)
-			}
+	if cursor != "" && query.Offset > 0 {
+		return UserSearchResponse{}, &InvalidInputErr{Field: "offset", Err: ErrNotSupported}
+	}
+	query, err = parseUserQuery(query)
+	if err != nil {
+		return UserSearchResponse{}, err
+	}
+	fingerprint := queryFingerprint(query)
+	if cursor != "" {
+		payload, err := decodeCursor(s.cursorSecret, cursor)
+		if err != nil {
+			return UserSearchResponse{}, &InvalidInputErr{Field: "cursor", Err: err}
 		}
+		if payload.Query != fingerprint {
+			return UserSearchResponse{}, &InvalidInputErr{Field: "cursor", Err: ErrCursorInvalid}
+		}
+		values, err := parseCursorValues(query.Sort, payload.Values)
+		if err != nil {
+			return UserSearchResponse{}, &InvalidInputErr{Field: "cursor", Err: err}
+		}
+		query.Cursor = &entity.Cursor{Values: values, Backward: payload.Backward}
+	}
+
+	// The extra user tells whether there is another page past this one.
+	limit := query.Limit
+	query.Limit++
+	page, err := s.repo.Search(ctx, query)
+	if err != nil {
+		return UserSearchResponse{}, err
+	}
 
+	users := page.Users
+	backward := query.Cursor != nil && query.Cursor.Backward
+	more := len(users) > limit
+	if more && backward {
+		users = users[len(users)-limit:]
+	} else if more {
+		users = users[:limit]
+	}
+
+	resp := UserSearchResponse{
+		Users:  make([]UserResponse, 0, len(users)),
+		Total:  page.Total,
+		Limit:  limit,
+		Offset: query.Offset,
+	}
+	for _, u := range users {
+		resp.Users = append(resp.Users, parseUserResp(u))
+	}
+	if len(users) == 0 {
+		return resp, nil
 	}
-	return filteredUsers, nil
+
+	hasNext := more || backward
+	hasPrev := (more && backward) || (!backward && (query.Cursor != nil || query.Offset > 0))
+	if hasNext {
+		resp.NextCursor, err = encodeCursor(s.cursorSecret, cursorPayload{
+			Query:  fingerprint,
+			Values: cursorValues(query.Sort, users[len(users)-1]),
+		})
+		if err != nil {
+			return UserSearchResponse{}, err
+		}
+	}
+	if hasPrev {
+		resp.PrevCursor, err = encodeCursor(s.cursorSecret, cursorPayload{
+			Query:    fingerprint,
+			Values:   cursorValues(query.Sort, users[0]),
+			Backward: true,
+		})
+		if err != nil {
+			return UserSearchResponse{}, err
+		}
+	}
+	return resp, nil
 }
 
-func (s UserService) Update(args UserUpdateArgs) error {
+func (s UserService) Update(ctx context.Context, args UserUpdateArgs) (err error) {
+	ctx, end := startSpan(ctx, "UserService.Update")
+	defer func() { end(err) }()
+
 	if err := validateUserUpdate(args); err != nil {
 		return err
 	}
-	user, err := s.repo.Read(args.ID)
+	user, err := s.repo.Read(ctx, args.ID)
 	if err != nil {
 		return err
 	}
@@ -154,29 +284,53 @@ func (s UserService) Update(args UserUpdateArgs) error {
 	if !args.BirthDay.IsZero() {
 		user.BirthDay = args.BirthDay
 	}
-	return s.repo.Update(user)
+	return s.repo.Update(ctx, user)
 }
 
-func (s UserService) Delete(id uint64) error {
+func (s UserService) Delete(ctx context.Context, id uint64) (err error) {
+	ctx, end := startSpan(ctx, "UserService.Delete")
+	defer func() { end(err) }()
+
 	if id == 0 {
 		return &InvalidInputErr{Field: "id", Err: ErrZeroValue}
 	}
-	return s.repo.Delete(id)
+	return s.repo.Delete(ctx, id)
 }
 
-func (s UserService) Activate(id uint64) error {
+func (s UserService) Activate(ctx context.Context, id uint64) (err error) {
+	ctx, end := startSpan(ctx, "UserService.Activate")
+	defer func() { end(err) }()
+
 	if id == 0 {
 		return &InvalidInputErr{Field: "id", Err: ErrZeroValue}
 	}
-	user, err := s.repo.Read(id)
+	user, err := s.repo.Read(ctx, id)
 	if err != nil {
 		return err
 	}
 	user.Active = true
-	return s.repo.Update(user)
+	return s.repo.Update(ctx, user)
+}
+
+func (s UserService) Deactivate(ctx context.Context, id uint64) (err error) {
+	ctx, end := startSpan(ctx, "UserService.Deactivate")
+	defer func() { end(err) }()
+
+	if id == 0 {
+		return &InvalidInputErr{Field: "id", Err: ErrZeroValue}
+	}
+	user, err := s.repo.Read(ctx, id)
+	if err != nil {
+		return err
+	}
+	user.Active = false
+	return s.repo.Update(ctx, user)
 }
 
-func (s UserService) ChangeEmail(id uint64, email string) error {
+func (s UserService) ChangeEmail(ctx context.Context, id uint64, email string) (err error) {
+	ctx, end := startSpan(ctx, "UserService.ChangeEmail")
+	defer func() { end(err) }()
+
 	if id == 0 {
 		return &InvalidInputErr{Field: "id", Err: ErrZeroValue}
 	}
@@ -184,55 +338,123 @@ func (s UserService) ChangeEmail(id uint64, email string) error {
 		return &InvalidInputErr{Field: "email", Err: err}
 	}
 
-	user, err := s.repo.Read(id)
+	user, err := s.repo.Read(ctx, id)
 	if err != nil {
 		return err
 	}
 	user.Email = email
-	return s.repo.Update(user)
+	return s.repo.Update(ctx, user)
 }
 
-func (s UserService) ChangePasswd(id uint64, passwd string) error {
+func (s UserService) ChangePasswd(ctx context.Context, id uint64, currentPasswd, passwd string) (err error) {
+	ctx, end := startSpan(ctx, "UserService.ChangePasswd")
+	defer func() { end(err) }()
+
+	if currentPasswd == "" {
+		return &InvalidInputErr{Field: "currentPasswd", Err: ErrEmptyValue}
+	}
 	if err := validateUserPasswd(passwd); err != nil {
-		return err
+		return &InvalidInputErr{Field: "passwd", Err: err}
 	}
-	user, err := s.repo.Read(id)
+	user, err := s.repo.Read(ctx, id)
 	if err != nil {
 		return err
 	}
+	if err := s.compareHashAndPassword(user.Passwd, currentPasswd); err != nil {
+		return &InvalidInputErr{Field: "currentPasswd", Err: ErrPasswdDoNotMatch}
+	}
 
-	hashedPasswd, err := hashPasswd(passwd)
+	hashedPasswd, err := s.hashPasswd(passwd)
 	if err != nil {
 		return err
 	}
 	user.Passwd = hashedPasswd
-	return s.repo.Update(user)
+	return s.repo.Update(ctx, user)
 }
 
-func (s UserService) IsActive(id uint64) (bool, error) {
-	user, err := s.repo.Read(id)
+func (s UserService) ChangeRole(ctx context.Context, id uint64, role string) (err error) {
+	ctx, end := startSpan(ctx, "UserService.ChangeRole")
+	defer func() { end(err) }()
+
+	if id == 0 {
+		return &InvalidInputErr{Field: "id", Err: ErrZeroValue}
+	}
+	if err := validateRoleName(role); err != nil {
+		return &InvalidInputErr{Field: "role", Err: err}
+	}
+	// The role is looked up first, so every backend rejects an unknown role alike.
+	if _, err := s.roles.Read(ctx, role); err != nil {
+		if isNotFound(err) {
+			return &InvalidInputErr{Field: "role", Err: ErrRoleUnknown}
+		}
+		return err
+	}
+	user, err := s.repo.Read(ctx, id)
+	if err != nil {
+		return err
+	}
+	user.Role = role
+	return s.repo.Update(ctx, user)
+}
+
+func (s UserService) IsActive(ctx context.Context, id uint64) (_ bool, err error) {
+	ctx, end := startSpan(ctx, "UserService.IsActive")
+	defer func() { end(err) }()
+
+	user, err := s.repo.Read(ctx, id)
 	if err != nil {
 		return false, err
 	}
 	return user.Active, nil
 }
 
-func (s UserService) ValidateLogin(username string, passwd string) (UserLoginResponse, error) {
+// UserRole returns the current role of the user and whether the user is active.
+func (s UserService) UserRole(ctx context.Context, id uint64) (_ string, _ bool, err error) {
+	ctx, end := startSpan(ctx, "UserService.UserRole")
+	defer func() { end(err) }()
+
+	user, err := s.repo.Read(ctx, id)
+	if isNotFound(err) {
+		// A deleted user is reported as inactive, so its access tokens are rejected.
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return user.Role, user.Active, nil
+}
+
+// ValidateLogin returns the user matching the credentials, every attempt is recorded as a login success or failure.
+func (s UserService) ValidateLogin(ctx context.Context, username string, passwd string) (_ UserLoginResponse, err error) {
+	ctx, end := startSpan(ctx, "UserService.ValidateLogin")
+	defer func() { end(err) }()
+
+	user, err := s.validateLogin(ctx, username, passwd)
+	s.metrics.ObserveLogin(err == nil)
+	return user, err
+}
+
+func (s UserService) validateLogin(ctx context.Context, username string, passwd string) (UserLoginResponse, error) {
 	if username == "" {
 		return UserLoginResponse{}, &InvalidInputErr{Field: "username", Err: ErrEmptyValue}
 	}
 	if err := validateUserPasswd(passwd); err != nil {
-		return UserLoginResponse{}, err
+		return UserLoginResponse{}, &InvalidInputErr{Field: "passwd", Err: err}
 	}
-	users, err := s.Find("Username", username)
+	users, err := s.Find(ctx, "Username", username)
 	if err != nil {
 		return UserLoginResponse{}, err
 	}
-	if total := len(users); total != 1 {
-		return UserLoginResponse{}, fmt.Errorf("expected one user got %d", total)
+	// The unknown users and the wrong passwords fail alike, so the callers can't tell which usernames exist.
+	if len(users) != 1 {
+		_ = s.compareHashAndPassword(unknownUserPasswdHash, passwd)
+		return UserLoginResponse{}, &UnauthorizedErr{Err: ErrInvalidCredentials}
 	}
-	if err := compareHashAndPassword(users[0].Passwd, passwd); err != nil {
-		return UserLoginResponse{}, ErrPasswdDoNotMatch
+	if err := s.compareHashAndPassword(users[0].Passwd, passwd); err != nil {
+		return UserLoginResponse{}, &UnauthorizedErr{Err: ErrInvalidCredentials}
+	}
+	if !users[0].Active {
+		return UserLoginResponse{}, &UnauthorizedErr{Err: ErrUserInactive}
 	}
 
 	return UserLoginResponse{
@@ -241,6 +463,23 @@ func (s UserService) ValidateLogin(username string, passwd string) (UserLoginRes
 		LastName:  users[0].LastName,
 		Email:     users[0].Email,
 		Username:  users[0].Username,
+		Role:      users[0].Role,
 		LastLogin: users[0].LastLogin.Time,
 	}, nil
 }
+
+// hashPasswd hashes the password, recording the time spent.
+func (s UserService) hashPasswd(passwd string) (string, error) {
+	defer s.observePasswdHash(PasswdHashOp, time.Now())
+	return hashPasswd(passwd)
+}
+
+// compareHashAndPassword compares the password with its hash, recording the time spent.
+func (s UserService) compareHashAndPassword(hashedPassword, passwd string) error {
+	defer s.observePasswdHash(PasswdCompareOp, time.Now())
+	return compareHashAndPassword(hashedPassword, passwd)
+}
+
+func (s UserService) observePasswdHash(operation string, start time.Time) {
+	s.metrics.ObservePasswdHash(operation, time.Since(start))
+}
@@ -1,11 +1,14 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+	"github.com/wizeline/CA-Microservices-Go/internal/repository"
 	"github.com/wizeline/CA-Microservices-Go/internal/service/mocks"
 
 	"github.com/stretchr/testify/assert"
@@ -18,6 +21,8 @@ var (
 	_ UserRepo = &mocks.UserRepo{}
 
 	errRepoTest = errors.New("some repo error")
+
+	testCursorSecret = "some-cursor-secret"
 )
 
 func TestUserService_Create(t *testing.T) {
@@ -31,6 +36,10 @@ func TestUserService_Create(t *testing.T) {
 		args UserCreateArgs
 		err  error
 	}{
+		{
+			name: "Empty arguments",
+			err:  &InvalidInputErr{Field: "args", Err: ErrEmptyArgs},
+		},
 		{
 			name: "Empty firstName",
 			args: UserCreateArgs{
@@ -129,10 +138,10 @@ func TestUserService_Create(t *testing.T) {
 			mockRepo := &mocks.UserRepo{}
 			// TODO: migrate the Create mocked function to validate the expected arguments to the repository. Currently, there are some issues due to the hashed password.
 			// mockRepo.On("Create", tt.repo.args).Return(tt.repo.err)
-			mockRepo.On("Create", mock.AnythingOfType("entity.User")).Return(tt.repo.err)
-			svc := NewUserService(mockRepo)
+			mockRepo.On("Create", mock.Anything, mock.AnythingOfType("entity.User")).Return(tt.repo.err)
+			svc := NewUserService(mockRepo, nil, testCursorSecret)
 
-			err := svc.Create(tt.args)
+			err := svc.Create(context.Background(), tt.args)
 
 			if tt.err != nil {
 				assert.Error(t, err)
@@ -165,7 +174,7 @@ func TestUserService_Get(t *testing.T) {
 			name: "ID zero value",
 			id:   0,
 			exp:  UserResponse{},
-			err:  ErrZeroValue,
+			err:  &InvalidInputErr{Field: "id", Err: ErrZeroValue},
 		},
 		{
 			name: "Repository error",
@@ -211,10 +220,10 @@ func TestUserService_Get(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			mockRepo := &mocks.UserRepo{}
-			mockRepo.On("Read", test.repo.id).Return(test.repo.resp.user, test.repo.resp.err)
-			svc := NewUserService(mockRepo)
+			mockRepo.On("Read", mock.Anything, test.repo.id).Return(test.repo.resp.user, test.repo.resp.err)
+			svc := NewUserService(mockRepo, nil, testCursorSecret)
 
-			out, err := svc.Get(test.id)
+			out, err := svc.Get(context.Background(), test.id)
 
 			if test.err != nil {
 				assert.Error(t, err)
@@ -272,10 +281,10 @@ func TestUserService_GetAll(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			mockRepo := mocks.NewUserRepo(t)
-			mockRepo.On("ReadAll").Return(test.repoResp.users, test.repoResp.err)
-			svc := NewUserService(mockRepo)
+			mockRepo.On("ReadAll", mock.Anything).Return(test.repoResp.users, test.repoResp.err)
+			svc := NewUserService(mockRepo, nil, testCursorSecret)
 
-			out, err := svc.GetAll()
+			out, err := svc.GetAll(context.Background())
 
 			if test.err != nil {
 				assert.Error(t, err)
@@ -312,7 +321,7 @@ func TestUserService_Update(t *testing.T) {
 		{
 			name: "No arguments",
 			args: UserUpdateArgs{},
-			err:  ErrEmptyArgs,
+			err:  &InvalidInputErr{Field: "args", Err: ErrEmptyArgs},
 		},
 		{
 			name: "ID zero value",
@@ -464,11 +473,11 @@ func TestUserService_Update(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			mockRepo := &mocks.UserRepo{}
-			mockRepo.On("Read", test.repoRead.id).Return(test.repoRead.resp.user, test.repoRead.resp.err)
-			mockRepo.On("Update", test.repoUpdate.args).Return(test.repoUpdate.err)
-			svc := NewUserService(mockRepo)
+			mockRepo.On("Read", mock.Anything, test.repoRead.id).Return(test.repoRead.resp.user, test.repoRead.resp.err)
+			mockRepo.On("Update", mock.Anything, test.repoUpdate.args).Return(test.repoUpdate.err)
+			svc := NewUserService(mockRepo, nil, testCursorSecret)
 
-			err := svc.Update(test.args)
+			err := svc.Update(context.Background(), test.args)
 
 			if test.err != nil {
 				assert.Error(t, err)
@@ -519,10 +528,10 @@ func TestUserService_Delete(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &mocks.UserRepo{}
-			mockRepo.On("Delete", tt.repo.id).Return(tt.repo.err)
-			svc := NewUserService(mockRepo)
+			mockRepo.On("Delete", mock.Anything, tt.repo.id).Return(tt.repo.err)
+			svc := NewUserService(mockRepo, nil, testCursorSecret)
 
-			err := svc.Delete(tt.id)
+			err := svc.Delete(context.Background(), tt.id)
 
 			if tt.err != nil {
 				assert.Error(t, err)
@@ -593,10 +602,10 @@ func TestUserService_IsActive(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := mocks.NewUserRepo(t)
-			mockRepo.On("Read", tt.repo.id).Return(tt.repo.resp.user, tt.repo.resp.err)
-			svc := NewUserService(mockRepo)
+			mockRepo.On("Read", mock.Anything, tt.repo.id).Return(tt.repo.resp.user, tt.repo.resp.err)
+			svc := NewUserService(mockRepo, nil, testCursorSecret)
 
-			out, err := svc.IsActive(tt.id)
+			out, err := svc.IsActive(context.Background(), tt.id)
 
 			if tt.err != nil {
 				assert.Error(t, err)
@@ -657,15 +666,88 @@ func TestUserService_Activate(t *testing.T) {
 	for _, tt := range testsCases {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := mocks.NewUserRepo(t)
-			svc := NewUserService(mockRepo)
+			svc := NewUserService(mockRepo, nil, testCursorSecret)
 
-			mockRepo.On("Read", tt.userID).Return(tt.user, tt.repoReadError)
+			mockRepo.On("Read", mock.Anything, tt.userID).Return(tt.user, tt.repoReadError)
 
 			if tt.repoReadError == nil {
-				mockRepo.On("Update", tt.userToStore).Return(tt.repoUpdateError)
+				mockRepo.On("Update", mock.Anything, tt.userToStore).Return(tt.repoUpdateError)
+			}
+
+			gotErr := svc.Activate(context.Background(), tt.userID)
+
+			if tt.wantErr != nil {
+				assert.Error(t, gotErr)
+				assert.EqualError(t, gotErr, tt.wantErr.Error())
+			} else {
+				assert.Nil(t, gotErr)
+			}
+		})
+	}
+}
+
+func TestUserService_Deactivate(t *testing.T) {
+	testsCases := []struct {
+		name            string
+		userID          uint64
+		repoReadError   error
+		repoUpdateError error
+		wantErr         error
+		user            entity.User
+		userToStore     entity.User
+	}{
+		{
+			name:    "ID zero value",
+			userID:  0,
+			wantErr: &InvalidInputErr{Field: "id", Err: ErrZeroValue},
+		},
+		{
+			name:   "Deactivate valid user",
+			userID: 1,
+			user: entity.User{
+				ID:     1,
+				Active: true,
+			},
+			userToStore: entity.User{
+				ID:     1,
+				Active: false,
+			},
+		},
+		{
+			name:          "Deactivate inexistent user",
+			userID:        1,
+			repoReadError: errors.New("mockRepo: user doesn't exists"),
+			wantErr:       errors.New("mockRepo: user doesn't exists"),
+		},
+		{
+			name:            "Can't deactivate user",
+			userID:          1,
+			repoUpdateError: errors.New("mockRepo: user can't be updated"),
+			wantErr:         errors.New("mockRepo: user can't be updated"),
+			user: entity.User{
+				ID:     1,
+				Active: true,
+			},
+			userToStore: entity.User{
+				ID:     1,
+				Active: false,
+			},
+		},
+	}
+
+	for _, tt := range testsCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewUserRepo(t)
+			svc := NewUserService(mockRepo, nil, testCursorSecret)
+
+			if tt.userID != 0 {
+				mockRepo.On("Read", mock.Anything, tt.userID).Return(tt.user, tt.repoReadError)
+			}
+			if tt.userID != 0 && tt.repoReadError == nil {
+				mockRepo.On("Update", mock.Anything, tt.userToStore).Return(tt.repoUpdateError)
 			}
 
-			gotErr := svc.Activate(tt.userID)
+			gotErr := svc.Deactivate(context.Background(), tt.userID)
 
 			if tt.wantErr != nil {
 				assert.Error(t, gotErr)
@@ -732,13 +814,13 @@ func TestUserService_ChangeEmail(t *testing.T) {
 	for _, tt := range testsCases {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &mocks.UserRepo{}
-			mockRepo.On("Read", tt.userID).Return(tt.user, tt.repoReadError)
+			mockRepo.On("Read", mock.Anything, tt.userID).Return(tt.user, tt.repoReadError)
 			if tt.repoReadError == nil {
-				mockRepo.On("Update", tt.userToStore).Return(tt.repoUpdateError)
+				mockRepo.On("Update", mock.Anything, tt.userToStore).Return(tt.repoUpdateError)
 			}
-			svc := NewUserService(mockRepo)
+			svc := NewUserService(mockRepo, nil, testCursorSecret)
 
-			gotErr := svc.ChangeEmail(tt.userID, tt.newEmail)
+			gotErr := svc.ChangeEmail(context.Background(), tt.userID, tt.newEmail)
 
 			if tt.wantErr != nil {
 				assert.Error(t, gotErr)
@@ -751,35 +833,42 @@ func TestUserService_ChangeEmail(t *testing.T) {
 }
 
 func TestUserService_ChangePasswd(t *testing.T) {
+	currentHash, err := bcrypt.GenerateFromPassword([]byte("pass123"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
 	type testcase struct {
 		name            string
 		userID          uint64
+		currentPasswd   string
 		newPasswd       string
 		repoReadError   error
 		user            entity.User
 		repoUpdateError error
-		userToStore     entity.User
 		wantErr         error
 	}
 	invalidPasswordTestCases := []testcase{
 		{
-			name:      "Can't change passwd of user to an invalid passwd",
-			userID:    1,
-			newPasswd: "p",
-			wantErr:   ErrInvalidPasswd,
-			user: entity.User{
-				ID:     1,
-				Passwd: "pass123",
-			},
+			name:          "Can't change passwd without the current passwd",
+			userID:        1,
+			currentPasswd: "",
+			newPasswd:     "newpass",
+			wantErr:       &InvalidInputErr{Field: "currentPasswd", Err: ErrEmptyValue},
+		},
+		{
+			name:          "Can't change passwd of user to an invalid passwd",
+			userID:        1,
+			currentPasswd: "pass123",
+			newPasswd:     "p",
+			wantErr:       &InvalidInputErr{Field: "passwd", Err: ErrInvalidPasswd},
 		},
 	}
 
 	for _, tt := range invalidPasswordTestCases {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := mocks.NewUserRepo(t)
-			svc := NewUserService(mockRepo)
+			svc := NewUserService(mockRepo, nil, testCursorSecret)
 
-			gotErr := svc.ChangePasswd(tt.userID, tt.newPasswd)
+			gotErr := svc.ChangePasswd(context.Background(), tt.userID, tt.currentPasswd, tt.newPasswd)
 
 			assert.Error(t, gotErr)
 			assert.EqualError(t, gotErr, tt.wantErr.Error())
@@ -788,37 +877,44 @@ func TestUserService_ChangePasswd(t *testing.T) {
 
 	updatePasswordTestCases := []testcase{
 		{
-			name:      "Change passwd of valid user",
-			userID:    1,
-			newPasswd: "newpass",
+			name:          "Change passwd of valid user",
+			userID:        1,
+			currentPasswd: "pass123",
+			newPasswd:     "newpass",
 			user: entity.User{
 				ID:     1,
-				Passwd: "pass123",
+				Passwd: string(currentHash),
 			},
-			userToStore: entity.User{
+		},
+		{
+			name:          "Current passwd does not match",
+			userID:        1,
+			currentPasswd: "wrongpass",
+			newPasswd:     "newpass",
+			user: entity.User{
 				ID:     1,
-				Passwd: "newpass",
+				Passwd: string(currentHash),
 			},
+			wantErr: &InvalidInputErr{Field: "currentPasswd", Err: ErrPasswdDoNotMatch},
 		},
 		{
-			name:   "Repository fails to get user",
-			userID: 1, newPasswd: "newpass",
+			name:          "Repository fails to get user",
+			userID:        1,
+			currentPasswd: "pass123",
+			newPasswd:     "newpass",
 			repoReadError: errors.New("mockRepo: user doesn't exists"),
 			wantErr:       errors.New("mockRepo: user doesn't exists"),
 		},
 		{
 			name:            "Repository fails to update user",
 			userID:          1,
+			currentPasswd:   "pass123",
 			newPasswd:       "newpass",
 			repoUpdateError: errors.New("mockRepo: user can't be updated"),
 			wantErr:         errors.New("mockRepo: user can't be updated"),
 			user: entity.User{
 				ID:     1,
-				Passwd: "pass123",
-			},
-			userToStore: entity.User{
-				ID:     1,
-				Passwd: "newpass",
+				Passwd: string(currentHash),
 			},
 		},
 	}
@@ -826,18 +922,18 @@ func TestUserService_ChangePasswd(t *testing.T) {
 	for _, tt := range updatePasswordTestCases {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := mocks.NewUserRepo(t)
-			mockRepo.On("Read", tt.userID).Return(tt.user, tt.repoReadError)
-			if tt.repoReadError == nil {
-				mockRepo.On("Update", mock.AnythingOfType("entity.User")).Return(tt.repoUpdateError).Once().Run(func(args mock.Arguments) {
-					userArg := args.Get(0).(entity.User)
+			mockRepo.On("Read", mock.Anything, tt.userID).Return(tt.user, tt.repoReadError)
+			if tt.repoReadError == nil && tt.currentPasswd == "pass123" {
+				mockRepo.On("Update", mock.Anything, mock.AnythingOfType("entity.User")).Return(tt.repoUpdateError).Once().Run(func(args mock.Arguments) {
+					userArg := args.Get(1).(entity.User)
 
 					assert.Equal(t, tt.userID, userArg.ID)
 					assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(userArg.Passwd), []byte(tt.newPasswd)))
 				})
 			}
-			svc := NewUserService(mockRepo)
+			svc := NewUserService(mockRepo, nil, testCursorSecret)
 
-			gotErr := svc.ChangePasswd(tt.userID, tt.newPasswd)
+			gotErr := svc.ChangePasswd(context.Background(), tt.userID, tt.currentPasswd, tt.newPasswd)
 
 			if tt.wantErr != nil {
 				assert.Error(t, gotErr)
@@ -853,6 +949,7 @@ func TestUserService_Find(t *testing.T) {
 	type testcase struct {
 		name      string
 		filter    string
+		field     string
 		value     string
 		users     []entity.User
 		repoErr   error
@@ -869,6 +966,7 @@ func TestUserService_Find(t *testing.T) {
 			name:   "Find by FirstName",
 			users:  users,
 			filter: "FirstName",
+			field:  entity.UserFieldFirstName,
 			value:  "Lisa",
 			wantUsers: []entity.User{
 				{
@@ -884,6 +982,7 @@ func TestUserService_Find(t *testing.T) {
 			name:   "Find by LastName",
 			users:  users,
 			filter: "LastName",
+			field:  entity.UserFieldLastName,
 			value:  "LastnameB",
 			wantUsers: []entity.User{
 				{
@@ -899,6 +998,7 @@ func TestUserService_Find(t *testing.T) {
 			name:   "Find by Email",
 			users:  users,
 			filter: "Email",
+			field:  entity.UserFieldEmail,
 			value:  "juan1@email.com",
 			wantUsers: []entity.User{
 				{
@@ -914,6 +1014,7 @@ func TestUserService_Find(t *testing.T) {
 			name:   "Find by Username",
 			users:  users,
 			filter: "Username",
+			field:  entity.UserFieldUsername,
 			value:  "joan1",
 			wantUsers: []entity.User{
 				{
@@ -930,6 +1031,7 @@ func TestUserService_Find(t *testing.T) {
 			users:     []entity.User{},
 			repoErr:   errors.New("repo failed to fetch users"),
 			filter:    "LastName",
+			field:     entity.UserFieldLastName,
 			value:     "lisa1",
 			wantUsers: []entity.User{},
 			wantErr:   errors.New("repo failed to fetch users"),
@@ -939,10 +1041,12 @@ func TestUserService_Find(t *testing.T) {
 	for _, tt := range findUsersByFiltersTestCases {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := mocks.NewUserRepo(t)
-			mockRepo.On("ReadAll").Return(tt.users, tt.repoErr)
-			svc := NewUserService(mockRepo)
+			mockRepo.On("Search", mock.Anything, entity.UserQuery{
+				Filters: []entity.Filter{{Field: tt.field, Op: entity.FilterEq, Value: tt.value}},
+			}).Return(entity.UserPage{Users: tt.wantUsers, Total: len(tt.wantUsers)}, tt.repoErr)
+			svc := NewUserService(mockRepo, nil, testCursorSecret)
 
-			out, err := svc.Find(tt.filter, tt.value)
+			out, err := svc.Find(context.Background(), tt.filter, tt.value)
 
 			if tt.wantErr != nil {
 				assert.Error(t, err)
@@ -971,9 +1075,9 @@ func TestUserService_Find(t *testing.T) {
 	for _, tt := range validateFiltersTestCases {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := mocks.NewUserRepo(t)
-			svc := NewUserService(mockRepo)
+			svc := NewUserService(mockRepo, nil, testCursorSecret)
 
-			gotUsers, gotErr := svc.Find(tt.filter, tt.value)
+			gotUsers, gotErr := svc.Find(context.Background(), tt.filter, tt.value)
 
 			assert.Error(t, gotErr)
 			assert.EqualError(t, gotErr, tt.wantErr.Error())
@@ -995,6 +1099,7 @@ func TestUserService_ValidateLogin(t *testing.T) {
 			ID:       1,
 			Username: "user1",
 			Passwd:   hashedPasswd,
+			Active:   true,
 		},
 	}
 
@@ -1011,10 +1116,10 @@ func TestUserService_ValidateLogin(t *testing.T) {
 			username: "user2",
 			password: userPasswd,
 			repoResp: repoResp{
-				users: users,
+				users: []entity.User{},
 				err:   nil,
 			},
-			err: errors.New("expected one user got 0"),
+			err: &UnauthorizedErr{Err: ErrInvalidCredentials},
 		},
 		{
 			name:     "Invalid password",
@@ -1025,7 +1130,16 @@ func TestUserService_ValidateLogin(t *testing.T) {
 				err:   nil,
 			},
 			exp: UserLoginResponse{},
-			err: ErrPasswdDoNotMatch,
+			err: &UnauthorizedErr{Err: ErrInvalidCredentials},
+		},
+		{
+			name:     "Inactive user",
+			username: "user3",
+			password: userPasswd,
+			repoResp: repoResp{
+				users: []entity.User{{ID: 3, Username: "user3", Passwd: hashedPasswd}},
+			},
+			err: &UnauthorizedErr{Err: ErrUserInactive},
 		},
 		{
 			name:     "Repository error",
@@ -1056,10 +1170,12 @@ func TestUserService_ValidateLogin(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			mockRepo := mocks.NewUserRepo(t)
-			mockRepo.On("ReadAll").Return(test.repoResp.users, test.repoResp.err)
-			svc := NewUserService(mockRepo)
+			mockRepo.On("Search", mock.Anything, entity.UserQuery{
+				Filters: []entity.Filter{{Field: entity.UserFieldUsername, Op: entity.FilterEq, Value: test.username}},
+			}).Return(entity.UserPage{Users: test.repoResp.users}, test.repoResp.err)
+			svc := NewUserService(mockRepo, nil, testCursorSecret)
 
-			out, err := svc.ValidateLogin(test.username, test.password)
+			out, err := svc.ValidateLogin(context.Background(), test.username, test.password)
 
 			if test.err != nil {
 				assert.Error(t, err)
@@ -1073,3 +1189,308 @@ func TestUserService_ValidateLogin(t *testing.T) {
 	}
 
 }
+
+func TestUserService_UserRole(t *testing.T) {
+	mockRepo := mocks.NewUserRepo(t)
+	mockRepo.On("Read", mock.Anything, uint64(1)).Return(entity.User{ID: 1, Role: entity.RoleAdmin, Active: true}, nil)
+	mockRepo.On("Read", mock.Anything, uint64(2)).Return(entity.User{}, errRepoTest)
+	mockRepo.On("Read", mock.Anything, uint64(3)).Return(entity.User{}, &repository.NotFoundErr{Entity: "user"})
+	svc := NewUserService(mockRepo, nil, testCursorSecret)
+
+	role, active, err := svc.UserRole(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.RoleAdmin, role)
+	assert.True(t, active)
+
+	_, _, err = svc.UserRole(context.Background(), 2)
+	assert.ErrorIs(t, err, errRepoTest)
+
+	_, active, err = svc.UserRole(context.Background(), 3)
+	assert.NoError(t, err, "a deleted user is not an error")
+	assert.False(t, active)
+}
+
+// userMetricsStub records the measures of a UserService.
+type userMetricsStub struct {
+	hashOps []string
+	logins  []bool
+}
+
+func (m *userMetricsStub) ObservePasswdHash(operation string, _ time.Duration) {
+	m.hashOps = append(m.hashOps, operation)
+}
+
+func (m *userMetricsStub) ObserveLogin(success bool) {
+	m.logins = append(m.logins, success)
+}
+
+func TestUserService_ValidateLogin_metrics(t *testing.T) {
+	hashedPasswd, err := hashPasswd("mypass")
+	assert.NoError(t, err)
+	mockRepo := mocks.NewUserRepo(t)
+	mockRepo.On("Search", mock.Anything, mock.Anything).
+		Return(entity.UserPage{Users: []entity.User{{ID: 1, Username: "user1", Passwd: hashedPasswd, Active: true}}}, nil)
+	metrics := &userMetricsStub{}
+	svc := NewUserService(mockRepo, nil, testCursorSecret).WithMetrics(metrics)
+
+	_, err = svc.ValidateLogin(context.Background(), "user1", "mypass")
+	assert.NoError(t, err)
+	_, err = svc.ValidateLogin(context.Background(), "user1", "pass567")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	_, err = svc.ValidateLogin(context.Background(), "", "mypass")
+	assert.Error(t, err)
+
+	assert.Equal(t, []bool{true, false, false}, metrics.logins, "every attempt is recorded")
+	assert.Equal(t, []string{PasswdCompareOp, PasswdCompareOp}, metrics.hashOps, "the hashes compared are measured")
+}
+
+func TestUserService_ChangeRole(t *testing.T) {
+	tests := []struct {
+		name            string
+		userID          uint64
+		role            string
+		roleReadError   error
+		repoReadError   error
+		repoUpdateError error
+		user            entity.User
+		userToStore     entity.User
+		wantErr         error
+	}{
+		{
+			name:    "ID zero value",
+			userID:  0,
+			role:    entity.RoleAdmin,
+			wantErr: &InvalidInputErr{Field: "id", Err: ErrZeroValue},
+		},
+		{
+			name:    "Invalid role",
+			userID:  1,
+			role:    "Super Admin",
+			wantErr: &InvalidInputErr{Field: "role", Err: ErrInvalidFormat},
+		},
+		{
+			name:          "Unknown role",
+			userID:        1,
+			role:          "auditor",
+			roleReadError: &repository.NotFoundErr{Entity: "role"},
+			wantErr:       &InvalidInputErr{Field: "role", Err: ErrRoleUnknown},
+		},
+		{
+			name:          "Repository fails to get role",
+			userID:        1,
+			role:          entity.RoleAdmin,
+			roleReadError: errRepoTest,
+			wantErr:       errRepoTest,
+		},
+		{
+			name:          "Repository fails to get user",
+			userID:        1,
+			role:          entity.RoleAdmin,
+			repoReadError: errRepoTest,
+			wantErr:       errRepoTest,
+		},
+		{
+			name:        "Role changed",
+			userID:      1,
+			role:        entity.RoleAdmin,
+			user:        entity.User{ID: 1, Role: entity.RoleUser},
+			userToStore: entity.User{ID: 1, Role: entity.RoleAdmin},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mocks.UserRepo{}
+			mockRepo.On("Read", mock.Anything, tt.userID).Return(tt.user, tt.repoReadError)
+			mockRepo.On("Update", mock.Anything, tt.userToStore).Return(tt.repoUpdateError)
+			mockRoles := &mocks.RoleRepo{}
+			mockRoles.On("Read", mock.Anything, tt.role).Return(entity.Role{Name: tt.role}, tt.roleReadError)
+			svc := NewUserService(mockRepo, mockRoles, testCursorSecret)
+
+			gotErr := svc.ChangeRole(context.Background(), tt.userID, tt.role)
+
+			if tt.wantErr != nil {
+				assert.Error(t, gotErr)
+				assert.EqualError(t, gotErr, tt.wantErr.Error())
+				return
+			}
+			assert.Nil(t, gotErr)
+		})
+	}
+}
+
+func TestUserService_Search(t *testing.T) {
+	birthDay := time.Date(1990, time.December, 5, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		query     entity.UserQuery
+		repoQuery entity.UserQuery
+		repoPage  entity.UserPage
+		repoErr   error
+		exp       UserSearchResponse
+		prev      bool
+		err       error
+	}{
+		{
+			name:  "Limit out of range",
+			query: entity.UserQuery{Limit: maxSearchLimit + 1},
+			err:   &InvalidInputErr{Field: "limit", Err: ErrOutOfRange},
+		},
+		{
+			name:  "Negative offset",
+			query: entity.UserQuery{Offset: -1},
+			err:   &InvalidInputErr{Field: "offset", Err: ErrOutOfRange},
+		},
+		{
+			name:  "Offset and cursor",
+			query: entity.UserQuery{Offset: 10, Cursor: &entity.Cursor{Values: []any{uint64(1)}}},
+			err:   &InvalidInputErr{Field: "offset", Err: ErrNotSupported},
+		},
+		{
+			name: "Unsupported filter field",
+			query: entity.UserQuery{
+				Filters: []entity.Filter{{Field: "passwd", Op: entity.FilterEq, Value: "secret"}},
+			},
+			err: &InvalidFilterErr{Filter: "passwd", Err: ErrNotSupported},
+		},
+		{
+			name: "Unsupported filter operation",
+			query: entity.UserQuery{
+				Filters: []entity.Filter{{Field: entity.UserFieldRole, Op: entity.FilterPrefix, Value: "ad"}},
+			},
+			err: &InvalidFilterErr{Filter: "role[prefix]", Err: ErrNotSupported},
+		},
+		{
+			name: "Invalid filter value",
+			query: entity.UserQuery{
+				Filters: []entity.Filter{{Field: entity.UserFieldBirthDay, Op: entity.FilterGte, Value: "05/12/1990"}},
+			},
+			err: &InvalidFilterErr{Filter: "birthday", Err: ErrInvalidFormat},
+		},
+		{
+			name: "Unsupported sort field",
+			query: entity.UserQuery{
+				Sort: []entity.Sort{{Field: entity.UserFieldActive}},
+			},
+			err: &InvalidInputErr{Field: "sort", Err: fmt.Errorf("active %w", ErrNotSupported)},
+		},
+		{
+			name:  "Repository error",
+			query: entity.UserQuery{},
+			repoQuery: entity.UserQuery{
+				Filters: []entity.Filter{},
+				Sort:    []entity.Sort{{Field: entity.UserFieldID}},
+				Limit:   defaultSearchLimit + 1,
+			},
+			repoErr: errRepoTest,
+			err:     errRepoTest,
+		},
+		{
+			name: "Found",
+			query: entity.UserQuery{
+				Filters: []entity.Filter{
+					{Field: entity.UserFieldFirstName, Op: entity.FilterPrefix, Value: "fo"},
+					{Field: entity.UserFieldActive, Op: entity.FilterEq, Value: "true"},
+					{Field: entity.UserFieldBirthDay, Op: entity.FilterLte, Value: "1990-12-05"},
+				},
+				Sort:   []entity.Sort{{Field: entity.UserFieldLastName, Desc: true}},
+				Limit:  1,
+				Offset: 1,
+			},
+			repoQuery: entity.UserQuery{
+				Filters: []entity.Filter{
+					{Field: entity.UserFieldFirstName, Op: entity.FilterPrefix, Value: "fo"},
+					{Field: entity.UserFieldActive, Op: entity.FilterEq, Value: true},
+					{Field: entity.UserFieldBirthDay, Op: entity.FilterLte, Value: birthDay},
+				},
+				Sort:   []entity.Sort{{Field: entity.UserFieldLastName, Desc: true}, {Field: entity.UserFieldID}},
+				Limit:  2,
+				Offset: 1,
+			},
+			repoPage: entity.UserPage{
+				Users: []entity.User{{ID: 2, FirstName: "foo", Passwd: "hash"}},
+				Total: 2,
+			},
+			exp: UserSearchResponse{
+				Users:  []UserResponse{{ID: 2, FirstName: "foo"}},
+				Total:  2,
+				Limit:  1,
+				Offset: 1,
+			},
+			prev: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mocks.UserRepo{}
+			mockRepo.On("Search", mock.Anything, tt.repoQuery).Return(tt.repoPage, tt.repoErr)
+			svc := NewUserService(mockRepo, nil, testCursorSecret)
+
+			out, err := svc.Search(context.Background(), tt.query, "")
+
+			if tt.err != nil {
+				assert.Error(t, err)
+				assert.EqualError(t, err, tt.err.Error())
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.prev, out.PrevCursor != "")
+			out.PrevCursor = ""
+			assert.Equal(t, tt.exp, out)
+		})
+	}
+}
+
+func TestUserService_Search_cursor(t *testing.T) {
+	users := []entity.User{{ID: 1, LastName: "a"}, {ID: 2, LastName: "b"}, {ID: 3, LastName: "b"}, {ID: 4, LastName: "c"}}
+	sorts := []entity.Sort{{Field: entity.UserFieldLastName}, {Field: entity.UserFieldID}}
+	query := entity.UserQuery{Sort: sorts[:1], Limit: 2}
+	ids := func(resp UserSearchResponse) []uint64 {
+		out := make([]uint64, 0, len(resp.Users))
+		for _, u := range resp.Users {
+			out = append(out, u.ID)
+		}
+		return out
+	}
+	searchQuery := func(cursor *entity.Cursor) entity.UserQuery {
+		return entity.UserQuery{Filters: []entity.Filter{}, Sort: sorts, Limit: 3, Cursor: cursor}
+	}
+
+	mockRepo := mocks.NewUserRepo(t)
+	svc := NewUserService(mockRepo, nil, testCursorSecret)
+
+	// First page: there is a next page and no previous one.
+	mockRepo.On("Search", mock.Anything, searchQuery(nil)).Return(entity.UserPage{Users: users[:3], Total: 4}, nil).Once()
+	first, err := svc.Search(context.Background(), query, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2}, ids(first))
+	assert.NotEmpty(t, first.NextCursor)
+	assert.Empty(t, first.PrevCursor)
+
+	// Second page: the cursor points after the last user of the first page.
+	mockRepo.On("Search", mock.Anything, searchQuery(&entity.Cursor{Values: []any{"b", uint64(2)}})).Return(entity.UserPage{Users: users[2:], Total: 4}, nil).Once()
+	second, err := svc.Search(context.Background(), query, first.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{3, 4}, ids(second))
+	assert.Empty(t, second.NextCursor)
+	assert.NotEmpty(t, second.PrevCursor)
+
+	// Back to the first page: the cursor points before the first user of the second page.
+	mockRepo.On("Search", mock.Anything, searchQuery(&entity.Cursor{Values: []any{"b", uint64(3)}, Backward: true})).Return(entity.UserPage{Users: users[:2], Total: 4}, nil).Once()
+	back, err := svc.Search(context.Background(), query, second.PrevCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2}, ids(back))
+	assert.NotEmpty(t, back.NextCursor)
+	assert.Empty(t, back.PrevCursor)
+
+	invalid := &InvalidInputErr{Field: "cursor", Err: ErrCursorInvalid}
+	_, err = svc.Search(context.Background(), query, first.NextCursor+"x")
+	assert.EqualError(t, err, invalid.Error(), "tampered cursor")
+	_, err = svc.Search(context.Background(), entity.UserQuery{Limit: 2}, first.NextCursor)
+	assert.EqualError(t, err, invalid.Error(), "cursor of another query")
+	_, err = NewUserService(mockRepo, nil, "another-secret").Search(context.Background(), query, first.NextCursor)
+	assert.EqualError(t, err, invalid.Error(), "cursor signed with another secret")
+	_, err = svc.Search(context.Background(), entity.UserQuery{Sort: sorts[:1], Offset: 2}, first.NextCursor)
+	assert.EqualError(t, err, (&InvalidInputErr{Field: "offset", Err: ErrNotSupported}).Error())
+}
@@ -1,10 +1,57 @@
 package service
 
-import "github.com/wizeline/CA-Microservices-Go/internal/entity"
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+var (
+	textFilterOps = []entity.FilterOp{entity.FilterEq, entity.FilterPrefix, entity.FilterContains}
+	timeFilterOps = []entity.FilterOp{entity.FilterEq, entity.FilterGte, entity.FilterLte}
+
+	// userFilterOps lists the filter operations supported by each searchable user field.
+	userFilterOps = map[string][]entity.FilterOp{
+		entity.UserFieldFirstName: textFilterOps,
+		entity.UserFieldLastName:  textFilterOps,
+		entity.UserFieldEmail:     textFilterOps,
+		entity.UserFieldUsername:  textFilterOps,
+		entity.UserFieldRole:      {entity.FilterEq},
+		entity.UserFieldActive:    {entity.FilterEq},
+		entity.UserFieldBirthDay:  timeFilterOps,
+		entity.UserFieldCreatedAt: timeFilterOps,
+	}
+
+	userSortFields = []string{
+		entity.UserFieldID,
+		entity.UserFieldFirstName,
+		entity.UserFieldLastName,
+		entity.UserFieldEmail,
+		entity.UserFieldUsername,
+		entity.UserFieldBirthDay,
+		entity.UserFieldCreatedAt,
+	}
+
+	// userFilterFields maps the filters supported by Find to the searchable user fields.
+	userFilterFields = map[string]string{
+		"FirstName": entity.UserFieldFirstName,
+		"LastName":  entity.UserFieldLastName,
+		"Email":     entity.UserFieldEmail,
+		"Username":  entity.UserFieldUsername,
+	}
+)
 
 func validateUserCreate(u UserCreateArgs) error {
 	if u == (UserCreateArgs{}) {
-		return ErrEmptyArgs
+		return &InvalidInputErr{Field: "args", Err: ErrEmptyArgs}
 	}
 	if u.FirstName == "" {
 		return &InvalidInputErr{Field: "FirstName", Err: ErrEmptyValue}
@@ -26,7 +73,7 @@ func validateUserCreate(u UserCreateArgs) error {
 
 func validateUserUpdate(user UserUpdateArgs) error {
 	if user == (UserUpdateArgs{}) {
-		return ErrEmptyArgs
+		return &InvalidInputErr{Field: "args", Err: ErrEmptyArgs}
 	}
 	if user.ID == 0 {
 		return &InvalidInputErr{Field: "ID", Err: ErrZeroValue}
@@ -61,5 +108,91 @@ func parseUserResp(user entity.User) UserResponse {
 		Email:     user.Email,
 		BirthDay:  user.BirthDay,
 		Username:  user.Username,
+		Role:      user.Role,
+		Active:    user.Active,
+	}
+}
+
+// parseUserQuery validates the search query and returns it with typed filter values, the default limit and the ID tiebreaker set.
+func parseUserQuery(q entity.UserQuery) (entity.UserQuery, error) {
+	switch {
+	case q.Limit < 0 || q.Limit > maxSearchLimit:
+		return entity.UserQuery{}, &InvalidInputErr{Field: "limit", Err: ErrOutOfRange}
+	case q.Offset < 0:
+		return entity.UserQuery{}, &InvalidInputErr{Field: "offset", Err: ErrOutOfRange}
+	case q.Offset > 0 && q.Cursor != nil:
+		return entity.UserQuery{}, &InvalidInputErr{Field: "offset", Err: ErrNotSupported}
+	}
+	if q.Limit == 0 {
+		q.Limit = defaultSearchLimit
+	}
+
+	filters := make([]entity.Filter, 0, len(q.Filters))
+	for _, f := range q.Filters {
+		ops, ok := userFilterOps[f.Field]
+		if !ok {
+			return entity.UserQuery{}, &InvalidFilterErr{Filter: f.Field, Err: ErrNotSupported}
+		}
+		if !slices.Contains(ops, f.Op) {
+			return entity.UserQuery{}, &InvalidFilterErr{Filter: fmt.Sprintf("%s[%s]", f.Field, f.Op), Err: ErrNotSupported}
+		}
+		value, err := parseFilterValue(f.Field, f.Value)
+		if err != nil {
+			return entity.UserQuery{}, &InvalidFilterErr{Filter: f.Field, Err: err}
+		}
+		filters = append(filters, entity.Filter{Field: f.Field, Op: f.Op, Value: value})
+	}
+	q.Filters = filters
+
+	sorts := make([]entity.Sort, 0, len(q.Sort)+1)
+	sortedByID := false
+	for _, srt := range q.Sort {
+		if !slices.Contains(userSortFields, srt.Field) {
+			return entity.UserQuery{}, &InvalidInputErr{Field: "sort", Err: fmt.Errorf("%s %w", srt.Field, ErrNotSupported)}
+		}
+		sortedByID = sortedByID || srt.Field == entity.UserFieldID
+		sorts = append(sorts, srt)
+	}
+	// The ID breaks the ties of the sort fields, so the order is total and the cursors are stable.
+	if !sortedByID {
+		sorts = append(sorts, entity.Sort{Field: entity.UserFieldID})
+	}
+	q.Sort = sorts
+	return q, nil
+}
+
+// parseFilterValue converts the filter value to the type of the field, the string values are parsed.
+func parseFilterValue(field string, value any) (any, error) {
+	switch field {
+	case entity.UserFieldActive:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			active, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, ErrInvalidFormat
+			}
+			return active, nil
+		}
+	case entity.UserFieldBirthDay, entity.UserFieldCreatedAt:
+		switch v := value.(type) {
+		case time.Time:
+			return v, nil
+		case string:
+			if t, err := time.Parse(time.RFC3339, v); err == nil {
+				return t, nil
+			}
+			t, err := time.Parse(time.DateOnly, v)
+			if err != nil {
+				return nil, ErrInvalidFormat
+			}
+			return t, nil
+		}
+	default:
+		if v, ok := value.(string); ok {
+			return v, nil
+		}
 	}
+	return nil, ErrInvalidFormat
 }
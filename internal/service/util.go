@@ -1,11 +1,21 @@
 package service
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"regexp"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Size in bytes of the random values used to generate tokens.
+const (
+	refreshTokenSize = 32
+	familyIDSize     = 16
+)
+
 func validateEmail(email string) error {
 	if email == "" {
 		return ErrEmptyValue
@@ -30,3 +40,18 @@ func hashPasswd(passwd string) (string, error) {
 func compareHashAndPassword(hashedPassword, passwd string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(passwd))
 }
+
+// randomToken returns a URL-safe string encoding size random bytes
+func randomToken(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken returns the hex encoded SHA-256 digest of the token, which is the only form the tokens are stored
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,107 @@
+// Package tracing sets up the OpenTelemetry distributed tracing of the service.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// The supported span exporters.
+// The stdout and file exporters write the spans as JSON, they are intended for testing without a collector.
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+)
+
+var ErrExporterUnknown = errors.New("unknown tracing exporter")
+
+// Provider records the spans of the service and sends them to the configured exporter.
+type Provider struct {
+	provider *sdktrace.TracerProvider
+	file     io.Closer
+}
+
+// NewProvider returns a Provider of the configured exporter, and installs it as the global OpenTelemetry tracer provider.
+// The W3C trace context and baggage propagators are installed whatever the exporter, so the traces of the callers
+// go on through the service even when it records no span.
+func NewProvider(cfg config.Tracing, app config.Application) (Provider, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var (
+		exporter sdktrace.SpanExporter
+		file     *os.File
+		err      error
+	)
+	switch cfg.Exporter() {
+	case ExporterNone:
+		return Provider{}, nil
+	case ExporterOTLP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.OTLPEndpoint())}
+		if cfg.OTLPInsecure() {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(context.Background(), opts...)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterFile:
+		file, err = os.OpenFile(cfg.FilePath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return Provider{}, fmt.Errorf("failed opening traces file: %w", err)
+		}
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(file))
+	default:
+		return Provider{}, fmt.Errorf("tracing exporter %q: %w", cfg.Exporter(), ErrExporterUnknown)
+	}
+	if err != nil {
+		if file != nil {
+			file.Close()
+		}
+		return Provider{}, err
+	}
+
+	res := resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(app.Name()),
+		semconv.ServiceVersion(app.Version()),
+	)
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio()))),
+	)
+	otel.SetTracerProvider(tp)
+
+	p := Provider{provider: tp}
+	if file != nil {
+		p.file = file
+	}
+	return p, nil
+}
+
+// Shutdown sends the pending spans to the exporter and stops it, it does nothing when the tracing is disabled.
+func (p Provider) Shutdown(ctx context.Context) error {
+	if p.provider == nil {
+		return nil
+	}
+	err := p.provider.Shutdown(ctx)
+	if p.file != nil {
+		if closeErr := p.file.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
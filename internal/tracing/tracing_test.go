@@ -0,0 +1,51 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+)
+
+func TestNewProvider(t *testing.T) {
+	t.Run("None", func(t *testing.T) {
+		t.Setenv("CAMGO_TRACING_EXPORTER", ExporterNone)
+		cfg := config.NewConfig()
+		p, err := NewProvider(cfg.Tracing, cfg.Application)
+		require.NoError(t, err)
+		assert.NoError(t, p.Shutdown(context.Background()))
+		assert.ElementsMatch(t, []string{"traceparent", "tracestate", "baggage"}, otel.GetTextMapPropagator().Fields(),
+			"the W3C headers are propagated even without exporter")
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		t.Setenv("CAMGO_TRACING_EXPORTER", "jaeger")
+		cfg := config.NewConfig()
+		_, err := NewProvider(cfg.Tracing, cfg.Application)
+		assert.ErrorIs(t, err, ErrExporterUnknown)
+	})
+
+	t.Run("File", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "traces.json")
+		t.Setenv("CAMGO_TRACING_EXPORTER", ExporterFile)
+		t.Setenv("CAMGO_TRACING_FILE_PATH", path)
+		cfg := config.NewConfig()
+		p, err := NewProvider(cfg.Tracing, cfg.Application)
+		require.NoError(t, err)
+
+		_, span := otel.Tracer("test").Start(context.Background(), "GET /api/v0/users/{id}")
+		span.End()
+		require.NoError(t, p.Shutdown(context.Background()), "the pending spans are flushed")
+
+		traces, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Contains(t, string(traces), `"Name":"GET /api/v0/users/{id}"`)
+		assert.Contains(t, string(traces), `"Value":"camgo"`, "the spans carry the service name")
+	})
+}
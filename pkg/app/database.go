@@ -0,0 +1,171 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/config"
+	"github.com/wizeline/CA-Microservices-Go/internal/db"
+	"github.com/wizeline/CA-Microservices-Go/internal/db/migration"
+	"github.com/wizeline/CA-Microservices-Go/internal/health"
+	"github.com/wizeline/CA-Microservices-Go/internal/logger"
+	"github.com/wizeline/CA-Microservices-Go/internal/repository"
+	"github.com/wizeline/CA-Microservices-Go/internal/service"
+)
+
+// repositories holds the storage of every entity for the configured database driver.
+type repositories struct {
+	users  service.UserRepo
+	tokens service.TokenRepo
+	roles  service.RoleRepo
+}
+
+// storage holds the database connection of the configured driver, its repositories and its readiness checks.
+// The connection is nil and there are no checks for the drivers keeping the data in the process.
+type storage struct {
+	conn   db.Conn
+	repos  repositories
+	checks []health.Check
+}
+
+// dbDriver holds the connector, the migrations dialect and the repositories of a database driver.
+// The connector is nil for the drivers keeping the data in the process, they get a nil connection.
+type dbDriver struct {
+	connect      func(cfg config.Database) (db.Conn, error)
+	dialect      migration.Dialect
+	repositories func(conn db.Conn) repositories
+}
+
+// dbDrivers is the registry of the supported database drivers, selected by the database.driver configuration.
+var dbDrivers = map[string]dbDriver{
+	db.DriverPostgres: {
+		connect: func(cfg config.Database) (db.Conn, error) {
+			return db.NewPgConn(cfg.Postgres)
+		},
+		dialect: migration.Postgres,
+		repositories: func(conn db.Conn) repositories {
+			return repositories{
+				users:  repository.NewUserRepositoryPg(conn.DB()),
+				tokens: repository.NewTokenRepositoryPg(conn.DB()),
+				roles:  repository.NewRoleRepositoryPg(conn.DB()),
+			}
+		},
+	},
+	db.DriverMySQL: {
+		connect: func(cfg config.Database) (db.Conn, error) {
+			return db.NewMySQLConn(cfg.MySQL)
+		},
+		dialect: migration.MySQL,
+		repositories: func(conn db.Conn) repositories {
+			return repositories{
+				users:  repository.NewUserRepositoryMySQL(conn.DB()),
+				tokens: repository.NewTokenRepositoryMySQL(conn.DB()),
+				roles:  repository.NewRoleRepositoryMySQL(conn.DB()),
+			}
+		},
+	},
+	db.DriverSQLite: {
+		connect: func(cfg config.Database) (db.Conn, error) {
+			return db.NewSQLiteConn(cfg.SQLite)
+		},
+		dialect: migration.SQLite,
+		repositories: func(conn db.Conn) repositories {
+			return repositories{
+				users:  repository.NewUserRepositorySQLite(conn.DB()),
+				tokens: repository.NewTokenRepositorySQLite(conn.DB()),
+				roles:  repository.NewRoleRepositorySQLite(conn.DB()),
+			}
+		},
+	},
+	db.DriverMemory: {
+		repositories: func(db.Conn) repositories {
+			return repositories{
+				users:  repository.NewUserRepositoryMemory(),
+				tokens: repository.NewTokenRepositoryMemory(),
+				roles:  repository.NewRoleRepositoryMemory(),
+			}
+		},
+	},
+}
+
+// lookupDBDriver returns the registered driver of the configuration.
+func lookupDBDriver(cfg config.Database) (dbDriver, error) {
+	driver, ok := dbDrivers[cfg.Driver()]
+	if !ok {
+		return dbDriver{}, fmt.Errorf("database driver %q: %w", cfg.Driver(), db.ErrDriverUnknown)
+	}
+	return driver, nil
+}
+
+// ConnectDB opens the SQL database of the configured driver, and returns the dialect of its migrations.
+// The drivers keeping the data in the process are rejected with db.ErrDriverNotSQL.
+func ConnectDB(cfg config.Database) (db.Conn, migration.Dialect, error) {
+	driver, err := lookupDBDriver(cfg)
+	if err != nil {
+		return nil, migration.Dialect{}, err
+	}
+	if driver.connect == nil {
+		return nil, migration.Dialect{}, fmt.Errorf("database driver %q: %w", cfg.Driver(), db.ErrDriverNotSQL)
+	}
+	conn, err := driver.connect(cfg)
+	if err != nil {
+		return nil, migration.Dialect{}, err
+	}
+	return conn, driver.dialect, nil
+}
+
+// NewUserRepo returns the user repository of the configured driver stored in the connected database.
+func NewUserRepo(cfg config.Database, conn db.Conn) (service.UserRepo, error) {
+	driver, err := lookupDBDriver(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return driver.repositories(conn).users, nil
+}
+
+// NewRoleRepo returns the role repository of the configured driver stored in the connected database.
+func NewRoleRepo(cfg config.Database, conn db.Conn) (service.RoleRepo, error) {
+	driver, err := lookupDBDriver(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return driver.repositories(conn).roles, nil
+}
+
+// provideRepositories connects to the database of the configured driver and applies the pending migrations.
+// The database is ready while it answers the pings and its schema is up to date, both checks are critical.
+func provideRepositories(cfg config.Database, l logger.ZeroLog) (storage, error) {
+	driver, err := lookupDBDriver(cfg)
+	if err != nil {
+		return storage{}, err
+	}
+	if driver.connect == nil {
+		l.Log().Warn().Str("driver", cfg.Driver()).Msg("using an in-process database, the data is lost on shutdown")
+		return storage{repos: driver.repositories(nil)}, nil
+	}
+
+	dbConn, err := driver.connect(cfg)
+	if err != nil {
+		return storage{}, err
+	}
+	l.Log().Debug().Str("driver", cfg.Driver()).Msg("database connection ready")
+
+	migrations, err := migration.Load(driver.dialect, cfg.MigrationsDir())
+	if err != nil {
+		dbConn.Close()
+		return storage{}, err
+	}
+	migrator := migration.NewMigrator(dbConn.DB(), driver.dialect, migrations, cfg.MigrationsLockTimeout(), l)
+	if err := migrator.Up(0); err != nil {
+		dbConn.Close()
+		return storage{}, err
+	}
+
+	return storage{
+		conn:  dbConn,
+		repos: driver.repositories(dbConn),
+		checks: []health.Check{
+			{Name: "database", Checker: health.CheckerFunc(dbConn.DB().PingContext), Critical: true},
+			{Name: "migrations", Checker: migrator, Critical: true},
+		},
+	}, nil
+}
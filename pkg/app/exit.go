@@ -0,0 +1,54 @@
+package app
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/db"
+	"github.com/wizeline/CA-Microservices-Go/internal/db/migration"
+)
+
+// Exit codes of the startup failures, they follow the sysexits.h conventions so the orchestrators
+// can tell a database outage, worth a restart, from a broken migration needing a fix.
+const (
+	ExitFailure     = 1
+	ExitDataErr     = 65 // a migration file is invalid, modified or fails to apply
+	ExitUnavailable = 69 // the database is unreachable
+	ExitTempFail    = 75 // another instance holds the migrations lock
+)
+
+// ExitCode returns the process exit code of the startup error.
+func ExitCode(err error) int {
+	var (
+		lockErr     *migration.LockTimeoutErr
+		netErr      net.Error
+		migErr      *migration.Err
+		applyErr    *migration.ApplyErr
+		checksumErr *migration.ChecksumMismatchErr
+		readErr     *migration.ReadFileErr
+	)
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &lockErr):
+		return ExitTempFail
+	case errors.Is(err, db.ErrUnreachable),
+		errors.Is(err, driver.ErrBadConn),
+		errors.Is(err, sql.ErrConnDone),
+		errors.As(err, &netErr),
+		errors.As(err, &migErr):
+		return ExitUnavailable
+	case errors.As(err, &applyErr),
+		errors.As(err, &checksumErr),
+		errors.As(err, &readErr),
+		errors.Is(err, migration.ErrVersionDuplicated),
+		errors.Is(err, migration.ErrMigrationMissing),
+		errors.Is(err, migration.ErrDownMissing),
+		errors.Is(err, migration.ErrUpMissing):
+		return ExitDataErr
+	default:
+		return ExitFailure
+	}
+}
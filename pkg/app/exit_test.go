@@ -0,0 +1,72 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/db"
+	"github.com/wizeline/CA-Microservices-Go/internal/db/migration"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		exp  int
+	}{
+		{
+			name: "No error",
+			exp:  0,
+		},
+		{
+			name: "Database unreachable",
+			err:  fmt.Errorf("%w: %w", db.ErrUnreachable, errors.New("connection refused")),
+			exp:  ExitUnavailable,
+		},
+		{
+			name: "Connection lost while applying a migration",
+			err:  &migration.ApplyErr{Version: 1, Name: "create_users_table", Err: &net.OpError{Op: "read", Err: errors.New("reset")}},
+			exp:  ExitUnavailable,
+		},
+		{
+			name: "Schema migrations table unreadable",
+			err:  &migration.Err{Err: errors.New("permission denied")},
+			exp:  ExitUnavailable,
+		},
+		{
+			name: "Migration fails",
+			err:  &migration.ApplyErr{Version: 1, Name: "create_users_table", Line: 3, Err: errors.New("syntax error")},
+			exp:  ExitDataErr,
+		},
+		{
+			name: "Modified migration",
+			err:  &migration.ChecksumMismatchErr{Version: 1, Name: "create_users_table"},
+			exp:  ExitDataErr,
+		},
+		{
+			name: "Missing migration file",
+			err:  fmt.Errorf("migration 2 create_roles_tables: %w", migration.ErrMigrationMissing),
+			exp:  ExitDataErr,
+		},
+		{
+			name: "Lock timeout",
+			err:  &migration.LockTimeoutErr{Timeout: time.Minute, Err: errors.New("canceling statement")},
+			exp:  ExitTempFail,
+		},
+		{
+			name: "Other error",
+			err:  errors.New("invalid jwt secret"),
+			exp:  ExitFailure,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.exp, ExitCode(tt.err))
+		})
+	}
+}
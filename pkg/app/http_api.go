@@ -2,28 +2,45 @@ package app
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
 	"errors"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/wizeline/CA-Microservices-Go/api"
 	"github.com/wizeline/CA-Microservices-Go/internal/config"
 	"github.com/wizeline/CA-Microservices-Go/internal/controller"
 	"github.com/wizeline/CA-Microservices-Go/internal/db"
-	"github.com/wizeline/CA-Microservices-Go/internal/db/migration"
+	"github.com/wizeline/CA-Microservices-Go/internal/db/seed"
+	"github.com/wizeline/CA-Microservices-Go/internal/entity"
+	"github.com/wizeline/CA-Microservices-Go/internal/health"
 	"github.com/wizeline/CA-Microservices-Go/internal/logger"
-	"github.com/wizeline/CA-Microservices-Go/internal/repository"
+	"github.com/wizeline/CA-Microservices-Go/internal/metrics"
+	"github.com/wizeline/CA-Microservices-Go/internal/middleware"
 	"github.com/wizeline/CA-Microservices-Go/internal/router"
 	"github.com/wizeline/CA-Microservices-Go/internal/service"
+	"github.com/wizeline/CA-Microservices-Go/internal/tracing"
 )
 
+// tracingFlushTimeout bounds the export of the pending spans on shutdown.
+const tracingFlushTimeout = 5 * time.Second
+
+// ErrAdminIncomplete is returned when the initial administrator is configured without its email or password.
+var ErrAdminIncomplete = errors.New("initial admin requires a username, an email and a password")
+
 type ApiHTTP struct {
-	cfg    config.HTTPServer
-	dbConn *db.PgConn
-	server *http.Server
-	logger logger.ZeroLog
+	cfg       config.HTTPServer
+	dbConn    db.Conn
+	readiness health.Readiness
+	server    *http.Server
+	admin     *http.Server
+	tracing   tracing.Provider
+	logger    logger.ZeroLog
 }
 
 func provideSwaggerHTTP(cfg config.Application, l logger.ZeroLog) controller.SwaggerHTTP {
@@ -31,39 +48,126 @@ func provideSwaggerHTTP(cfg config.Application, l logger.ZeroLog) controller.Swa
 	return controller.NewSwaggerHTTP()
 }
 
-func NewApiHTTP(cfg config.Config, l logger.ZeroLog) (ApiHTTP, error) {
+// provideAdminServer returns the server exposing the operational endpoints on the admin port.
+func provideAdminServer(cfg config.HTTPAdmin, prom metrics.Prometheus) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", prom.Handler())
+	return &http.Server{
+		Handler:      mux,
+		Addr:         cfg.Address(),
+		WriteTimeout: 15 * time.Second,
+		ReadTimeout:  15 * time.Second,
+		IdleTimeout:  time.Second * 60,
+	}
+}
+
+// provideCursorSecret returns the configured key of the pagination cursors, or a random one when it is unset.
+func provideCursorSecret(cfg config.Pagination, l logger.ZeroLog) (string, error) {
+	if cfg.CursorSecret() != "" {
+		return cfg.CursorSecret(), nil
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	l.Log().Warn().Msg("pagination cursor secret not configured, using a random one: the cursors are invalidated on restart and not shared between replicas")
+	return base64.RawStdEncoding.EncodeToString(key), nil
+}
 
-	// Initialize database connection
-	dbConn, err := db.NewPgConn(cfg.Database.Postgres)
+// provideAdmin creates the initial administrator when it is configured, so the authenticated routes are reachable
+// without seeding the database. An existing account keeps its password, it is only made an active admin again.
+func provideAdmin(ctx context.Context, cfg config.Admin, users seed.UserService, l logger.ZeroLog) error {
+	if cfg.Username() == "" {
+		return nil
+	}
+	if cfg.Email() == "" || cfg.Passwd() == "" {
+		return ErrAdminIncomplete
+	}
+	admin := seed.UserFixture{
+		FirstName: "Initial",
+		LastName:  "Admin",
+		Email:     cfg.Email(),
+		BirthDay:  "1970-01-01",
+		Username:  cfg.Username(),
+		Passwd:    cfg.Passwd(),
+		Role:      entity.RoleAdmin,
+		Active:    true,
+	}
+	if _, err := seed.NewSeeder(users, l).Apply(ctx, seed.Fixtures{Users: []seed.UserFixture{admin}}); err != nil {
+		return fmt.Errorf("initial admin: %w", err)
+	}
+	return nil
+}
+
+func NewApiHTTP(cfg config.Config, l logger.ZeroLog) (_ ApiHTTP, err error) {
+	prom := metrics.NewPrometheus()
+
+	cursorSecret, err := provideCursorSecret(cfg.Pagination, l)
 	if err != nil {
 		return ApiHTTP{}, err
 	}
-	l.Log().Debug().Msg("database connection ready")
 
-	// Run Migrations
-	err = migration.Run(dbConn.DB(), []migration.Migration{
-		migration.CreateUsersTable,
-	}, l)
+	// Tracing, installed first so every span is recorded by the configured exporter
+	tp, err := tracing.NewProvider(cfg.Tracing, cfg.Application)
+	if err != nil {
+		return ApiHTTP{}, err
+	}
+	l.Log().Debug().Str("exporter", cfg.Tracing.Exporter()).Msg("tracing ready")
+	defer func() {
+		if err != nil {
+			flushTracing(tp, l)
+		}
+	}()
+
+	// Initialize the repositories of the database driver
+	store, err := provideRepositories(cfg.Database, l)
+	if err != nil {
+		return ApiHTTP{}, err
+	}
+	defer func() {
+		if err != nil && store.conn != nil {
+			store.conn.Close()
+		}
+	}()
+	repos := store.repos
+	readiness := health.NewReadiness(cfg.HTTPServer.ReadinessTimeout(), store.checks...)
+	if store.conn != nil {
+		if err := prom.RegisterDB(cfg.Database.Driver(), store.conn.DB()); err != nil {
+			return ApiHTTP{}, err
+		}
+	}
+
+	// Authentication
+	jwtAuth, err := middleware.NewJWT(cfg.Auth.JWT)
 	if err != nil {
 		return ApiHTTP{}, err
 	}
 
 	// User dependencies
-	userRepo := repository.NewUserRepositoryPg(dbConn.DB())
-	userSvc := service.NewUserService(userRepo)
+	userSvc := service.NewUserService(repos.users, repos.roles, cursorSecret).WithMetrics(prom)
+	tokenSvc := service.NewTokenService(repos.tokens, cfg.Auth.RefreshToken.TTL())
+	if err := provideAdmin(context.Background(), cfg.Auth.Admin, userSvc, l); err != nil {
+		return ApiHTTP{}, err
+	}
+
+	// Authorization
+	roleSvc := service.NewRoleService(repos.roles)
+	rbac := middleware.NewRBAC(roleSvc, userSvc)
 
 	// Router
-	r := router.NewChi(cfg.Application, l)
+	r := router.NewChi(cfg.Application, cfg.HTTPServer, prom, l)
 	r.Add(
 		provideSwaggerHTTP(cfg.Application, l),
-		controller.NewHealthCheckHTTP(),
-		controller.NewUserHTTP(userSvc),
+		controller.NewHealthCheckHTTP(readiness),
+		controller.NewUserHTTP(userSvc, tokenSvc, jwtAuth, rbac),
+		controller.NewRoleHTTP(roleSvc, jwtAuth, rbac),
 	)
 	r.RegisterRoutes()
 
 	return ApiHTTP{
-		cfg:    cfg.HTTPServer,
-		dbConn: dbConn,
+		cfg:       cfg.HTTPServer,
+		dbConn:    store.conn,
+		readiness: readiness,
 		server: &http.Server{
 			Handler:      r.Router(),
 			Addr:         cfg.HTTPServer.Address(),
@@ -71,13 +175,15 @@ func NewApiHTTP(cfg config.Config, l logger.ZeroLog) (ApiHTTP, error) {
 			ReadTimeout:  15 * time.Second,
 			IdleTimeout:  time.Second * 60,
 		},
-		logger: l,
+		admin:   provideAdminServer(cfg.HTTPAdmin, prom),
+		tracing: tp,
+		logger:  l,
 	}, nil
 
 }
 
 // Start runs the http API and quits doing a grateful shutdown.
-// To stop the server you must send a syscall.SIGINT signal usually through `CTRL+C`.
+// To stop the server you must send a syscall.SIGINT signal, usually through `CTRL+C`, or the syscall.SIGTERM sent by the orchestrators.
 func (h ApiHTTP) Start() {
 	go func() {
 		h.logger.Log().Info().Msgf("running http server on %v", h.cfg.Address())
@@ -86,25 +192,57 @@ func (h ApiHTTP) Start() {
 			h.logger.Log().Fatal().Err(err).Msg("http server startup failed")
 		}
 	}()
+	go func() {
+		h.logger.Log().Info().Msgf("running http admin server on %v", h.admin.Addr)
+		err := h.admin.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			h.logger.Log().Fatal().Err(err).Msg("http admin server startup failed")
+		}
+	}()
 
 	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 	<-quit
 }
 
 // Shutdown performs tasks of safely shutting down processes and closing connections.
+// The node reports itself not ready first, and keeps serving during the drain delay so the load balancers stop routing requests to it.
+// The shutdown timeout starts once the drain delay is over.
 func (h ApiHTTP) Shutdown() {
+	h.readiness.Shutdown()
+	if h.cfg.DrainDelay() > 0 {
+		h.logger.Log().Info().Dur("delay", h.cfg.DrainDelay()).Msg("draining http traffic")
+		<-time.After(h.cfg.DrainDelay())
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), h.cfg.ShutdownTimeout())
 	defer cancel()
 
 	if err := h.server.Shutdown(ctx); err != nil {
 		h.logger.Log().Error().Err(err).Msg("http server graceful shutdown failed")
 	}
+	// The admin server is stopped last, so the metrics are scraped while the traffic drains.
+	if err := h.admin.Shutdown(ctx); err != nil {
+		h.logger.Log().Error().Err(err).Msg("http admin server graceful shutdown failed")
+	}
 
-	if err := h.dbConn.Close(); err != nil {
-		h.logger.Log().Err(err).Msg("failed closing database connection")
+	if h.dbConn != nil {
+		if err := h.dbConn.Close(); err != nil {
+			h.logger.Log().Err(err).Msg("failed closing database connection")
+		}
 	}
 
+	flushTracing(h.tracing, h.logger)
+
 	h.logger.Log().Info().Msg("http api shutdown gracefully")
-	os.Exit(0)
+}
+
+// flushTracing exports the pending spans and stops the tracing provider, it gets its own timeout
+// so a slow collector is not given the time left by the servers shutdown.
+func flushTracing(tp tracing.Provider, l logger.ZeroLog) {
+	ctx, cancel := context.WithTimeout(context.Background(), tracingFlushTimeout)
+	defer cancel()
+	if err := tp.Shutdown(ctx); err != nil {
+		l.Log().Err(err).Msg("failed flushing the pending spans")
+	}
 }
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wizeline/CA-Microservices-Go/internal/config"
+	"github.com/wizeline/CA-Microservices-Go/internal/logger"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvideCursorSecret_unset(t *testing.T) {
+	l := logger.NewZeroLogFrom(zerolog.Nop())
+
+	first, err := provideCursorSecret(config.Pagination{}, l)
+	require.NoError(t, err)
+	second, err := provideCursorSecret(config.Pagination{}, l)
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, first)
+	assert.NotEqual(t, first, second)
+}
+
+func TestNewApiHTTP_adminIncomplete(t *testing.T) {
+	t.Setenv("CAMGO_DATABASE_DRIVER", "memory")
+	t.Setenv("CAMGO_AUTH_JWT_SECRET", "camgo-app-test-secret-0123456789abcdef")
+	t.Setenv("CAMGO_AUTH_ADMIN_USERNAME", "root")
+
+	_, err := NewApiHTTP(config.NewConfig(), logger.NewZeroLogFrom(zerolog.Nop()))
+	assert.ErrorIs(t, err, ErrAdminIncomplete)
+}
+
+// TestApiHTTP_registration runs the registration of a user end to end on the memory driver:
+// the registered user is inactive until the initial admin activates it.
+func TestApiHTTP_registration(t *testing.T) {
+	t.Setenv("CAMGO_DATABASE_DRIVER", "memory")
+	t.Setenv("CAMGO_AUTH_JWT_SECRET", "camgo-app-test-secret-0123456789abcdef")
+	t.Setenv("CAMGO_AUTH_ADMIN_USERNAME", "root")
+	t.Setenv("CAMGO_AUTH_ADMIN_EMAIL", "root@camgo.dev")
+	t.Setenv("CAMGO_AUTH_ADMIN_PASSWD", "r00tp4s5")
+
+	api, err := NewApiHTTP(config.NewConfig(), logger.NewZeroLogFrom(zerolog.Nop()))
+	require.NoError(t, err)
+	srv := httptest.NewServer(api.server.Handler)
+	defer srv.Close()
+
+	call := func(method, path, token, payload string, out any) int {
+		t.Helper()
+		req, err := http.NewRequest(method, srv.URL+"/api/v0"+path, strings.NewReader(payload))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		res, err := srv.Client().Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		if out != nil {
+			require.NoError(t, json.NewDecoder(res.Body).Decode(out))
+		}
+		return res.StatusCode
+	}
+	login := func(username, passwd string) (int, string) {
+		t.Helper()
+		var resp struct {
+			AccessToken string `json:"access_token"`
+		}
+		code := call(http.MethodPost, "/login", "", fmt.Sprintf(`{"username": %q, "password": %q}`, username, passwd), &resp)
+		return code, resp.AccessToken
+	}
+
+	code := call(http.MethodPost, "/users", "", `{"first_name": "Lisa", "last_name": "Field", "email": "lisa@field.com", "birthday": "1990-01-02", "username": "lisa", "password": "lisap4s5"}`, nil)
+	require.Equal(t, http.StatusCreated, code)
+
+	code, _ = login("lisa", "lisap4s5")
+	assert.Equal(t, http.StatusUnauthorized, code, "the registered users are inactive")
+
+	code, adminToken := login("root", "r00tp4s5")
+	require.Equal(t, http.StatusOK, code, "the initial admin can log in")
+
+	var page struct {
+		Users []struct {
+			ID string `json:"id"`
+		} `json:"users"`
+	}
+	code = call(http.MethodGet, "/users/filter?filter=Username&value=lisa", adminToken, "", &page)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, page.Users, 1)
+
+	code = call(http.MethodPut, "/users/"+page.Users[0].ID+"/activation", adminToken, "", nil)
+	require.Equal(t, http.StatusOK, code)
+
+	code, token := login("lisa", "lisap4s5")
+	assert.Equal(t, http.StatusOK, code, "the activated user can log in")
+	assert.NotEmpty(t, token)
+}